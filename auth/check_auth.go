@@ -2,19 +2,18 @@ package auth
 
 import (
 	"errors"
-	"os"
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
-func ExtractUserIDFromToken(tokenString string) (uint, error) {
+func (j JWT) ExtractUserIDFromToken(tokenString string) (uint, error) {
 	tokenString = strings.TrimSpace(strings.TrimPrefix(tokenString, "Bearer "))
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid token signing method")
 		}
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+		return j.Secret(), nil
 	})
 	if err != nil {
 		return 0, err
@@ -25,6 +24,9 @@ func ExtractUserIDFromToken(tokenString string) (uint, error) {
 	}
 
 	claims := token.Claims.(jwt.MapClaims)
+	if _, ok := claims["purpose"]; ok {
+		return 0, errors.New("token cannot be used for authentication")
+	}
 	userIDFloat, ok := claims["user_id"].(float64)
 	if !ok {
 		return 0, errors.New("user_id not found in token claims")
@@ -0,0 +1,116 @@
+package auth
+
+import "backend-pedika-fiber/models"
+
+const (
+	PermProfile               = "profile"
+	PermEmergencyContactRead  = "emergency_contact:read"
+	PermEmergencyContactWrite = "emergency_contact:write"
+	PermLaporanRead           = "laporan:read"
+	PermLaporanWrite          = "laporan:write"
+	PermViolenceCategoryRead  = "violence_category:read"
+	PermViolenceCategoryWrite = "violence_category:write"
+	PermContentRead           = "content:read"
+	PermContentWrite          = "content:write"
+	PermEventRead             = "event:read"
+	PermEventWrite            = "event:write"
+	PermJanjiTemuRead         = "janjitemu:read"
+	PermJanjiTemuWrite        = "janjitemu:write"
+	// Catatan konsultasi bersifat rahasia, sehingga dipisah dari izin janji
+	// temu yang juga dimiliki supervisor.
+	PermCatatanKonsultasiRead  = "catatan_konsultasi:read"
+	PermCatatanKonsultasiWrite = "catatan_konsultasi:write"
+	PermPenilaianRead          = "penilaian:read"
+	PermUserManage             = "user:manage"
+	PermRoleManage             = "role:manage"
+)
+
+var allPermissions = []string{
+	PermProfile,
+	PermEmergencyContactRead,
+	PermEmergencyContactWrite,
+	PermLaporanRead,
+	PermLaporanWrite,
+	PermViolenceCategoryRead,
+	PermViolenceCategoryWrite,
+	PermContentRead,
+	PermContentWrite,
+	PermEventRead,
+	PermEventWrite,
+	PermJanjiTemuRead,
+	PermJanjiTemuWrite,
+	PermCatatanKonsultasiRead,
+	PermCatatanKonsultasiWrite,
+	PermPenilaianRead,
+	PermUserManage,
+	PermRoleManage,
+}
+
+// RolePermissions adalah matriks izin untuk setiap role staff.
+// Role masyarakat tidak ada di sini karena tidak boleh mengakses /api/admin.
+var RolePermissions = map[string][]string{
+	models.RoleSuperAdmin: allPermissions,
+	models.RoleAdmin:      without(allPermissions, PermRoleManage),
+	models.RoleKonselor: {
+		PermProfile,
+		PermEmergencyContactRead,
+		PermLaporanRead,
+		PermLaporanWrite,
+		PermViolenceCategoryRead,
+		PermContentRead,
+		PermEventRead,
+		PermJanjiTemuRead,
+		PermJanjiTemuWrite,
+		PermCatatanKonsultasiRead,
+		PermCatatanKonsultasiWrite,
+	},
+	models.RoleContentEditor: {
+		PermProfile,
+		PermEmergencyContactRead,
+		PermViolenceCategoryRead,
+		PermViolenceCategoryWrite,
+		PermContentRead,
+		PermContentWrite,
+		PermEventRead,
+		PermEventWrite,
+	},
+	models.RoleSupervisor: {
+		PermProfile,
+		PermEmergencyContactRead,
+		PermLaporanRead,
+		PermViolenceCategoryRead,
+		PermContentRead,
+		PermEventRead,
+		PermJanjiTemuRead,
+		PermCatatanKonsultasiRead,
+		PermPenilaianRead,
+	},
+}
+
+func IsStaffRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+func IsValidRole(role string) bool {
+	return role == models.RoleMasyarakat || IsStaffRole(role)
+}
+
+func HasPermission(role, permission string) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func without(permissions []string, excluded string) []string {
+	var result []string
+	for _, p := range permissions {
+		if p != excluded {
+			result = append(result, p)
+		}
+	}
+	return result
+}
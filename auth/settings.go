@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"backend-pedika-fiber/config"
+	"time"
+)
+
+// JWT menyimpan pengaturan token login. Dibuat sekali lewat NewJWT saat
+// aplikasi start lalu diberikan ke handler dan middleware yang membutuhkan.
+type JWT struct {
+	cfg config.JWTConfig
+}
+
+func NewJWT(cfg config.JWTConfig) JWT {
+	return JWT{cfg: cfg}
+}
+
+func (j JWT) Secret() []byte {
+	return []byte(j.cfg.SecretKey)
+}
+
+// TokenTTL adalah masa berlaku token login.
+func (j JWT) TokenTTL() time.Duration {
+	return j.cfg.TTL
+}
@@ -0,0 +1,389 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Config berisi seluruh konfigurasi aplikasi. Dibaca sekali saat start lewat
+// Load, lalu diteruskan ke komponen yang membutuhkan (database, auth,
+// storage, mail, dan seterusnya).
+type Config struct {
+	// Env "development" melonggarkan beberapa pengaman, misalnya mengizinkan
+	// driver email dan SMS yang hanya menulis ke log. Default production.
+	Env         string
+	Port        string
+	FrontendURL string
+	Proxy       ProxyConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
+	Storage     StorageConfig
+	Mail        MailConfig
+	SMS         SMSConfig
+	RateLimit   RateLimitConfig
+	Pengingat   PengingatConfig
+	Pertemuan   PertemuanConfig
+}
+
+// ProxyConfig menentukan dari mana IP client dibaca. Header hanya dipercaya
+// jika request datang langsung dari salah satu TrustedProxies (IP atau CIDR
+// load balancer). Pakai header yang selalu ditimpa load balancer, misalnya
+// X-Real-IP, karena nilai paling kiri X-Forwarded-For bisa diisi client.
+type ProxyConfig struct {
+	Header         string
+	TrustedProxies []string
+}
+
+type DatabaseConfig struct {
+	// Driver "mysql" untuk production, "sqlite" untuk development lokal dan
+	// test. Untuk sqlite, Name berisi path file database atau ":memory:".
+	Driver       string
+	Username     string
+	Password     string
+	Address      string
+	Name         string
+	MaxOpenConns int
+	MaxIdleConns int
+}
+
+type JWTConfig struct {
+	SecretKey         string
+	TTL               time.Duration
+	TwoFactorEnforced bool
+}
+
+// StorageConfig berisi kredensial Cloudinary untuk upload file.
+type StorageConfig struct {
+	CloudName string
+	APIKey    string
+	APISecret string
+}
+
+type MailConfig struct {
+	// Driver "smtp" untuk production, "log" hanya untuk development.
+	Driver   string
+	Sender   string
+	Password string
+	SMTPHost string
+	SMTPPort string
+}
+
+type SMSConfig struct {
+	// Driver "twilio" untuk production, "log" hanya untuk development.
+	Driver     string
+	AccountSID string
+	AuthToken  string
+	From       string
+}
+
+// RateLimitConfig dibaca dari env dengan format "<jumlah>/<durasi>",
+// misalnya RATE_LIMIT_AUTH="20/1m".
+type RateLimitConfig struct {
+	// Store "memory" untuk satu instance atau "mysql" untuk beberapa replica.
+	Store  string
+	Auth   Limit
+	Public Limit
+	Write  Limit
+	Upload Limit
+}
+
+// PengingatConfig mengatur scheduler pengingat janji temu dan event.
+// Channels dibaca dari PENGINGAT_CHANNELS, misalnya "email,sms".
+type PengingatConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	Channels []string
+}
+
+// PertemuanConfig mengatur tautan rapat untuk konsultasi video. Template
+// wajib berisi {kode}, misalnya "https://meet.jit.si/pedika-{kode}".
+type PertemuanConfig struct {
+	Template string
+}
+
+type Limit struct {
+	Max    int
+	Window time.Duration
+}
+
+// Load membaca file env (default .env, bisa diganti lewat ENV_FILE) jika ada,
+// lalu environment variable. Environment variable yang sudah di-set tidak
+// ditimpa oleh isi file, sehingga deployment container cukup memakai env saja.
+func Load() (*Config, error) {
+	envFile := os.Getenv("ENV_FILE")
+	if envFile == "" {
+		envFile = ".env"
+	}
+	if err := godotenv.Load(envFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("config: failed to read %s: %w", envFile, err)
+	}
+	return FromEnv()
+}
+
+// FromEnv membangun Config dari environment variable saja, tanpa membaca file.
+func FromEnv() (*Config, error) {
+	r := &reader{}
+	cfg := &Config{
+		Env:         r.str("APP_ENV", "production"),
+		Port:        r.str("PORT", "8080"),
+		FrontendURL: strings.TrimRight(r.str("FRONTEND_URL", "http://localhost:3000"), "/"),
+		Proxy: ProxyConfig{
+			Header:         r.str("PROXY_HEADER", ""),
+			TrustedProxies: r.list("TRUSTED_PROXIES"),
+		},
+		Database: DatabaseConfig{
+			Driver:       r.str("DB_DRIVER", "mysql"),
+			Username:     r.str("DB_USERNAME", ""),
+			Password:     r.str("DB_PASSWORD", ""),
+			Address:      r.str("DB_URL", "127.0.0.1:3306"),
+			Name:         r.str("DB_DATABASE", ""),
+			MaxOpenConns: r.integer("DB_MAX_OPEN_CONNS", 10),
+			MaxIdleConns: r.integer("DB_MAX_IDLE_CONNS", 5),
+		},
+		JWT: JWTConfig{
+			SecretKey:         r.str("JWT_SECRET_KEY", ""),
+			TTL:               r.duration("JWT_TTL", 10*time.Hour),
+			TwoFactorEnforced: r.boolean("TWO_FACTOR_ENFORCED", false),
+		},
+		Storage: StorageConfig{
+			CloudName: r.str("CLOUD_NAME", ""),
+			APIKey:    r.str("API_KEY", ""),
+			APISecret: r.str("API_SECRET", ""),
+		},
+		Mail: MailConfig{
+			Driver:   r.str("MAIL_DRIVER", ""),
+			Sender:   r.str("EMAIL_SENDER", ""),
+			Password: r.str("EMAIL_PASSWORD", ""),
+			SMTPHost: r.str("SMTP_HOST", ""),
+			SMTPPort: r.str("SMTP_PORT", "587"),
+		},
+		SMS: SMSConfig{
+			Driver:     r.str("SMS_DRIVER", ""),
+			AccountSID: r.str("TWILIO_ACCOUNT_SID", ""),
+			AuthToken:  r.str("TWILIO_AUTH_TOKEN", ""),
+			From:       r.str("TWILIO_FROM", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Store:  r.str("RATE_LIMIT_STORE", "memory"),
+			Auth:   r.limit("RATE_LIMIT_AUTH", Limit{Max: 20, Window: time.Minute}),
+			Public: r.limit("RATE_LIMIT_PUBLIC", Limit{Max: 120, Window: time.Minute}),
+			Write:  r.limit("RATE_LIMIT_WRITE", Limit{Max: 60, Window: time.Minute}),
+			Upload: r.limit("RATE_LIMIT_UPLOAD", Limit{Max: 20, Window: 10 * time.Minute}),
+		},
+		Pengingat: PengingatConfig{
+			Enabled:  r.boolean("PENGINGAT_ENABLED", true),
+			Interval: r.duration("PENGINGAT_INTERVAL", time.Minute),
+			Channels: strings.Split(r.str("PENGINGAT_CHANNELS", "email"), ","),
+		},
+		Pertemuan: PertemuanConfig{
+			Template: r.str("PERTEMUAN_TEMPLATE_TAUTAN", "https://meet.jit.si/pedika-{kode}"),
+		},
+	}
+
+	cfg.validate(r)
+	if len(r.errs) > 0 {
+		return nil, fmt.Errorf("config: invalid configuration: %w", errors.Join(r.errs...))
+	}
+	return cfg, nil
+}
+
+// IsDevelopment melaporkan apakah APP_ENV di-set ke development secara
+// eksplisit.
+func (cfg *Config) IsDevelopment() bool {
+	return cfg.Env == "development"
+}
+
+func (cfg *Config) validate(r *reader) {
+	switch cfg.Env {
+	case "development", "production":
+	default:
+		r.fail(fmt.Sprintf("APP_ENV must be development or production, got %q", cfg.Env))
+	}
+
+	if cfg.Proxy.Header != "" && len(cfg.Proxy.TrustedProxies) == 0 {
+		r.fail("TRUSTED_PROXIES is required when PROXY_HEADER is set")
+	}
+	for _, proxy := range cfg.Proxy.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			r.fail(fmt.Sprintf("TRUSTED_PROXIES must contain IP addresses or CIDR ranges, got %q", proxy))
+		}
+	}
+
+	switch cfg.Database.Driver {
+	case "mysql":
+		r.required("DB_USERNAME", cfg.Database.Username)
+	case "sqlite":
+	default:
+		r.fail(fmt.Sprintf("DB_DRIVER must be mysql or sqlite, got %q", cfg.Database.Driver))
+	}
+	r.required("DB_DATABASE", cfg.Database.Name)
+	r.required("JWT_SECRET_KEY", cfg.JWT.SecretKey)
+	if cfg.JWT.SecretKey != "" && len(cfg.JWT.SecretKey) < 16 {
+		r.fail("JWT_SECRET_KEY must be at least 16 characters")
+	}
+
+	r.required("CLOUD_NAME", cfg.Storage.CloudName)
+	r.required("API_KEY", cfg.Storage.APIKey)
+	r.required("API_SECRET", cfg.Storage.APISecret)
+
+	switch cfg.Mail.Driver {
+	case "smtp":
+		r.required("EMAIL_SENDER", cfg.Mail.Sender)
+		r.required("SMTP_HOST", cfg.Mail.SMTPHost)
+		r.required("SMTP_PORT", cfg.Mail.SMTPPort)
+	case "log":
+		if !cfg.IsDevelopment() {
+			r.fail("MAIL_DRIVER=log is only allowed when APP_ENV=development")
+		}
+	case "":
+		r.fail("MAIL_DRIVER is required")
+	default:
+		r.fail(fmt.Sprintf("MAIL_DRIVER must be smtp or log, got %q", cfg.Mail.Driver))
+	}
+
+	switch cfg.SMS.Driver {
+	case "twilio":
+		r.required("TWILIO_ACCOUNT_SID", cfg.SMS.AccountSID)
+		r.required("TWILIO_AUTH_TOKEN", cfg.SMS.AuthToken)
+		r.required("TWILIO_FROM", cfg.SMS.From)
+	case "log":
+		if !cfg.IsDevelopment() {
+			r.fail("SMS_DRIVER=log is only allowed when APP_ENV=development")
+		}
+	case "":
+		r.fail("SMS_DRIVER is required")
+	default:
+		r.fail(fmt.Sprintf("SMS_DRIVER must be twilio or log, got %q", cfg.SMS.Driver))
+	}
+
+	switch cfg.RateLimit.Store {
+	case "memory", "mysql":
+	default:
+		r.fail(fmt.Sprintf("RATE_LIMIT_STORE must be memory or mysql, got %q", cfg.RateLimit.Store))
+	}
+
+	if cfg.Pengingat.Interval <= 0 {
+		r.fail("PENGINGAT_INTERVAL must be a positive duration")
+	}
+	for _, channel := range cfg.Pengingat.Channels {
+		switch strings.TrimSpace(channel) {
+		case "email", "sms":
+		default:
+			r.fail(fmt.Sprintf("PENGINGAT_CHANNELS may only contain email and sms, got %q", channel))
+		}
+	}
+
+	if !strings.HasPrefix(cfg.Pertemuan.Template, "https://") || !strings.Contains(cfg.Pertemuan.Template, "{kode}") {
+		r.fail(fmt.Sprintf("PERTEMUAN_TEMPLATE_TAUTAN must be an https URL containing {kode}, got %q", cfg.Pertemuan.Template))
+	}
+}
+
+// ParseLimit mengurai batas rate limit dengan format "<jumlah>/<durasi>".
+func ParseLimit(value string) (int, time.Duration, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected format <max>/<window>, got %q", value)
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || max <= 0 {
+		return 0, 0, fmt.Errorf("max must be a positive integer, got %q", parts[0])
+	}
+	window, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || window <= 0 {
+		return 0, 0, fmt.Errorf("window must be a positive duration, got %q", parts[1])
+	}
+	return max, window, nil
+}
+
+// reader mengumpulkan semua kesalahan konfigurasi supaya bisa dilaporkan
+// sekaligus, bukan satu per satu setiap kali aplikasi gagal start.
+type reader struct {
+	errs []error
+}
+
+func (r *reader) fail(message string) {
+	r.errs = append(r.errs, errors.New(message))
+}
+
+func (r *reader) required(key, value string) {
+	if value == "" {
+		r.fail(key + " is required")
+	}
+}
+
+func (r *reader) str(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return strings.TrimSpace(value)
+	}
+	return fallback
+}
+
+// list membaca daftar yang dipisah koma. Nilai kosong menghasilkan nil.
+func (r *reader) list(key string) []string {
+	var items []string
+	for _, item := range strings.Split(r.str(key, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (r *reader) integer(key string, fallback int) int {
+	value := r.str(key, "")
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		r.fail(fmt.Sprintf("%s must be an integer, got %q", key, value))
+		return fallback
+	}
+	return n
+}
+
+func (r *reader) boolean(key string, fallback bool) bool {
+	value := r.str(key, "")
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.fail(fmt.Sprintf("%s must be true or false, got %q", key, value))
+		return fallback
+	}
+	return b
+}
+
+func (r *reader) duration(key string, fallback time.Duration) time.Duration {
+	value := r.str(key, "")
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		r.fail(fmt.Sprintf("%s must be a duration like 10h or 30m, got %q", key, value))
+		return fallback
+	}
+	return d
+}
+
+func (r *reader) limit(key string, fallback Limit) Limit {
+	value := r.str(key, "")
+	if value == "" {
+		return fallback
+	}
+	max, window, err := ParseLimit(value)
+	if err != nil {
+		r.fail(fmt.Sprintf("%s: %v", key, err))
+		return fallback
+	}
+	return Limit{Max: max, Window: window}
+}
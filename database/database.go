@@ -1,62 +1,86 @@
 package database
 
 import (
+	"backend-pedika-fiber/config"
 	"database/sql"
 	"fmt"
-	"log"
-	"os"
-	"sync"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-var (
-	db     *sql.DB
-	dbOnce sync.Once
-	DB     *gorm.DB
-)
-
-func getDB() *sql.DB {
-	dbOnce.Do(func() {
-		var err error
-		err = godotenv.Load()
-		if err != nil {
-			log.Fatal("Error loading .env file")
-		}
-
-		db, err = sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true",
-			os.Getenv("DB_USERNAME"),
-			os.Getenv("DB_PASSWORD"),
-			os.Getenv("DB_URL"),
-			os.Getenv("DB_DATABASE")))
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		db.SetMaxOpenConns(10)
-		db.SetMaxIdleConns(5)
-		err = db.Ping()
-		if err != nil {
-			log.Fatalf("Failed to connect to database: %v", err)
-		}
-
-		DB, err = gorm.Open(mysql.New(mysql.Config{
-			Conn: db,
-		}), &gorm.Config{})
-		if err != nil {
-			log.Fatal(err)
-		}
-	})
-	return db
+// Connect membuka koneksi database sekali saat aplikasi start. Kesalahan
+// dikembalikan ke pemanggil supaya main yang memutuskan untuk berhenti.
+// Koneksi yang dikembalikan diteruskan ke repository, tidak disimpan global.
+func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	switch cfg.Driver {
+	case "", "mysql":
+		return connectMySQL(cfg)
+	case "sqlite":
+		return OpenSQLite(cfg.Name)
+	default:
+		return nil, fmt.Errorf("database: unsupported driver %q", cfg.Driver)
+	}
 }
 
-func GetDBInstance() *sql.DB {
-	return getDB()
+func connectMySQL(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	sqlDB, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true",
+		cfg.Username,
+		cfg.Password,
+		cfg.Address,
+		cfg.Name))
+	if err != nil {
+		return nil, fmt.Errorf("database: failed to open connection: %w", err)
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("database: failed to connect to %s: %w", cfg.Address, err)
+	}
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: sqlDB,
+	}), &gorm.Config{})
+	if err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("database: failed to initialize gorm: %w", err)
+	}
+	return gormDB, nil
 }
 
-func GetGormDBInstance() *gorm.DB {
-	return DB
+// OpenSQLite membuka database SQLite dari path file, ":memory:", atau DSN
+// "file:..." lengkap. Foreign key diaktifkan supaya perilakunya sama dengan
+// MySQL, dan koneksi dibatasi satu karena SQLite hanya mengizinkan satu
+// penulis sekaligus.
+func OpenSQLite(name string) (*gorm.DB, error) {
+	dsn := name
+	if dsn != ":memory:" && !strings.HasPrefix(dsn, "file:") {
+		dsn = "file:" + dsn
+	}
+	if strings.Contains(dsn, "?") {
+		dsn += "&"
+	} else {
+		dsn += "?"
+	}
+	dsn += "_foreign_keys=1&_busy_timeout=5000"
+
+	gormDB, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("database: failed to open sqlite %s: %w", name, err)
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("database: failed to initialize gorm: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("database: failed to connect to sqlite %s: %w", name, err)
+	}
+	return gormDB, nil
 }
@@ -1,208 +1,142 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
 	"errors"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
 )
 
-func GetAllContents(c *fiber.Ctx) error {
-	var contents []models.Content
-	if err := database.DB.Preload("ViolenceCategory").Find(&contents).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to retrieve contents",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+func (h *Handler) GetAllContents(c *fiber.Ctx) error {
+	contents, err := h.repos.Contents.List()
+	if err != nil {
+		return helper.InternalError("content.list_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "List of contents",
+		Message: i18n.T(c, "content.list"),
 		Data:    contents,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func GetContentByID(c *fiber.Ctx) error {
-	contentID := c.Params("id")
-
-	var content models.Content
-	if err := database.DB.Preload("ViolenceCategory").First(&content, contentID).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(fiber.Map{
-			"error": "Content not found",
-		})
+func (h *Handler) GetContentByID(c *fiber.Ctx) error {
+	content, err := h.repos.Contents.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("content.not_found")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Content details",
+		Message: i18n.T(c, "content.detail"),
 		Data:    content,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func CreateContent(c *fiber.Ctx) error {
-	var content models.Content
-	if err := c.BodyParser(&content); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+type CreateContentRequest struct {
+	Judul              string `json:"judul" form:"judul" validate:"required,max=255"`
+	IsiContent         string `json:"isi_content" form:"isi_content" validate:"required"`
+	ViolenceCategoryID string `json:"violence_category_id" form:"violence_category_id" validate:"required,digits"`
+}
+
+// UpdateContentRequest: field yang kosong tidak diubah.
+type UpdateContentRequest struct {
+	Judul              string `json:"judul" form:"judul" validate:"max=255"`
+	IsiContent         string `json:"isi_content" form:"isi_content"`
+	ViolenceCategoryID string `json:"violence_category_id" form:"violence_category_id" validate:"digits"`
+}
+
+func (h *Handler) CreateContent(c *fiber.Ctx) error {
+	var req CreateContentRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
 	file, err := c.FormFile("image_content")
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Image file not provided",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+		return helper.BadRequest("common.image_required")
 	}
 
 	src, err := file.Open()
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to open image file",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("common.image_open_failed")
 	}
 	defer src.Close()
-	imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+	imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal Mengupload Gambar",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 	}
-	content.ImageContent = imageURL
-	content.Judul = c.FormValue("judul")
-	content.IsiContent = c.FormValue("isi_content")
-	violenceCategoryID, err := strconv.ParseInt(c.FormValue("violence_category_id"), 10, 64)
-	if err != nil || violenceCategoryID == 0 {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Id Kategory Kekerasan Tidak Ditemukan ",
+	violenceCategoryID := parseID(req.ViolenceCategoryID)
+	if _, err := h.repos.ViolenceCategory.FindByID(violenceCategoryID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.BadRequest("category.not_found")
 		}
-		return c.Status(http.StatusBadRequest).JSON(response)
-	}
-	var violenceCategory models.ViolenceCategory
-	if err := database.DB.First(&violenceCategory, violenceCategoryID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Kategori Kekerasan Tidak ditemukan",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
-		}
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to check violence category",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("category.check_failed")
 	}
 
-	content.ViolenceCategoryID = uint(violenceCategoryID)
-	if err := database.DB.Create(&content).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal Membuat Konten",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	content := models.Content{
+		Judul:              req.Judul,
+		IsiContent:         req.IsiContent,
+		ImageContent:       imageURL,
+		ViolenceCategoryID: violenceCategoryID,
 	}
-	if err := database.DB.Preload("ViolenceCategory").First(&content, content.ID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to load content with violence category",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Contents.Create(&content); err != nil {
+		return helper.InternalError("content.create_failed")
 	}
-	response := helper.ResponseWithData{
+	content, err = h.repos.Contents.FindByID(content.ID)
+	if err != nil {
+		return helper.InternalError("content.reload_failed")
+	}
+	response := helper.Response{
 		Code:    http.StatusCreated,
 		Status:  "success",
-		Message: "Berhasil Membuat Konten",
+		Message: i18n.T(c, "content.created"),
 		Data:    content,
 	}
 	return c.Status(http.StatusCreated).JSON(response)
 }
 
-func UpdateContent(c *fiber.Ctx) error {
-	contentID := c.Params("id")
+func (h *Handler) UpdateContent(c *fiber.Ctx) error {
+	contentID := parseID(c.Params("id"))
 
 	// Fetch existing content from the database
-	var existingContent models.Content
-	if err := database.DB.Preload("ViolenceCategory").First(&existingContent, contentID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Konten Tidak ada",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
+	existingContent, err := h.repos.Contents.FindByID(contentID)
+	if err != nil {
+		return helper.NotFound("content.not_found")
 	}
 
 	// Parse form data
-	judul := c.FormValue("judul")
-	if judul != "" {
-		existingContent.Judul = judul
+	var req UpdateContentRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
-
-	isiContent := c.FormValue("isi_content")
-	if isiContent != "" {
-		existingContent.IsiContent = isiContent
+	if req.Judul != "" {
+		existingContent.Judul = req.Judul
+	}
+	if req.IsiContent != "" {
+		existingContent.IsiContent = req.IsiContent
 	}
 
-	violenceCategoryID := c.FormValue("violence_category_id")
-	if violenceCategoryID != "" {
-		vcID, err := strconv.ParseUint(violenceCategoryID, 10, 64)
-		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Kategori ID Salah",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
-		}
+	if req.ViolenceCategoryID != "" {
+		vcID := parseID(req.ViolenceCategoryID)
 
 		// Check if the violence category exists in the database
-		var violenceCategory models.ViolenceCategory
-		if err := database.DB.First(&violenceCategory, vcID).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				response := helper.ResponseWithOutData{
-					Code:    http.StatusBadRequest,
-					Status:  "error",
-					Message: "Tidak Dapat Mencari Kategori kekerasan",
-				}
-				return c.Status(http.StatusBadRequest).JSON(response)
+		if _, err := h.repos.ViolenceCategory.FindByID(vcID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return helper.BadRequest("category.not_found")
 			}
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Gagal Memeriksa Kategori kekerasan",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.InternalError("category.check_failed")
 		}
-		existingContent.ViolenceCategoryID = uint(vcID)
+		existingContent.ViolenceCategoryID = vcID
 	}
 
 	// Handle image file if provided
@@ -210,23 +144,13 @@ func UpdateContent(c *fiber.Ctx) error {
 	if err == nil {
 		src, err := file.Open()
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Gagal Membuka File Gambar",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.InternalError("common.image_open_failed")
 		}
 		defer src.Close()
 
-		imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+		imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Gagal Mengupload Gambar",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 		}
 		existingContent.ImageContent = imageURL
 	}
@@ -235,48 +159,36 @@ func UpdateContent(c *fiber.Ctx) error {
 	existingContent.UpdatedAt = time.Now()
 
 	// Save updated content to the database
-	if err := database.DB.Save(&existingContent).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal Mengupdate Konten",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Contents.Save(&existingContent); err != nil {
+		return helper.InternalError("content.update_failed")
 	}
 
 	// Reload content with related ViolenceCategory to include in response
-	if err := database.DB.Preload("ViolenceCategory").First(&existingContent, contentID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal Memuat Konten dengan Kategori Kekerasan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	existingContent, err = h.repos.Contents.FindByID(contentID)
+	if err != nil {
+		return helper.InternalError("content.reload_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Berhasil Mengupdate Konten",
+		Message: i18n.T(c, "content.updated"),
 		Data:    existingContent,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func DeleteContent(c *fiber.Ctx) error {
-	contentID := c.Params("id")
-	var content models.Content
-	if err := database.DB.First(&content, contentID).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(fiber.Map{
-			"error": "Content not found",
-		})
+func (h *Handler) DeleteContent(c *fiber.Ctx) error {
+	contentID := parseID(c.Params("id"))
+	if _, err := h.repos.Contents.FindByID(contentID); err != nil {
+		return helper.NotFound("content.not_found")
 	}
-	if err := database.DB.Delete(&content, contentID).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete content",
-		})
+	if err := h.repos.Contents.Delete(contentID); err != nil {
+		return helper.InternalError("content.delete_failed")
 	}
-	return c.Status(http.StatusOK).JSON(fiber.Map{
-		"message": "Kontent Berhasil Dihapus",
+	return c.Status(http.StatusOK).JSON(helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "content.deleted"),
 	})
 }
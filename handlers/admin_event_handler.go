@@ -1,209 +1,132 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"backend-pedika-fiber/models"
-	"fmt"
+	"backend-pedika-fiber/validation"
 	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func GetAllEvent(c *fiber.Ctx) error {
-	var event []models.Event
-	if err := database.DB.Find(&event).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to retrieve contents",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+func (h *Handler) GetAllEvent(c *fiber.Ctx) error {
+	event, err := h.repos.Events.List()
+	if err != nil {
+		return helper.InternalError("content.list_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "List of contents",
+		Message: i18n.T(c, "event.list"),
 		Data:    event,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func GetEventByID(c *fiber.Ctx) error {
-	eventID := c.Params("id")
-
-	var event models.Event
-	if err := database.DB.First(&event, eventID).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Event not found",
-		})
+func (h *Handler) GetEventByID(c *fiber.Ctx) error {
+	event, err := h.repos.Events.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("event.not_found")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Event details",
+		Message: i18n.T(c, "event.detail"),
 		Data:    event,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func CreateEvent(c *fiber.Ctx) error {
-	var event models.Event
-	if err := c.BodyParser(&event); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+// Format tanggal_pelaksanaan yang diterima: input datetime-local dari web
+// admin, serta beberapa format lain yang pernah dikirim client. Daftar ini
+// harus sama dengan aturan datetime di tag validate request event.
+var eventDateLayouts = []string{
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:00",
+	"2006-01-02 15:04",
+	"02/01/2006 15:04",
+}
+
+type CreateEventRequest struct {
+	NamaEvent          string `json:"nama_event" form:"nama_event" validate:"required,max=255"`
+	DeskripsiEvent     string `json:"deskripsi_event" form:"deskripsi_event" validate:"required"`
+	TanggalPelaksanaan string `json:"tanggal_pelaksanaan" form:"tanggal_pelaksanaan" validate:"required,datetime=2006-01-02T15:04|2006-01-02 15:04:00|2006-01-02 15:04|02/01/2006 15:04"`
+}
+
+// UpdateEventRequest: field yang kosong tidak diubah.
+type UpdateEventRequest struct {
+	NamaEvent          string `json:"nama_event" form:"nama_event" validate:"max=255"`
+	DeskripsiEvent     string `json:"deskripsi_event" form:"deskripsi_event"`
+	TanggalPelaksanaan string `json:"tanggal_pelaksanaan" form:"tanggal_pelaksanaan" validate:"datetime=2006-01-02T15:04|2006-01-02 15:04:00|2006-01-02 15:04|02/01/2006 15:04"`
+}
+
+func (h *Handler) CreateEvent(c *fiber.Ctx) error {
+	var req CreateEventRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
 
 	file, err := c.FormFile("thumbnail_event")
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Image file not provided",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+		return helper.BadRequest("common.image_required")
 	}
 
 	src, err := file.Open()
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to open image file",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("common.image_open_failed")
 	}
 	defer src.Close()
 
-	imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+	imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to upload image",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
-	}
-
-	event.ThumbnailEvent = imageURL
-	event.NamaEvent = c.FormValue("nama_event")
-	event.DeskripsiEvent = c.FormValue("deskripsi_event")
-	tanggalPelaksanaanStr := c.FormValue("tanggal_pelaksanaan")
-
-	var parsedDate time.Time
-	var parseErr error
-
-	formats := []string{
-		"2006-01-02T15:04",    // Format expected from datetime-local input
-		"2006-01-02 15:04:00", // Format received in the log
-		"2006-01-02 15:04",    // Without seconds
-		"02/01/2006 15:04",    // Another possible format
-	}
-
-	for _, format := range formats {
-		parsedDate, parseErr = time.Parse(format, tanggalPelaksanaanStr)
-		if parseErr == nil {
-			break
-		}
-		fmt.Println("Error parsing date with format", format, ":", parseErr)
+		return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 	}
 
-	if parseErr != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Format tanggal tidak valid. Format yang diharapkan adalah YYYY-MM-DDTHH:MM",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+	tanggalPelaksanaan, _ := validation.ParseDateTime(req.TanggalPelaksanaan, eventDateLayouts...)
+	event := models.Event{
+		NamaEvent:          req.NamaEvent,
+		DeskripsiEvent:     req.DeskripsiEvent,
+		ThumbnailEvent:     imageURL,
+		TanggalPelaksanaan: tanggalPelaksanaan,
 	}
 
-	event.TanggalPelaksanaan = parsedDate
-
-	if err := database.DB.Create(&event).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to create event",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Events.Create(&event); err != nil {
+		return helper.InternalError("event.create_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusCreated,
 		Status:  "success",
-		Message: "Berhasil membuat Content",
+		Message: i18n.T(c, "event.created"),
 		Data:    event,
 	}
 	return c.Status(http.StatusCreated).JSON(response)
 }
 
-func UpdateEvent(c *fiber.Ctx) error {
-	eventID := c.Params("id")
-
+func (h *Handler) UpdateEvent(c *fiber.Ctx) error {
 	// Fetch existing event from the database
-	var existingEvent models.Event
-	if err := database.DB.First(&existingEvent, eventID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Event tidak ditemukan",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
+	existingEvent, err := h.repos.Events.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("event.not_found")
 	}
 
 	// Parse form data for updating the event
-	eventName := c.FormValue("nama_event")
-	if eventName != "" {
-		existingEvent.NamaEvent = eventName
+	var req UpdateEventRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
-
-	eventDescription := c.FormValue("deskripsi_event")
-	if eventDescription != "" {
-		existingEvent.DeskripsiEvent = eventDescription
+	if req.NamaEvent != "" {
+		existingEvent.NamaEvent = req.NamaEvent
 	}
-
-	eventDate := c.FormValue("tanggal_pelaksanaan")
-	if eventDate != "" {
-		fmt.Println("Received eventDate:", eventDate) // Log to see the format of the date received
-
-		var parsedDate time.Time
-		var err error
-
-		// Try different date formats
-		formats := []string{
-			"2006-01-02T15:04",    // Format expected from datetime-local input
-			"2006-01-02 15:04:00", // Format received in the log
-			"2006-01-02 15:04",    // Without seconds
-			"02/01/2006 15:04",    // Another possible format
-		}
-
-		for _, format := range formats {
-			parsedDate, err = time.Parse(format, eventDate)
-			if err == nil {
-				break
-			}
-			fmt.Println("Error parsing date with format", format, ":", err)
-		}
-
-		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Format tanggal tidak valid. Format yang diharapkan adalah YYYY-MM-DDTHH:MM",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
-		}
-
-		existingEvent.TanggalPelaksanaan = parsedDate
+	if req.DeskripsiEvent != "" {
+		existingEvent.DeskripsiEvent = req.DeskripsiEvent
+	}
+	if req.TanggalPelaksanaan != "" {
+		existingEvent.TanggalPelaksanaan, _ = validation.ParseDateTime(req.TanggalPelaksanaan, eventDateLayouts...)
 	}
 
 	// Handle image file if provided
@@ -211,23 +134,13 @@ func UpdateEvent(c *fiber.Ctx) error {
 	if err == nil {
 		src, err := file.Open()
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Gagal membuka file gambar",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.InternalError("common.image_open_failed")
 		}
 		defer src.Close()
 
-		imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+		imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Gagal mengupload gambar",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 		}
 		existingEvent.ThumbnailEvent = imageURL
 	}
@@ -236,38 +149,30 @@ func UpdateEvent(c *fiber.Ctx) error {
 	existingEvent.UpdatedAt = time.Now()
 
 	// Save the updated event to the database
-	if err := database.DB.Save(&existingEvent).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal mengupdate event",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Events.Save(&existingEvent); err != nil {
+		return helper.InternalError("event.update_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Event berhasil diedit",
+		Message: i18n.T(c, "event.updated"),
 		Data:    existingEvent,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func DeleteEvent(c *fiber.Ctx) error {
-	eventID := c.Params("id")
-	var event models.Event
-	if err := database.DB.First(&event, eventID).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(fiber.Map{
-			"error": "event not found",
-		})
+func (h *Handler) DeleteEvent(c *fiber.Ctx) error {
+	eventID := parseID(c.Params("id"))
+	if _, err := h.repos.Events.FindByID(eventID); err != nil {
+		return helper.NotFound("event.not_found")
 	}
-	if err := database.DB.Delete(&event, eventID).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete event",
-		})
+	if err := h.repos.Events.Delete(eventID); err != nil {
+		return helper.InternalError("event.delete_failed")
 	}
-	return c.Status(http.StatusOK).JSON(fiber.Map{
-		"message": "event deleted successfully",
+	return c.Status(http.StatusOK).JSON(helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "event.deleted"),
 	})
 }
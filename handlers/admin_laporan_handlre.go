@@ -1,34 +1,22 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/auth"
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
 	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
 )
 
 /*=========================== AMBIL SEMUA LAPORAN =======================*/
-func GetLatestReports(c *fiber.Ctx) error {
-	var reports []models.Laporan
-	db := database.GetGormDBInstance()
-
-	if err := db.
-		Preload("ViolenceCategory").
-		Order("created_at desc").
-		Limit(10).
-		Find(&reports).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to fetch latest reports",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+func (h *Handler) GetLatestReports(c *fiber.Ctx) error {
+	reports, err := h.repos.Laporan.Latest(10)
+	if err != nil {
+		return helper.InternalError("laporan.latest_failed")
 	}
 
 	var result []map[string]interface{}
@@ -56,10 +44,10 @@ func GetLatestReports(c *fiber.Ctx) error {
 		})
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Latest reports retrieved successfully",
+		Message: i18n.T(c, "laporan.latest"),
 		Data:    result,
 	}
 	return c.Status(http.StatusOK).JSON(response)
@@ -67,71 +55,36 @@ func GetLatestReports(c *fiber.Ctx) error {
 
 /*=========================== TAMPILKAN DETAIL LAPORAN USER BERDASARKAN NO_REGISTRASI =======================*/
 
-func GetLaporanByNoRegistrasi(c *fiber.Ctx) error {
+func (h *Handler) GetLaporanByNoRegistrasi(c *fiber.Ctx) error {
 	noRegistrasi := c.Params("no_registrasi")
-	var laporan models.Laporan
-	db := database.GetGormDBInstance()
-
-	if err := db.
-		Preload("User").
-		Preload("ViolenceCategory").
-		Where("no_registrasi = ?", noRegistrasi).
-		First(&laporan).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Report not found",
-			}
-			return c.Status(http.StatusNotFound).JSON(response)
-		}
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to fetch report detail",
+	laporan, err := h.repos.Laporan.FindDetail(noRegistrasi)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("laporan.not_found")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("laporan.retrieve_failed")
 	}
 
-	var trackingLaporan []models.TrackingLaporan
-	if err := db.Where("no_registrasi = ?", noRegistrasi).Find(&trackingLaporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to fetch tracking laporan details",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	trackingLaporan, err := h.repos.Tracking.ListByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		return helper.InternalError("laporan.tracking_failed")
 	}
 
-	var pelaku []models.Pelaku
-	if err := db.Where("no_registrasi = ?", noRegistrasi).Find(&pelaku).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to fetch pelaku details",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	pelaku, err := h.repos.Pelaku.ListByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		return helper.InternalError("laporan.pelaku_failed")
 	}
 
-	var korban []models.Korban
-	if err := db.Where("no_registrasi = ?", noRegistrasi).Find(&korban).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to fetch korban details",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	korban, err := h.repos.Korban.ListByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		return helper.InternalError("laporan.korban_failed")
 	}
 
 	var userMelihat models.User
 	if laporan.UserIDMelihat != nil {
-		if err := db.First(&userMelihat, *laporan.UserIDMelihat).Error; err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to fetch user detail who viewed the report",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+		userMelihat, err = h.repos.Users.FindByID(*laporan.UserIDMelihat)
+		if err != nil {
+			return helper.InternalError("laporan.viewer_failed")
 		}
 	}
 
@@ -153,66 +106,50 @@ func GetLaporanByNoRegistrasi(c *fiber.Ctx) error {
 		responseData.UserMelihat = &userMelihat
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Report detail retrieved successfully",
+		Message: i18n.T(c, "laporan.detail"),
 		Data:    responseData,
 	}
 
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func AdminLihatLaporan(c *fiber.Ctx) error {
+func (h *Handler) AdminLihatLaporan(c *fiber.Ctx) error {
 	noRegistrasi := c.Params("no_registrasi")
 	token := c.Get("Authorization")
-	userID, err := auth.ExtractUserIDFromToken(token)
+	userID, err := h.jwt.ExtractUserIDFromToken(token)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized",
-		}
-		return c.Status(http.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("common.unauthorized")
 	}
 
-	var laporan models.Laporan
-	db := database.GetGormDBInstance()
-	if err := db.Where("no_registrasi = ?", noRegistrasi).First(&laporan).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Laporan not found",
-			}
-			return c.Status(http.StatusNotFound).JSON(response)
-		}
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to retrieve laporan",
+	laporan, err := h.repos.Laporan.FindByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("laporan.not_found")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("laporan.retrieve_failed")
 	}
 
+	// Membuka ulang laporan yang sudah dilihat tidak mengirim notifikasi lagi.
+	berubah := laporan.Status != "Dilihat"
 	laporan.Status = "Dilihat"
 	now := time.Now()
 	laporan.WaktuDilihat = &now
 	laporan.UserIDMelihat = &userID
 
-	if err := db.Save(&laporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update laporan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Laporan.Save(&laporan); err != nil {
+		return helper.InternalError("laporan.update_failed")
+	}
+	if berubah {
+		h.notifyLaporan(laporan, laporan.UserID, models.NotificationLaporanDilihat)
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Laporan status updated successfully",
+		Message: i18n.T(c, "laporan.status_updated"),
 		Data: fiber.Map{
 			"no_registrasi":  laporan.NoRegistrasi,
 			"status":         laporan.Status,
@@ -225,45 +162,33 @@ func AdminLihatLaporan(c *fiber.Ctx) error {
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func AdminProsesLaporan(c *fiber.Ctx) error {
+func (h *Handler) AdminProsesLaporan(c *fiber.Ctx) error {
 	noRegistrasi := c.Params("no_registrasi")
 
-	var laporan models.Laporan
-	db := database.GetGormDBInstance()
-	if err := db.Where("no_registrasi = ?", noRegistrasi).First(&laporan).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Laporan not found",
-			}
-			return c.Status(http.StatusNotFound).JSON(response)
-		}
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to retrieve laporan",
+	laporan, err := h.repos.Laporan.FindByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("laporan.not_found")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("laporan.retrieve_failed")
 	}
 
+	berubah := laporan.Status != "Diproses"
 	laporan.Status = "Diproses"
 	now := time.Now()
 	laporan.WaktuDiproses = &now
 
-	if err := db.Save(&laporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update laporan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Laporan.Save(&laporan); err != nil {
+		return helper.InternalError("laporan.update_failed")
+	}
+	if berubah {
+		h.notifyLaporan(laporan, laporan.UserID, models.NotificationLaporanDiproses)
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Laporan Berhasil Diproses",
+		Message: i18n.T(c, "laporan.processed"),
 		Data: fiber.Map{
 			"no_registrasi":  laporan.NoRegistrasi,
 			"status":         laporan.Status,
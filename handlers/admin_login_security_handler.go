@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/repository"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+/*=========================== AKUN YANG SEDANG TERKUNCI =======================*/
+func (h *Handler) AdminGetLockedAccounts(c *fiber.Ctx) error {
+	users, err := h.repos.Users.ListLocked(time.Now())
+	if err != nil {
+		return helper.InternalError("login_security.locked_list_failed")
+	}
+
+	var result []fiber.Map
+	for _, user := range users {
+		result = append(result, fiber.Map{
+			"id":                   user.ID,
+			"full_name":            user.FullName,
+			"username":             user.Username,
+			"email":                user.Email,
+			"role":                 user.Role,
+			"locked_until":         user.LockedUntil,
+			"last_failed_login_at": user.LastFailedLoginAt,
+		})
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "login_security.locked_list"),
+		Data:    result,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) AdminUnlockUser(c *fiber.Ctx) error {
+	user, status, message := h.findUserByParam(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+
+	h.resetFailedLogins(user.ID)
+
+	return c.Status(http.StatusOK).JSON(helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "login_security.unlocked"),
+	})
+}
+
+/*=========================== RIWAYAT PERCOBAAN LOGIN =======================*/
+func (h *Handler) AdminGetLoginAttempts(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := repository.LoginAttemptFilter{
+		UserID:    uint(c.QueryInt("user_id")),
+		IPAddress: c.Query("ip"),
+	}
+	if success := c.Query("success"); success != "" {
+		value := success == "true"
+		filter.Success = &value
+	}
+
+	attempts, total, err := h.repos.LoginAttempts.List(filter, repository.Page{Page: page, Limit: limit})
+	if err != nil {
+		return helper.InternalError("login_security.attempts_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "login_security.attempts_list"),
+		Data: fiber.Map{
+			"login_attempts": attempts,
+			"pagination": fiber.Map{
+				"page":  page,
+				"limit": limit,
+				"total": total,
+			},
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *Handler) GetRoles(c *fiber.Ctx) error {
+	var roles []fiber.Map
+	for role, permissions := range auth.RolePermissions {
+		roles = append(roles, fiber.Map{
+			"role":        role,
+			"permissions": permissions,
+		})
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i]["role"].(string) < roles[j]["role"].(string)
+	})
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "role.list"),
+		Data:    roles,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) UpdateUserRole(c *fiber.Ctx) error {
+	adminID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return helper.Unauthorized("common.unauthorized")
+	}
+
+	var req struct {
+		Role string `json:"role" form:"role" validate:"required"`
+	}
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	if !auth.IsValidRole(req.Role) {
+		return helper.BadRequest("role.unknown")
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return helper.BadRequest("user.invalid_id")
+	}
+	if uint(id) == adminID {
+		return helper.Forbidden("role.self_change")
+	}
+
+	user, err := h.repos.Users.FindByID(uint(id))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("user.not_found")
+		}
+		return helper.InternalError("user.retrieve_failed")
+	}
+
+	user.Role = req.Role
+	user.UpdatedAt = time.Now()
+	if err := h.repos.Users.Save(&user); err != nil {
+		return helper.InternalError("role.update_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "role.updated"),
+		Data: fiber.Map{
+			"id":         user.ID,
+			"username":   user.Username,
+			"role":       user.Role,
+			"updated_at": user.UpdatedAt,
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
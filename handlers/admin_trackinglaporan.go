@@ -1,88 +1,73 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
 	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/datatypes"
-	"gorm.io/gorm"
 )
 
-func CreateTrackingLaporan(c *fiber.Ctx) error {
-	var trackingLaporan models.TrackingLaporan
-	if err := c.BodyParser(&trackingLaporan); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
-	}
-	noRegistrasi := c.FormValue("no_registrasi")
-	if noRegistrasi == "" {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "No Registrasi is required",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
-	}
-	var existingLaporan models.Laporan
-	if err := database.GetGormDBInstance().Where("no_registrasi = ?", noRegistrasi).First(&existingLaporan).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "No Registrasi not found in Laporan table",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
-		}
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Database error",
+type CreateTrackingLaporanRequest struct {
+	NoRegistrasi string `json:"no_registrasi" form:"no_registrasi" validate:"required"`
+	Keterangan   string `json:"keterangan" form:"keterangan" validate:"required,max=2000"`
+}
+
+// UpdateTrackingLaporanRequest: field yang kosong tidak diubah. Document hanya
+// dipakai jika request dikirim sebagai JSON, bukan multipart.
+type UpdateTrackingLaporanRequest struct {
+	NoRegistrasi string            `json:"no_registrasi" form:"no_registrasi"`
+	Keterangan   string            `json:"keterangan" form:"keterangan" validate:"max=2000"`
+	Document     datatypes.JSONMap `json:"document" form:"-"`
+}
+
+func (h *Handler) CreateTrackingLaporan(c *fiber.Ctx) error {
+	var req CreateTrackingLaporanRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	noRegistrasi := req.NoRegistrasi
+	laporan, err := h.repos.Laporan.FindByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.BadRequest("laporan.not_found")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("common.internal_error")
 	}
 
 	form, err := c.MultipartForm()
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to retrieve multipart form",
-		})
+		return helper.InternalError("common.multipart_failed")
 	}
 	files := form.File["document"]
-	imageURLs, err := helper.UploadMultipleFileToCloudinary(files)
+	imageURLs, err := helper.UploadMultipleFileToCloudinary(h.uploader, files)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to upload documents",
-		})
+		return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.document_upload_failed")
 	}
 
-	trackingLaporan.Document = datatypes.JSONMap{"urls": imageURLs}
-	trackingLaporan.NoRegistrasi = noRegistrasi
-	trackingLaporan.Keterangan = c.FormValue("keterangan")
-	trackingLaporan.CreatedAt = time.Now()
-	trackingLaporan.UpdatedAt = time.Now()
+	trackingLaporan := models.TrackingLaporan{
+		NoRegistrasi: noRegistrasi,
+		Keterangan:   req.Keterangan,
+		Document:     datatypes.JSONMap{"urls": imageURLs},
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
 
-	if err := database.GetGormDBInstance().Create(&trackingLaporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to create tracking laporan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Tracking.Create(&trackingLaporan); err != nil {
+		return helper.InternalError("tracking.create_failed")
 	}
+	h.notifyLaporan(laporan, laporan.UserID, models.NotificationTrackingLaporan, trackingLaporan.Keterangan)
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusCreated,
 		Status:  "success",
-		Message: "Tracking laporan created successfully",
+		Message: i18n.T(c, "tracking.created"),
 		Data: fiber.Map{
 			"id":            trackingLaporan.ID,
 			"no_registrasi": trackingLaporan.NoRegistrasi,
@@ -96,43 +81,23 @@ func CreateTrackingLaporan(c *fiber.Ctx) error {
 	return c.Status(http.StatusCreated).JSON(response)
 }
 
-func UpdateTrackingLaporan(c *fiber.Ctx) error {
+func (h *Handler) UpdateTrackingLaporan(c *fiber.Ctx) error {
 	trackingLaporanID := c.Params("id")
 	if trackingLaporanID == "" {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "ID is required",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+		return helper.BadRequest("common.id_required")
 	}
 
-	var trackingLaporan models.TrackingLaporan
-	if err := database.GetGormDBInstance().First(&trackingLaporan, trackingLaporanID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Tracking Laporan not found",
-			}
-			return c.Status(http.StatusNotFound).JSON(response)
-		}
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Database error",
+	trackingLaporan, err := h.repos.Tracking.FindByID(parseID(trackingLaporanID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("tracking.not_found")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("common.internal_error")
 	}
 
-	var updatedData models.TrackingLaporan
-	if err := c.BodyParser(&updatedData); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+	var updatedData UpdateTrackingLaporanRequest
+	if err := validation.Bind(c, &updatedData); err != nil {
+		return err
 	}
 
 	if updatedData.NoRegistrasi != "" {
@@ -143,19 +108,15 @@ func UpdateTrackingLaporan(c *fiber.Ctx) error {
 	}
 	form, err := c.MultipartForm()
 	if err != nil && err != http.ErrNotMultipart {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to retrieve multipart form",
-		})
+		return helper.InternalError("common.multipart_failed")
 	}
 
 	if form != nil {
 		files := form.File["document"]
 		if len(files) > 0 {
-			imageURLs, err := helper.UploadMultipleFileToCloudinary(files)
+			imageURLs, err := helper.UploadMultipleFileToCloudinary(h.uploader, files)
 			if err != nil {
-				return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Failed to upload images",
-				})
+				return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 			}
 			trackingLaporan.Document = datatypes.JSONMap{"urls": imageURLs}
 		}
@@ -165,19 +126,14 @@ func UpdateTrackingLaporan(c *fiber.Ctx) error {
 
 	trackingLaporan.UpdatedAt = time.Now()
 
-	if err := database.GetGormDBInstance().Save(&trackingLaporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update tracking laporan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Tracking.Save(&trackingLaporan); err != nil {
+		return helper.InternalError("tracking.update_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Tracking laporan updated successfully",
+		Message: i18n.T(c, "tracking.updated"),
 		Data: fiber.Map{
 			"id":            trackingLaporan.ID,
 			"no_registrasi": trackingLaporan.NoRegistrasi,
@@ -191,48 +147,28 @@ func UpdateTrackingLaporan(c *fiber.Ctx) error {
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func DeleteTrackingLaporan(c *fiber.Ctx) error {
+func (h *Handler) DeleteTrackingLaporan(c *fiber.Ctx) error {
 	trackingLaporanID := c.Params("id")
 	if trackingLaporanID == "" {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "ID is required",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+		return helper.BadRequest("common.id_required")
 	}
 
-	var trackingLaporan models.TrackingLaporan
-	if err := database.GetGormDBInstance().First(&trackingLaporan, trackingLaporanID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Tracking Laporan not found",
-			}
-			return c.Status(http.StatusNotFound).JSON(response)
-		}
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Database error",
+	trackingLaporan, err := h.repos.Tracking.FindByID(parseID(trackingLaporanID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("tracking.not_found")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("common.internal_error")
 	}
 
-	if err := database.GetGormDBInstance().Delete(&trackingLaporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to delete tracking laporan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Tracking.Delete(&trackingLaporan); err != nil {
+		return helper.InternalError("tracking.delete_failed")
 	}
 
-	response := helper.ResponseWithOutData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Tracking laporan deleted successfully",
+		Message: i18n.T(c, "tracking.deleted"),
 	}
 
 	return c.Status(http.StatusOK).JSON(response)
@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminCreateUserRequest struct {
+	FullName    string `json:"full_name" form:"full_name" validate:"required,max=100"`
+	Username    string `json:"username" form:"username" validate:"min=3,max=50"`
+	Email       string `json:"email" form:"email" validate:"required,email,max=255"`
+	PhoneNumber string `json:"phone_number" form:"phone_number" validate:"required,phone"`
+	Password    string `json:"password" form:"password" validate:"required,min=8,max=72"`
+	Role        string `json:"role" form:"role"`
+	Alamat      string `json:"alamat" form:"alamat" validate:"max=255"`
+}
+
+// AdminUserRequest dipakai untuk mengubah user; field yang kosong tidak diubah.
+type AdminUserRequest struct {
+	FullName    string `json:"full_name" form:"full_name" validate:"max=100"`
+	Username    string `json:"username" form:"username" validate:"min=3,max=50"`
+	Email       string `json:"email" form:"email" validate:"email,max=255"`
+	PhoneNumber string `json:"phone_number" form:"phone_number" validate:"phone"`
+	Alamat      string `json:"alamat" form:"alamat" validate:"max=255"`
+}
+
+type SuspendUserRequest struct {
+	AlasanSuspend string `json:"alasan_suspend" form:"alasan_suspend" validate:"required,max=500"`
+}
+
+/*=========================== LIST USER DENGAN PENCARIAN DAN PAGINASI =======================*/
+func (h *Handler) AdminGetUsers(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	filter := repository.UserFilter{
+		Search: strings.TrimSpace(c.Query("search")),
+		Role:   c.Query("role"),
+	}
+	if suspended := c.Query("suspended"); suspended != "" {
+		value := suspended == "true"
+		filter.Suspended = &value
+	}
+
+	users, total, err := h.repos.Users.List(filter, repository.Page{Page: page, Limit: limit})
+	if err != nil {
+		return helper.InternalError("user.list_failed")
+	}
+	for i := range users {
+		users[i].Password = ""
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "user.list"),
+		Data: fiber.Map{
+			"users": users,
+			"pagination": fiber.Map{
+				"page":  page,
+				"limit": limit,
+				"total": total,
+			},
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) AdminGetUserByID(c *fiber.Ctx) error {
+	user, status, message := h.findUserByParam(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	user.Password = ""
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "user.detail"),
+		Data:    user,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+/*=========================== BUAT USER BARU OLEH ADMIN =======================*/
+func (h *Handler) AdminCreateUser(c *fiber.Ctx) error {
+	var req AdminCreateUserRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	if req.Role == "" {
+		req.Role = models.RoleMasyarakat
+	}
+	if !auth.IsValidRole(req.Role) {
+		return helper.BadRequest("role.unknown")
+	}
+	if req.Role != models.RoleMasyarakat && !callerHasPermission(c, auth.PermRoleManage) {
+		return helper.Forbidden("user.staff_create_forbidden")
+	}
+
+	if h.isEmailExists(req.Email) {
+		return helper.BadRequest("auth.email_registered")
+	}
+	if h.isPhoneNumberExists(req.PhoneNumber) {
+		return helper.BadRequest("auth.phone_registered")
+	}
+	if req.Username == "" {
+		req.Username = h.generateUsername(req.FullName)
+	} else if h.isUsernameExists(req.Username) {
+		return helper.BadRequest("auth.username_taken")
+	}
+
+	hashedPassword, err := HashPassword(req.Password)
+	if err != nil {
+		return helper.InternalError("password.hash_failed")
+	}
+
+	// Akun yang dibuat admin dianggap sudah diverifikasi oleh admin tersebut.
+	now := time.Now()
+	user := models.User{
+		FullName:        req.FullName,
+		Username:        req.Username,
+		Email:           req.Email,
+		PhoneNumber:     req.PhoneNumber,
+		Password:        hashedPassword,
+		Role:            req.Role,
+		Alamat:          req.Alamat,
+		EmailVerifiedAt: &now,
+		PhoneVerifiedAt: &now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := h.repos.Users.Create(&user); err != nil {
+		return helper.InternalError("user.create_failed")
+	}
+	user.Password = ""
+
+	response := helper.Response{
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: i18n.T(c, "user.created"),
+		Data:    user,
+	}
+	return c.Status(http.StatusCreated).JSON(response)
+}
+
+/*=========================== EDIT USER OLEH ADMIN =======================*/
+func (h *Handler) AdminUpdateUser(c *fiber.Ctx) error {
+	user, status, message := h.findUserByParam(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	if user.Role != models.RoleMasyarakat && !callerHasPermission(c, auth.PermRoleManage) {
+		return helper.Forbidden("user.staff_change_forbidden")
+	}
+
+	var req AdminUserRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+
+	if req.Username != "" && req.Username != user.Username {
+		if h.isUsernameExists(req.Username) {
+			return helper.BadRequest("auth.username_taken")
+		}
+		user.Username = req.Username
+	}
+	if req.Email != "" && req.Email != user.Email {
+		if h.isEmailExists(req.Email) {
+			return helper.BadRequest("auth.email_registered")
+		}
+		user.Email = req.Email
+		user.EmailVerifiedAt = nil
+	}
+	if req.PhoneNumber != "" && req.PhoneNumber != user.PhoneNumber {
+		if h.isPhoneNumberExists(req.PhoneNumber) {
+			return helper.BadRequest("auth.phone_registered")
+		}
+		user.PhoneNumber = req.PhoneNumber
+		user.PhoneVerifiedAt = nil
+	}
+	if req.FullName != "" {
+		user.FullName = req.FullName
+	}
+	if req.Alamat != "" {
+		user.Alamat = req.Alamat
+	}
+	user.UpdatedAt = time.Now()
+
+	if err := h.repos.Users.Save(&user); err != nil {
+		return helper.InternalError("user.update_failed")
+	}
+	user.Password = ""
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "user.updated"),
+		Data:    user,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+/*=========================== HAPUS USER =======================*/
+func (h *Handler) AdminDeleteUser(c *fiber.Ctx) error {
+	user, status, message := h.findUserByParam(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	if status, message := h.guardStaffAccountChange(c, user); status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+
+	if err := h.repos.Users.Delete(&user); err != nil {
+		if strings.Contains(err.Error(), "foreign key constraint fails") {
+			return helper.BadRequest("user.delete_blocked")
+		}
+		return helper.InternalError("user.delete_failed")
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "user.deleted"),
+	})
+}
+
+/*=========================== SUSPEND DAN AKTIFKAN KEMBALI USER =======================*/
+func (h *Handler) AdminSuspendUser(c *fiber.Ctx) error {
+	user, status, message := h.findUserByParam(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	if status, message := h.guardStaffAccountChange(c, user); status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+
+	var req SuspendUserRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.IsSuspended = true
+	user.SuspendedAt = &now
+	user.AlasanSuspend = strings.TrimSpace(req.AlasanSuspend)
+	user.UpdatedAt = now
+	if err := h.repos.Users.Save(&user); err != nil {
+		return helper.InternalError("user.suspend_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "user.suspended"),
+		Data: fiber.Map{
+			"id":             user.ID,
+			"is_suspended":   user.IsSuspended,
+			"suspended_at":   user.SuspendedAt,
+			"alasan_suspend": user.AlasanSuspend,
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) AdminReactivateUser(c *fiber.Ctx) error {
+	user, status, message := h.findUserByParam(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	if status, message := h.guardStaffAccountChange(c, user); status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+
+	user.IsSuspended = false
+	user.SuspendedAt = nil
+	user.AlasanSuspend = ""
+	user.UpdatedAt = time.Now()
+	if err := h.repos.Users.Save(&user); err != nil {
+		return helper.InternalError("user.reactivate_failed")
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "user.reactivated"),
+	})
+}
+
+/*=========================== RESET PASSWORD OLEH ADMIN =======================*/
+func (h *Handler) AdminResetUserPassword(c *fiber.Ctx) error {
+	user, status, message := h.findUserByParam(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	if status, message := h.guardStaffAccountChange(c, user); status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+
+	temporaryPassword, err := generateTemporaryPassword()
+	if err != nil {
+		return helper.InternalError("password.temporary_failed")
+	}
+	hashedPassword, err := HashPassword(temporaryPassword)
+	if err != nil {
+		return helper.InternalError("password.hash_failed")
+	}
+	if err := h.repos.Users.UpdatePassword(user.ID, hashedPassword); err != nil {
+		return helper.InternalError("password.update_failed")
+	}
+
+	// Password sementara hanya ditampilkan sekali, admin menyampaikannya ke user.
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "password.user_reset"),
+		Data: fiber.Map{
+			"id":                 user.ID,
+			"temporary_password": temporaryPassword,
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) findUserByParam(c *fiber.Ctx) (models.User, int, string) {
+	var user models.User
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return user, http.StatusBadRequest, "user.invalid_id"
+	}
+	user, err = h.repos.Users.FindByID(uint(id))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return user, http.StatusNotFound, "user.not_found"
+		}
+		return user, http.StatusInternalServerError, "user.retrieve_failed"
+	}
+	return user, 0, ""
+}
+
+// guardStaffAccountChange mencegah admin menonaktifkan/menghapus akunnya sendiri
+// dan membatasi perubahan akun staff hanya untuk role yang boleh mengelola role.
+func (h *Handler) guardStaffAccountChange(c *fiber.Ctx, user models.User) (int, string) {
+	adminID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return http.StatusUnauthorized, "common.unauthorized"
+	}
+	if user.ID == adminID {
+		return http.StatusForbidden, "user.self_action"
+	}
+	if user.Role != models.RoleMasyarakat && !callerHasPermission(c, auth.PermRoleManage) {
+		return http.StatusForbidden, "user.staff_change_forbidden"
+	}
+	return 0, ""
+}
+
+func callerHasPermission(c *fiber.Ctx, permission string) bool {
+	role, _ := c.Locals("role").(string)
+	return auth.HasPermission(role, permission)
+}
+
+func generateTemporaryPassword() (string, error) {
+	bytes := make([]byte, 9)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
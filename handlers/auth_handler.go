@@ -1,112 +1,96 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"backend-pedika-fiber/models"
-	"context"
-	"database/sql"
+	"backend-pedika-fiber/validation"
 	"fmt"
 	"log"
 	"math/rand"
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gofiber/fiber/v2"
-	"github.com/joho/godotenv"
 	"golang.org/x/crypto/bcrypt"
 )
 
-type Response struct {
-	Success int         `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
-	Token   string      `json:"token,omitempty"`
-	UserID  int         `json:"user_id,omitempty"`
-}
-
 /*|| ========================= REGISTER =================================== ||*/
 
-// func isPhoneNumberValid(phoneNumber string) bool {
-// 	pattern := `^08[0-9]{9,11}$`
-// 	matched, _ := regexp.MatchString(pattern, phoneNumber)
-// 	return matched
-// }
+type RegisterRequest struct {
+	FullName    string `json:"full_name" form:"full_name" validate:"required,max=100"`
+	Email       string `json:"email" form:"email" validate:"required,email,max=255"`
+	PhoneNumber string `json:"phone_number" form:"phone_number" validate:"required,phone"`
+	Password    string `json:"password" form:"password" validate:"required,min=8,max=72"`
+}
 
-func RegisterUser(c *fiber.Ctx) error {
-	var user models.User
-	if err := c.BodyParser(&user); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: err.Error(), Data: nil})
-	}
-	if user.FullName == "" || user.Password == "" || user.PhoneNumber == "" || user.Email == "" {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: "Fullname, Password, NoHP, and Email are required fields", Data: nil})
+func (h *Handler) RegisterUser(c *fiber.Ctx) error {
+	var req RegisterRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
 
-	if isEmailExists(user.Email) {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: "Email is already registered", Data: nil})
+	if h.isEmailExists(req.Email) {
+		return helper.BadRequest("auth.email_registered")
 	}
 
-	// if !isPhoneNumberValid(user.PhoneNumber) {
-	// 	return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: "Invalid phone number format", Data: nil})
-	// }
-
-	if isPhoneNumberExists(user.PhoneNumber) {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: "Phone number is already registered", Data: nil})
+	if h.isPhoneNumberExists(req.PhoneNumber) {
+		return helper.BadRequest("auth.phone_registered")
+	}
+	user := models.User{
+		FullName:    strings.TrimSpace(req.FullName),
+		Email:       strings.TrimSpace(req.Email),
+		PhoneNumber: req.PhoneNumber,
+		Password:    req.Password,
 	}
-	username := generateUsername(user.FullName)
+	username := h.generateUsername(user.FullName)
 
 	user.Role = "masyarakat"
 	user.Username = username
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
-	if user.PhotoProfile == "" {
-		user.PhotoProfile = ""
-	}
-	if user.TempatLahir == "" {
-		user.TempatLahir = ""
-	}
-	if user.TanggalLahir.IsZero() {
-		user.TanggalLahir = time.Time{}
-	}
-	if user.JenisKelamin == "" {
-		user.JenisKelamin = ""
-	}
 
 	hashedPassword, err := hashPassword(user.Password)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to hash password", Data: nil})
+		return helper.InternalError("password.hash_failed")
 	}
 	user.Password = hashedPassword
 
-	userID, err := saveUserToDatabase(&user)
-	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to register user", Data: nil})
+	if err := h.repos.Users.Create(&user); err != nil {
+		log.Println("Error saving user to database:", err)
+		return helper.InternalError("auth.register_failed")
 	}
 
-	user.ID = uint(userID)
+	for _, channel := range []string{models.OTPChannelEmail, models.OTPChannelPhone} {
+		if err := h.issueOTP(user, channel); err != nil {
+			log.Printf("Error sending %s OTP for user %d: %v\n", channel, user.ID, err)
+		}
+	}
 
-	return c.Status(http.StatusOK).JSON(Response{
-		Success: 200,
-		Message: "User registered successfully",
+	return c.Status(http.StatusOK).JSON(helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "auth.registered"),
 		Data:    user})
 }
 
-func isEmailExists(email string) bool {
-	db := database.GetDBInstance()
-	var count int
-	row := db.QueryRow("SELECT COUNT(*) FROM users WHERE email = ?", email)
-	row.Scan(&count)
-	return count > 0
+func (h *Handler) isEmailExists(email string) bool {
+	exists, err := h.repos.Users.EmailExists(email)
+	if err != nil {
+		log.Println("Error checking email:", err)
+	}
+	return exists
 }
 
-func isPhoneNumberExists(phoneNumber string) bool {
-	db := database.GetDBInstance()
-	var count int
-	row := db.QueryRow("SELECT COUNT(*) FROM users WHERE phone_number = ?", phoneNumber)
-	row.Scan(&count)
-	return count > 0
+func (h *Handler) isPhoneNumberExists(phoneNumber string) bool {
+	exists, err := h.repos.Users.PhoneNumberExists(phoneNumber)
+	if err != nil {
+		log.Println("Error checking phone number:", err)
+	}
+	return exists
 }
 
 func hashPassword(password string) (string, error) {
@@ -117,30 +101,8 @@ func hashPassword(password string) (string, error) {
 	return string(hashedPassword), nil
 }
 
-func saveUserToDatabase(user *models.User) (int64, error) {
-	db := database.GetDBInstance()
-
-	query := "INSERT INTO users (role, full_name, username, photo_profile, phone_number, email, password, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
-	result, err := db.Exec(query, user.Role, user.FullName, user.Username, user.PhotoProfile, user.PhoneNumber, user.Email, user.Password, user.CreatedAt, user.UpdatedAt)
-
-	if err != nil {
-		log.Println("Error saving user to database:", err)
-		return 0, err
-	}
-
-	userID, err := result.LastInsertId()
-	if err != nil {
-		log.Println("Error getting last inserted ID:", err)
-		return 0, err
-	}
-
-	log.Printf("User saved successfully to database with ID: %d\n", userID)
-	return userID, nil
-}
-
-func generateUsername(fullName string) string {
+func (h *Handler) generateUsername(fullName string) string {
 	var username string
-	db := database.GetDBInstance()
 
 	names := strings.Fields(fullName)
 	firstName := names[0]
@@ -149,81 +111,99 @@ func generateUsername(fullName string) string {
 		rand.Seed(time.Now().UnixNano())
 		randomNumber := rand.Intn(90000000) + 10000000
 		username = fmt.Sprintf("%s%d", strings.Title(strings.ToLower(firstName)), randomNumber)
-		if !isUsernameExists(db, username) {
+		if !h.isUsernameExists(username) {
 			break
 		}
 	}
 	return username
 }
 
-func isUsernameExists(db *sql.DB, username string) bool {
-	var count int
-	row := db.QueryRow("SELECT COUNT(*) FROM users WHERE username = ?", username)
-	row.Scan(&count)
-	return count > 0
+func (h *Handler) isUsernameExists(username string) bool {
+	exists, err := h.repos.Users.UsernameExists(username)
+	if err != nil {
+		log.Println("Error checking username:", err)
+	}
+	return exists
 }
 
 /*||============================== LOGIN =================================== ||*/
-func LoginUser(c *fiber.Ctx) error {
+func (h *Handler) LoginUser(c *fiber.Ctx) error {
 	var credentials models.LoginCredentials
 	if err := c.BodyParser(&credentials); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: err.Error(), Data: nil, UserID: 0})
+		return helper.BadRequest("common.invalid_request_body")
 	}
 
-	user, err := getUserByCredentials(credentials)
-	if err != nil {
-		return c.Status(http.StatusUnauthorized).JSON(Response{Success: 0, Message: "Email atau Username, oataur Phone Number or password salah", Data: nil, UserID: 0})
+	identifier := loginIdentifier(credentials)
+	if wait := h.ipRetryAfter(c.IP()); wait > 0 {
+		h.recordLoginAttempt(c, nil, identifier, false, loginReasonThrottled)
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(wait)))
+		return helper.TooManyRequests("auth.login_throttled")
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(credentials.Password))
+	user, err := h.repos.Users.FindByLogin(credentials.Email, credentials.Username, credentials.PhoneNumber)
 	if err != nil {
-		return c.Status(http.StatusUnauthorized).JSON(Response{Success: 0, Message: "Email atau Username, oataur Phone Number or password salah", Data: nil, UserID: 0})
+		h.recordLoginAttempt(c, nil, identifier, false, loginReasonUnknownAccount)
+		return helper.NewError(http.StatusUnauthorized, helper.CodeInvalidCredentials, "auth.invalid_credentials")
 	}
 
-	// Tidak ada pemanggilan fungsi VerifyToken di sini
+	if err := h.checkAccountLogin(c, user, identifier); err != nil {
+		return err
+	}
 
-	token, err := generateAuthToken(int64(user.ID), user.Role)
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(credentials.Password))
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to generate token", Data: nil, UserID: 0})
+		h.lockIfExceeded(user.ID)
+		h.recordLoginAttempt(c, &user.ID, identifier, false, loginReasonWrongPassword)
+		return helper.NewError(http.StatusUnauthorized, helper.CodeInvalidCredentials, "auth.invalid_credentials")
 	}
+	// Setelah password benar, respons login memakai bahasa pilihan user.
+	i18n.SetLocale(c, user.Locale)
 
-	fullUser, err := getUserByID(int(user.ID))
-	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to fetch user details", Data: nil, UserID: 0})
+	// Password benar tapi login belum selesai, jadi klaim percobaan ini
+	// dibatalkan. Counter tidak di-reset supaya kegagalan 2FA tetap terhitung.
+	if user.IsSuspended || user.TwoFactorEnabled {
+		h.releaseLoginAttempt(user.ID)
 	}
 
-	return c.Status(http.StatusOK).JSON(Response{Success: 1, Message: "Anda Berhasil Login", Data: fullUser, Token: token})
-}
+	if user.IsSuspended {
+		h.recordLoginAttempt(c, &user.ID, identifier, false, loginReasonSuspended)
+		return helper.NewError(http.StatusForbidden, helper.CodeAccountSuspended, "auth.account_suspended")
+	}
 
-func getUserByCredentials(credentials models.LoginCredentials) (models.User, error) {
-	db := database.GetDBInstance()
+	// JWT baru diberikan setelah langkah kedua di LoginTwoFactor berhasil.
+	if user.TwoFactorEnabled {
+		challengeToken, err := h.generateTwoFactorChallengeToken(int64(user.ID))
+		if err != nil {
+			return helper.InternalError("auth.token_failed")
+		}
+		return c.Status(http.StatusOK).JSON(helper.Response{Code: http.StatusOK, Status: "success", Message: i18n.T(c, "auth.two_factor_code_required"), Data: fiber.Map{
+			"two_factor_required": true,
+			"challenge_token":     challengeToken,
+		}})
+	}
 
-	var user models.User
-	query := "SELECT id, username, email, phone_number, role, password FROM users WHERE email = ? OR username = ? OR phone_number = ?"
-	err := db.QueryRow(query, credentials.Email, credentials.Username, credentials.PhoneNumber).Scan(&user.ID, &user.Username, &user.Email, &user.PhoneNumber, &user.Role, &user.Password)
+	// Tidak ada pemanggilan fungsi VerifyToken di sini
+	h.resetFailedLogins(user.ID)
+	h.recordLoginAttempt(c, &user.ID, identifier, true, loginReasonSuccess)
 
+	token, err := h.generateAuthToken(int64(user.ID), user.Role)
 	if err != nil {
-		log.Println("Error getting user by credentials:", err)
-		return models.User{}, err
+		return helper.InternalError("auth.token_failed")
 	}
-	return user, nil
+
+	return c.Status(http.StatusOK).JSON(helper.Response{Code: http.StatusOK, Status: "success", Message: i18n.T(c, "auth.login_success"), Data: fiber.Map{"token": token, "user": user}})
 }
 
-func generateAuthToken(userID int64, role string) (string, error) {
-	error := godotenv.Load()
-	if error != nil {
-		panic("Cannot Find ENV file")
-	}
-	expirationTime := time.Now().Add(10 * time.Hour)
+func (h *Handler) generateAuthToken(userID int64, role string) (string, error) {
+	expirationTime := time.Now().Add(h.jwt.TokenTTL())
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"exp":     expirationTime.Unix(),
 		"role":    role,
 	}
 
-	jwt_secreet := os.Getenv("JWT_SECRET_KEY")
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(jwt_secreet))
+	signedToken, err := token.SignedString(h.jwt.Secret())
 
 	if err != nil {
 		log.Println("Error generating JWT token:", err)
@@ -232,16 +212,3 @@ func generateAuthToken(userID int64, role string) (string, error) {
 
 	return signedToken, nil
 }
-
-func getUserByID(userID int) (models.User, error) {
-	db := database.GetDBInstance()
-
-	var user models.User
-	query := "SELECT id, full_name, username, role, photo_profile, phone_number, email, password, created_at, updated_at FROM users WHERE id = ?"
-	err := db.QueryRowContext(context.Background(), query, userID).Scan(&user.ID, &user.FullName, &user.Username, &user.Role, &user.PhotoProfile, &user.PhoneNumber, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt)
-	if err != nil {
-		log.Println("Error getting user by ID:", err)
-		return models.User{}, err
-	}
-	return user, nil
-}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SelesaikanJanjiTemuRequest: kategori_hasil wajib jika masyarakat hadir,
+// tindak_lanjut wajib jika perlu_tindak_lanjut bernilai true.
+type SelesaikanJanjiTemuRequest struct {
+	Kehadiran         string `json:"kehadiran" form:"kehadiran" validate:"required,oneof=hadir|tidak_hadir"`
+	KategoriHasil     string `json:"kategori_hasil" form:"kategori_hasil" validate:"oneof=tertangani|pendampingan_lanjutan|rujukan|pelaporan_kasus|lainnya"`
+	Catatan           string `json:"catatan" form:"catatan" validate:"max=5000"`
+	PerluTindakLanjut string `json:"perlu_tindak_lanjut" form:"perlu_tindak_lanjut" validate:"oneof=true|false"`
+	TindakLanjut      string `json:"tindak_lanjut" form:"tindak_lanjut" validate:"max=2000"`
+	NoRegistrasi      string `json:"no_registrasi" form:"no_registrasi" validate:"max=191"`
+}
+
+// findJanjiTemuKonselor memuat janji temu untuk catatan konsultasi. Konselor
+// hanya boleh mengakses janji temu yang ia tangani; staff lain dengan izin
+// catatan konsultasi boleh mengakses semuanya.
+func (h *Handler) findJanjiTemuKonselor(c *fiber.Ctx) (models.JanjiTemu, error) {
+	janjiTemu, err := h.repos.JanjiTemu.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return janjiTemu, helper.NotFound("janji_temu.not_found")
+	}
+	caller, _ := c.Locals("user").(models.User)
+	if caller.Role == models.RoleKonselor && (janjiTemu.KonselorID == nil || *janjiTemu.KonselorID != caller.ID) {
+		return janjiTemu, helper.Forbidden("catatan_konsultasi.forbidden")
+	}
+	return janjiTemu, nil
+}
+
+// AdminSelesaikanJanjiTemu mencatat kehadiran dan hasil konsultasi setelah
+// waktu janji temu dimulai. Janji temu hanya bisa diselesaikan sekali.
+func (h *Handler) AdminSelesaikanJanjiTemu(c *fiber.Ctx) error {
+	var req SelesaikanJanjiTemuRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	if req.Kehadiran == models.KehadiranHadir && req.KategoriHasil == "" {
+		return helper.ValidationFailed(requiredField("kategori_hasil"))
+	}
+	perluTindakLanjut := req.PerluTindakLanjut == "true"
+	if perluTindakLanjut && req.TindakLanjut == "" {
+		return helper.ValidationFailed(requiredField("tindak_lanjut"))
+	}
+
+	janjiTemu, err := h.findJanjiTemuKonselor(c)
+	if err != nil {
+		return err
+	}
+	status := models.JanjiTemuSelesai
+	if req.Kehadiran == models.KehadiranTidakHadir {
+		status = models.JanjiTemuTidakHadir
+	}
+	if !janjiTemu.BisaMenjadi(status) {
+		return errTransisi(janjiTemu, status)
+	}
+	if time.Now().UTC().Before(janjiTemu.WaktuDimulai) {
+		return helper.Conflict("catatan_konsultasi.not_started")
+	}
+
+	// Catatan milik konselor yang menangani janji temu, walaupun yang
+	// menyelesaikannya admin atau supervisor.
+	caller, _ := c.Locals("user").(models.User)
+	konselorID := caller.ID
+	if janjiTemu.KonselorID != nil {
+		konselorID = *janjiTemu.KonselorID
+	}
+	catatan := models.CatatanKonsultasi{
+		JanjiTemuID:       janjiTemu.ID,
+		KonselorID:        konselorID,
+		DibuatOlehID:      caller.ID,
+		Kehadiran:         req.Kehadiran,
+		Catatan:           req.Catatan,
+		PerluTindakLanjut: perluTindakLanjut,
+		TindakLanjut:      req.TindakLanjut,
+	}
+	if req.Kehadiran == models.KehadiranHadir {
+		catatan.KategoriHasil = req.KategoriHasil
+	}
+	if req.NoRegistrasi != "" {
+		if _, err := h.repos.Laporan.FindByNoRegistrasi(req.NoRegistrasi); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return helper.NotFound("laporan.not_found")
+			}
+			return helper.InternalError("laporan.retrieve_failed")
+		}
+		catatan.NoRegistrasi = &req.NoRegistrasi
+	}
+
+	janjiTemu.Status = status
+	if err := h.repos.JanjiTemu.Complete(&janjiTemu, &catatan); err != nil {
+		return helper.InternalError("catatan_konsultasi.save_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "catatan_konsultasi.saved"),
+		Data:    catatan,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) AdminGetCatatanKonsultasi(c *fiber.Ctx) error {
+	janjiTemu, err := h.findJanjiTemuKonselor(c)
+	if err != nil {
+		return err
+	}
+	catatan, err := h.repos.JanjiTemu.FindCatatan(janjiTemu.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("catatan_konsultasi.not_found")
+		}
+		return helper.InternalError("catatan_konsultasi.retrieve_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "catatan_konsultasi.detail"),
+		Data:    catatan,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
@@ -1,2 +1,195 @@
 package handlers
 
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/mail"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/sms"
+	"backend-pedika-fiber/validation"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	otpLength         = 6
+	otpTTL            = 10 * time.Minute
+	otpMaxAttempts    = 5
+	otpResendCooldown = 60 * time.Second
+)
+
+var errOTPCooldown = errors.New("otp resend cooldown")
+
+type SendOTPRequest struct {
+	Channel string `json:"channel" form:"channel" validate:"required,oneof=email|phone"`
+	Target  string `json:"target" form:"target" validate:"required,max=255"`
+}
+
+type VerifyOTPRequest struct {
+	Channel string `json:"channel" form:"channel" validate:"required,oneof=email|phone"`
+	Target  string `json:"target" form:"target" validate:"required,max=255"`
+	Code    string `json:"code" form:"code" validate:"required,len=6,digits"`
+}
+
+/*=========================== KIRIM ULANG OTP =======================*/
+// SendOTP selalu menjawab dengan pesan yang sama, baik target terdaftar,
+// sudah terverifikasi, masih cooldown, maupun tidak ada, supaya endpoint ini
+// tidak bisa dipakai untuk mengecek email atau nomor mana yang punya akun.
+// Pengiriman berjalan di background agar waktu respons juga tidak berbeda.
+func (h *Handler) SendOTP(c *fiber.Ctx) error {
+	var req SendOTPRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+
+	if user, err := h.findUserForOTP(req.Channel, req.Target); err == nil && !isChannelVerified(user, req.Channel) {
+		go h.kirimUlangOTP(user, req.Channel)
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "otp.sent"),
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// kirimUlangOTP menjalankan issueOTP di luar request. Cooldown bukan
+// kesalahan, sehingga hanya kegagalan lain yang dicatat di log.
+func (h *Handler) kirimUlangOTP(user models.User, channel string) {
+	if err := h.issueOTP(user, channel); err != nil && !errors.Is(err, errOTPCooldown) {
+		log.Println("Error sending OTP:", err)
+	}
+}
+
+/*=========================== VERIFIKASI OTP =======================*/
+// VerifyOTP menjawab setiap kegagalan dengan otp.invalid, termasuk target
+// yang tidak terdaftar atau sudah terverifikasi, karena alasan yang sama
+// dengan SendOTP.
+func (h *Handler) VerifyOTP(c *fiber.Ctx) error {
+	var req VerifyOTPRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	errInvalid := helper.NewError(http.StatusBadRequest, helper.CodeOTPInvalid, "otp.invalid")
+
+	user, err := h.findUserForOTP(req.Channel, req.Target)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errInvalid
+		}
+		return helper.InternalError("otp.verify_failed")
+	}
+
+	otp, err := h.repos.OTP.FindActive(user.ID, req.Channel, req.Target)
+	if err != nil {
+		return errInvalid
+	}
+	if time.Now().After(otp.ExpiresAt) {
+		return errInvalid
+	}
+	// Percobaan diklaim sebelum kode dibandingkan supaya request paralel
+	// tidak bisa menebak lebih dari otpMaxAttempts kali.
+	claimed, err := h.repos.OTP.ClaimAttempt(otp.ID, otpMaxAttempts)
+	if err != nil {
+		return helper.InternalError("otp.verify_failed")
+	}
+	if !claimed {
+		return errInvalid
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(otp.CodeHash), []byte(req.Code)); err != nil {
+		return errInvalid
+	}
+
+	if err := h.repos.OTP.Consume(otp, time.Now()); err != nil {
+		return helper.InternalError("otp.verify_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "otp.verified"),
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// issueOTP membuat kode OTP baru untuk channel tertentu dan mengirimkannya.
+// Kode lama yang belum dipakai otomatis tidak berlaku karena verifikasi
+// selalu memakai kode terbaru.
+func (h *Handler) issueOTP(user models.User, channel string) error {
+	target := user.Email
+	if channel == models.OTPChannelPhone {
+		target = user.PhoneNumber
+	}
+
+	if last, err := h.repos.OTP.Latest(user.ID, channel); err == nil {
+		if time.Since(last.CreatedAt) < otpResendCooldown {
+			return errOTPCooldown
+		}
+	}
+
+	code, err := generateOTPCode()
+	if err != nil {
+		return err
+	}
+	codeHash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	otp := models.OTPCode{
+		UserID:    user.ID,
+		Channel:   channel,
+		Target:    target,
+		CodeHash:  string(codeHash),
+		ExpiresAt: time.Now().Add(otpTTL),
+		CreatedAt: time.Now(),
+	}
+	if err := h.repos.OTP.Create(&otp); err != nil {
+		return err
+	}
+
+	// OTP bisa dikirim di luar request user (misalnya saat admin membuat akun),
+	// jadi bahasanya mengikuti preferensi yang tersimpan di akun.
+	body := i18n.Translate(user.Locale, "otp.message_body", code, int(otpTTL.Minutes()))
+	if channel == models.OTPChannelPhone {
+		return h.sms.Send(sms.Message{To: target, Body: body})
+	}
+	return h.mail.Send(mail.Message{To: target, Subject: i18n.Translate(user.Locale, "otp.email_subject"), Body: body})
+}
+
+func generateOTPCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < otpLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", otpLength, n), nil
+}
+
+func (h *Handler) findUserForOTP(channel, target string) (models.User, error) {
+	if channel == models.OTPChannelPhone {
+		return h.repos.Users.FindByPhoneNumber(target)
+	}
+	return h.repos.Users.FindByEmail(target)
+}
+
+func isChannelVerified(user models.User, channel string) bool {
+	if channel == models.OTPChannelPhone {
+		return user.PhoneVerifiedAt != nil
+	}
+	return user.EmailVerifiedAt != nil
+}
@@ -1,47 +1,52 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
-	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/validation"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func GetEmergencyContact(c *fiber.Ctx) error {
-	var emergencyContact models.EmergencyContact
-	if err := database.DB.First(&emergencyContact).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to get emergency contact", Data: nil})
+func (h *Handler) GetEmergencyContact(c *fiber.Ctx) error {
+	emergencyContact, err := h.repos.EmergencyContacts.First()
+	if err != nil {
+		return helper.InternalError("emergency_contact.retrieve_failed")
 	}
-	return c.Status(http.StatusOK).JSON(Response{Success: 1, Message: "Emergency contact retrieved successfully", Data: emergencyContact})
+	return c.Status(http.StatusOK).JSON(helper.Response{Code: http.StatusOK, Status: "success", Message: i18n.T(c, "emergency_contact.retrieved"), Data: emergencyContact})
 }
 
-func UpdateEmergencyContact(c *fiber.Ctx) error {
-	var updatedEmergencyContact models.EmergencyContact
-	if err := c.BodyParser(&updatedEmergencyContact); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: "Invalid request body", Data: nil})
+// Nomor darurat bisa berupa layanan pendek seperti 129, jadi tidak memakai
+// aturan phone yang khusus nomor seluler.
+type EmergencyContactRequest struct {
+	Phone string `json:"phone" form:"phone" validate:"required,max=20"`
+}
+
+func (h *Handler) UpdateEmergencyContact(c *fiber.Ctx) error {
+	var req EmergencyContactRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
 
-	var existingEmergencyContact models.EmergencyContact
-	if err := database.DB.First(&existingEmergencyContact).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(Response{Success: 0, Message: "Kontak Darurat Tidak ditemukan", Data: nil})
+	existingEmergencyContact, err := h.repos.EmergencyContacts.First()
+	if err != nil {
+		return helper.NotFound("emergency_contact.not_found")
 	}
 
-	existingEmergencyContact.Phone = updatedEmergencyContact.Phone
-	if err := database.DB.Save(&existingEmergencyContact).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Gagal Mengupdate Kontak Darurat", Data: nil})
+	existingEmergencyContact.Phone = req.Phone
+	if err := h.repos.EmergencyContacts.Save(&existingEmergencyContact); err != nil {
+		return helper.InternalError("emergency_contact.update_failed")
 	}
 
-	return c.Status(http.StatusOK).JSON(Response{Success: 1, Message: "Berhasil Mengupdate Kontak Darurat", Data: existingEmergencyContact})
+	return c.Status(http.StatusOK).JSON(helper.Response{Code: http.StatusOK, Status: "success", Message: i18n.T(c, "emergency_contact.updated"), Data: existingEmergencyContact})
 }
 
-func ShowEmergencyContactByID(c *fiber.Ctx) error {
-	id := c.Params("id")
-
-	var emergencyContact models.EmergencyContact
-	if err := database.DB.First(&emergencyContact, id).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(Response{Success: 0, Message: "Emergency contact not found", Data: nil})
+func (h *Handler) ShowEmergencyContactByID(c *fiber.Ctx) error {
+	emergencyContact, err := h.repos.EmergencyContacts.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("emergency_contact.not_found")
 	}
 
-	return c.Status(http.StatusOK).JSON(Response{Success: 1, Message: "Emergency contact retrieved successfully", Data: emergencyContact})
+	return c.Status(http.StatusOK).JSON(helper.Response{Code: http.StatusOK, Status: "success", Message: i18n.T(c, "emergency_contact.retrieved"), Data: emergencyContact})
 }
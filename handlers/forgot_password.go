@@ -1,64 +1,82 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/mail"
 	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
-	"net/smtp"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type ForgotPasswordRequest struct {
-	Email string `json:"email"`
+	Email string `json:"email" validate:"required,email"`
 }
 
 type ResetPasswordRequest struct {
-	Token           string `json:"token"`
-	NewPassword     string `json:"new_password"`
-	ConfirmPassword string `json:"confirm_password"`
+	Token           string `json:"token" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=new_password"`
 }
 
-func ForgotPassword(c *fiber.Ctx) error {
+const resetTokenTTL = 1 * time.Hour
+
+// Pesan yang sama dikirim baik email terdaftar maupun tidak, supaya endpoint
+// ini tidak bisa dipakai untuk mengecek email mana yang punya akun.
+const forgotPasswordMessage = "password.reset_link_sent"
+
+func (h *Handler) ForgotPassword(c *fiber.Ctx) error {
 	var req ForgotPasswordRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: "Invalid request body", Data: nil})
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
+	email := strings.TrimSpace(req.Email)
 
-	db := database.GetGormDBInstance()
-
-	var user models.User
-	if err := db.Where("email = ?", req.Email).First(&user).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(Response{Success: 0, Message: "Email not found", Data: nil})
+	// Pembuatan token dan pengiriman email berjalan di background. Dengan
+	// begitu email terdaftar dan tidak terdaftar sama-sama langsung dijawab,
+	// dan waktu respons tidak membocorkan email mana yang punya akun.
+	if user, err := h.repos.Users.FindByEmail(email); err == nil && !user.IsSuspended {
+		go h.kirimResetPassword(user)
 	}
 
+	return c.Status(http.StatusOK).JSON(helper.Response{Code: http.StatusOK, Status: "success", Message: i18n.T(c, forgotPasswordMessage)})
+}
+
+// kirimResetPassword membuat token reset baru lalu mengirim link-nya. Fungsi
+// ini berjalan di luar request, jadi kegagalan hanya dicatat di log.
+func (h *Handler) kirimResetPassword(user models.User) {
 	token, err := generateResetToken()
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to generate reset token", Data: nil})
+		log.Println("Error generating reset token:", err)
+		return
 	}
 
+	// Token lama yang belum dipakai tidak berlaku lagi begitu token baru diminta.
 	reset := models.PasswordReset{
-		Email:     req.Email,
-		Token:     token,
-		ExpiresAt: time.Now().Add(1 * time.Hour),
+		Email:     user.Email,
+		TokenHash: hashResetToken(token),
+		ExpiresAt: time.Now().Add(resetTokenTTL),
 		CreatedAt: time.Now(),
 	}
-
-	if err := db.Create(&reset).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to save reset token", Data: nil})
+	if err := h.repos.PasswordResets.Replace(&reset); err != nil {
+		log.Println("Error saving reset token:", err)
+		return
 	}
 
-	if err := sendResetEmail(req.Email, token); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to send reset email", Data: nil})
+	if err := h.sendResetEmail(user, token); err != nil {
+		log.Println("Error sending reset email:", err)
 	}
-
-	return c.Status(http.StatusOK).JSON(Response{Success: 1, Message: "Reset email sent successfully", Data: nil})
 }
 
 func generateResetToken() (string, error) {
@@ -69,62 +87,47 @@ func generateResetToken() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-func sendResetEmail(email, token string) error {
-	from := os.Getenv("EMAIL_SENDER")
-	password := os.Getenv("EMAIL_PASSWORD")
-	to := []string{email}
-	smtpHost := os.Getenv("SMTP_HOST")
-	smtpPort := os.Getenv("SMTP_PORT")
-
-	msg := []byte(fmt.Sprintf("Subject: Password Reset\n\nClick the link to reset your password: http://localhost:3000/reset-password?token=%s", token))
-
-	auth := smtp.PlainAuth("", from, password, smtpHost)
-	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, from, to, msg)
-	if err != nil {
-		return err
-	}
-	return nil
+// Token hanya disimpan dalam bentuk hash, sehingga bocornya tabel
+// password_resets tidak bisa dipakai untuk mereset password.
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
-func ResetPassword(c *fiber.Ctx) error {
-	var req ResetPasswordRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: "Invalid request body", Data: nil})
-	}
 
-	if req.NewPassword != req.ConfirmPassword {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: "New password and confirmation password do not match", Data: nil})
-	}
+func (h *Handler) resetPasswordURL(token string) string {
+	return fmt.Sprintf("%s/reset-password?token=%s", h.cfg.FrontendURL, token)
+}
 
-	db := database.GetGormDBInstance()
+func (h *Handler) sendResetEmail(user models.User, token string) error {
+	return h.mail.Send(mail.Message{
+		To:      user.Email,
+		Subject: i18n.Translate(user.Locale, "password.reset_email_subject"),
+		Body:    i18n.Translate(user.Locale, "password.reset_email_body", h.resetPasswordURL(token), int(resetTokenTTL.Minutes())),
+	})
+}
 
-	var reset models.PasswordReset
-	if err := db.Where("token = ? AND expires_at > ?", req.Token, time.Now()).First(&reset).Error; err != nil {
-		return c.Status(http.StatusBadRequest).JSON(Response{Success: 0, Message: "Invalid or expired token", Data: nil})
+func (h *Handler) ResetPassword(c *fiber.Ctx) error {
+	var req ResetPasswordRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
 
-	var user models.User
-	if err := db.Where("email = ?", reset.Email).First(&user).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "User not found", Data: nil})
+	hashedPassword, err := HashPassword(req.NewPassword)
+	if err != nil {
+		return helper.InternalError("password.hash_failed")
 	}
 
-	hashedPassword, err := hashiPassword(req.NewPassword)
+	reset, err := h.repos.PasswordResets.FindValid(hashResetToken(req.Token), time.Now())
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to hash new password", Data: nil})
+		return helper.BadRequest("password.reset_token_invalid")
 	}
 
-	if err := db.Model(&user).Update("password", hashedPassword).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to update password", Data: nil})
+	if err := h.repos.PasswordResets.Consume(reset, hashedPassword, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.BadRequest("password.reset_token_invalid")
+		}
+		return helper.InternalError("password.update_failed")
 	}
 
-	db.Delete(&reset)
-
-	return c.Status(http.StatusOK).JSON(Response{Success: 1, Message: "Password reset successfully", Data: nil})
-}
-
-func hashiPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
-	}
-	return string(hashedPassword), nil
+	return c.Status(http.StatusOK).JSON(helper.Response{Code: http.StatusOK, Status: "success", Message: i18n.T(c, "password.reset_success")})
 }
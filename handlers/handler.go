@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/config"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/mail"
+	"backend-pedika-fiber/pertemuan"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/sms"
+	"strconv"
+)
+
+// Handler menyimpan semua dependency yang dibutuhkan endpoint. Handler tidak
+// memakai koneksi database atau layanan luar yang global, sehingga bisa dibuat
+// dengan repository dan implementasi apa saja (GORM ke MySQL atau SQLite dan
+// sender palsu untuk test).
+type Handler struct {
+	cfg       *config.Config
+	repos     *repository.Repositories
+	jwt       auth.JWT
+	mail      mail.Sender
+	sms       sms.Sender
+	uploader  helper.Uploader
+	pertemuan pertemuan.Provider
+}
+
+// Deps adalah layanan luar yang dipakai handler. main mengisinya dari config,
+// sedangkan test memakai FakeSender, FakeUploader dan FakeProvider.
+type Deps struct {
+	JWT       auth.JWT
+	Mail      mail.Sender
+	SMS       sms.Sender
+	Uploader  helper.Uploader
+	Pertemuan pertemuan.Provider
+}
+
+func New(cfg *config.Config, repos *repository.Repositories, deps Deps) *Handler {
+	return &Handler{
+		cfg:       cfg,
+		repos:     repos,
+		jwt:       deps.JWT,
+		mail:      deps.Mail,
+		sms:       deps.SMS,
+		uploader:  deps.Uploader,
+		pertemuan: deps.Pertemuan,
+	}
+}
+
+// parseID mengubah parameter id dari URL. ID yang tidak valid menjadi 0,
+// yang tidak pernah ada di database sehingga berujung not found.
+func parseID(value string) uint {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/jadwal"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	slotRentangDefault  = 7
+	slotRentangMaksimal = 31
+)
+
+type CreateJadwalKonselorRequest struct {
+	Hari        string `json:"hari" form:"hari" validate:"required,int=0:6"`
+	JamMulai    string `json:"jam_mulai" form:"jam_mulai" validate:"required,time"`
+	JamSelesai  string `json:"jam_selesai" form:"jam_selesai" validate:"required,time,after=jam_mulai"`
+	DurasiMenit string `json:"durasi_menit" form:"durasi_menit" validate:"int=15:240"`
+}
+
+// CreatePengecualianJadwalRequest: jam_mulai dan jam_selesai diisi keduanya
+// untuk menutup sebagian hari, atau dikosongkan untuk menutup seharian.
+type CreatePengecualianJadwalRequest struct {
+	Tanggal    string `json:"tanggal" form:"tanggal" validate:"required,date"`
+	JamMulai   string `json:"jam_mulai" form:"jam_mulai" validate:"time"`
+	JamSelesai string `json:"jam_selesai" form:"jam_selesai" validate:"time,after=jam_mulai"`
+	Keterangan string `json:"keterangan" form:"keterangan" validate:"max=255"`
+}
+
+type SlotKonselorQuery struct {
+	KonselorID string `query:"konselor_id" form:"konselor_id" validate:"int"`
+	Dari       string `query:"dari" form:"dari" validate:"date"`
+	Sampai     string `query:"sampai" form:"sampai" validate:"date"`
+}
+
+// findKonselor memuat konselor dari parameter :konselor_id. Konselor hanya
+// boleh mengatur jadwalnya sendiri, sedangkan admin boleh mengatur jadwal
+// semua konselor.
+func (h *Handler) findKonselor(c *fiber.Ctx) (models.User, error) {
+	konselor, err := h.repos.Users.FindByID(parseID(c.Params("konselor_id")))
+	if err != nil || konselor.Role != models.RoleKonselor {
+		return konselor, helper.NotFound("konselor.not_found")
+	}
+	caller, _ := c.Locals("user").(models.User)
+	if caller.Role == models.RoleKonselor && caller.ID != konselor.ID {
+		return konselor, helper.Forbidden("jadwal.forbidden")
+	}
+	return konselor, nil
+}
+
+func (h *Handler) AdminGetJadwalKonselor(c *fiber.Ctx) error {
+	konselor, err := h.findKonselor(c)
+	if err != nil {
+		return err
+	}
+	jadwalMingguan, err := h.repos.JadwalKonselor.ListJadwal(konselor.ID)
+	if err != nil {
+		return helper.InternalError("jadwal.retrieve_failed")
+	}
+	pengecualian, err := h.repos.JadwalKonselor.ListPengecualian(konselor.ID, today(), time.Time{})
+	if err != nil {
+		return helper.InternalError("jadwal.retrieve_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "jadwal.detail"),
+		Data: fiber.Map{
+			"konselor_id":  konselor.ID,
+			"jadwal":       jadwalMingguan,
+			"pengecualian": pengecualian,
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) AdminCreateJadwalKonselor(c *fiber.Ctx) error {
+	konselor, err := h.findKonselor(c)
+	if err != nil {
+		return err
+	}
+	var req CreateJadwalKonselorRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	hari, _ := strconv.Atoi(req.Hari)
+	durasi := 60
+	if req.DurasiMenit != "" {
+		durasi, _ = strconv.Atoi(req.DurasiMenit)
+	}
+
+	jadwalMingguan, err := h.repos.JadwalKonselor.ListJadwal(konselor.ID)
+	if err != nil {
+		return helper.InternalError("jadwal.retrieve_failed")
+	}
+	mulai, _ := validation.ParseTime(req.JamMulai)
+	selesai, _ := validation.ParseTime(req.JamSelesai)
+	for _, j := range jadwalMingguan {
+		jMulai, _ := validation.ParseTime(j.JamMulai)
+		jSelesai, _ := validation.ParseTime(j.JamSelesai)
+		if j.Hari == hari && jadwal.Bertabrakan(mulai, selesai, jMulai, jSelesai) {
+			return helper.Conflict("jadwal.overlap")
+		}
+	}
+
+	baru := models.JadwalKonselor{
+		KonselorID:  konselor.ID,
+		Hari:        hari,
+		JamMulai:    mulai.Format(validation.TimeLayout),
+		JamSelesai:  selesai.Format(validation.TimeLayout),
+		DurasiMenit: durasi,
+	}
+	if err := h.repos.JadwalKonselor.CreateJadwal(&baru); err != nil {
+		return helper.InternalError("jadwal.create_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: i18n.T(c, "jadwal.created"),
+		Data:    baru,
+	}
+	return c.Status(http.StatusCreated).JSON(response)
+}
+
+func (h *Handler) AdminDeleteJadwalKonselor(c *fiber.Ctx) error {
+	konselor, err := h.findKonselor(c)
+	if err != nil {
+		return err
+	}
+	j, err := h.repos.JadwalKonselor.FindJadwal(parseID(c.Params("id")))
+	if err != nil || j.KonselorID != konselor.ID {
+		return helper.NotFound("jadwal.not_found")
+	}
+	// Janji temu yang sudah dipesan tetap berlaku; hanya slot baru yang hilang.
+	if err := h.repos.JadwalKonselor.DeleteJadwal(&j); err != nil {
+		return helper.InternalError("jadwal.delete_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "jadwal.deleted"),
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) AdminCreatePengecualianJadwal(c *fiber.Ctx) error {
+	konselor, err := h.findKonselor(c)
+	if err != nil {
+		return err
+	}
+	var req CreatePengecualianJadwalRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	if req.JamMulai != "" && req.JamSelesai == "" {
+		return helper.ValidationFailed(requiredField("jam_selesai"))
+	}
+	if req.JamMulai == "" && req.JamSelesai != "" {
+		return helper.ValidationFailed(requiredField("jam_mulai"))
+	}
+
+	tanggal, _ := validation.ParseDate(req.Tanggal)
+	pengecualian := models.PengecualianJadwal{
+		KonselorID: konselor.ID,
+		Tanggal:    tanggal,
+		JamMulai:   req.JamMulai,
+		JamSelesai: req.JamSelesai,
+		Keterangan: req.Keterangan,
+	}
+	if err := h.repos.JadwalKonselor.CreatePengecualian(&pengecualian); err != nil {
+		return helper.InternalError("jadwal.exception_create_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: i18n.T(c, "jadwal.exception_created"),
+		Data:    pengecualian,
+	}
+	return c.Status(http.StatusCreated).JSON(response)
+}
+
+func (h *Handler) AdminDeletePengecualianJadwal(c *fiber.Ctx) error {
+	konselor, err := h.findKonselor(c)
+	if err != nil {
+		return err
+	}
+	pengecualian, err := h.repos.JadwalKonselor.FindPengecualian(parseID(c.Params("id")))
+	if err != nil || pengecualian.KonselorID != konselor.ID {
+		return helper.NotFound("jadwal.exception_not_found")
+	}
+	if err := h.repos.JadwalKonselor.DeletePengecualian(&pengecualian); err != nil {
+		return helper.InternalError("jadwal.exception_delete_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "jadwal.exception_deleted"),
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// GetSlotKonselor menampilkan slot kosong mulai hari ini atau tanggal dari
+// sampai tanggal sampai (inklusif), untuk satu konselor atau semua konselor.
+func (h *Handler) GetSlotKonselor(c *fiber.Ctx) error {
+	var query SlotKonselorQuery
+	if err := c.QueryParser(&query); err != nil {
+		return helper.BadRequest("common.invalid_request_body")
+	}
+	if err := validation.Validate(&query); err != nil {
+		return err
+	}
+
+	dari := today()
+	if query.Dari != "" {
+		dari, _ = validation.ParseDate(query.Dari)
+	}
+	sampai := dari.AddDate(0, 0, slotRentangDefault)
+	if query.Sampai != "" {
+		sampai, _ = validation.ParseDate(query.Sampai)
+		sampai = sampai.AddDate(0, 0, 1)
+	}
+	if sampai.Sub(dari) > slotRentangMaksimal*24*time.Hour {
+		return helper.BadRequest("jadwal.range_too_long").WithArgs(slotRentangMaksimal)
+	}
+	// Slot yang sudah lewat tidak bisa dipesan lagi.
+	if now := time.Now().UTC(); dari.Before(now) {
+		dari = now
+	}
+
+	konselorID, _ := strconv.Atoi(query.KonselorID)
+	slots, err := h.slotKosong(uint(konselorID), dari, sampai)
+	if err != nil {
+		return helper.InternalError("jadwal.slots_failed")
+	}
+	if slots == nil {
+		slots = []jadwal.Slot{}
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "jadwal.slots"),
+		Data:    slots,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// slotKosong menghitung slot kosong konselor (0 = semua konselor aktif) yang
+// dimulai dalam rentang [dari, sampai).
+func (h *Handler) slotKosong(konselorID uint, dari, sampai time.Time) ([]jadwal.Slot, error) {
+	semua, err := h.repos.JadwalKonselor.ListJadwal(konselorID)
+	if err != nil {
+		return nil, err
+	}
+	// Akun yang dinonaktifkan atau sudah bukan konselor tidak menerima booking.
+	var jadwalAktif []models.JadwalKonselor
+	for _, j := range semua {
+		if j.Konselor.Role == models.RoleKonselor && !j.Konselor.IsSuspended {
+			jadwalAktif = append(jadwalAktif, j)
+		}
+	}
+	pengecualian, err := h.repos.JadwalKonselor.ListPengecualian(konselorID, jadwalTanggal(dari), sampai)
+	if err != nil {
+		return nil, err
+	}
+	terpakai, err := h.repos.JanjiTemu.ListAktifByKonselor(konselorID, dari, sampai)
+	if err != nil {
+		return nil, err
+	}
+	return jadwal.SlotKosong(jadwalAktif, pengecualian, terpakai, dari, sampai), nil
+}
+
+func requiredField(name string) helper.FieldError {
+	return helper.FieldError{Field: name, Code: "required", Message: "validation.required", Args: []interface{}{name}}
+}
+
+// today mengembalikan tanggal hari ini dalam waktu yang sama dengan
+// WaktuDimulai janji temu.
+func today() time.Time {
+	return jadwalTanggal(time.Now().UTC())
+}
+
+func jadwalTanggal(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UsulanJadwalRequest dipakai untuk usulan jadwal ulang maupun usulan
+// tandingan.
+type UsulanJadwalRequest struct {
+	WaktuDimulai string `json:"waktu_dimulai" form:"waktu_dimulai" validate:"required,datetime,future"`
+	WaktuSelesai string `json:"waktu_selesai" form:"waktu_selesai" validate:"required,datetime,after=waktu_dimulai"`
+	Alasan       string `json:"alasan" form:"alasan" validate:"max=500"`
+}
+
+// findJanjiTemuMilik memuat janji temu dari parameter :id yang dibuat oleh
+// user yang login. Janji temu milik orang lain dianggap tidak ada.
+func (h *Handler) findJanjiTemuMilik(c *fiber.Ctx) (models.JanjiTemu, uint, error) {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return models.JanjiTemu{}, 0, helper.Unauthorized("common.unauthorized")
+	}
+	janjiTemu, err := h.repos.JanjiTemu.FindByID(parseID(c.Params("id")))
+	if err != nil || janjiTemu.UserID != userID {
+		return janjiTemu, userID, helper.NotFound("janji_temu.not_found")
+	}
+	return janjiTemu, userID, nil
+}
+
+// findJanjiTemuPetugas memuat janji temu dari parameter :id untuk petugas.
+// Konselor hanya boleh mengelola janji temu yang ditanganinya sendiri.
+func (h *Handler) findJanjiTemuPetugas(c *fiber.Ctx) (models.JanjiTemu, uint, error) {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return models.JanjiTemu{}, 0, helper.Unauthorized("common.unauthorized")
+	}
+	janjiTemu, err := h.repos.JanjiTemu.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return janjiTemu, userID, helper.NotFound("janji_temu.not_found")
+	}
+	caller, _ := c.Locals("user").(models.User)
+	if caller.Role == models.RoleKonselor && (janjiTemu.KonselorID == nil || *janjiTemu.KonselorID != caller.ID) {
+		return janjiTemu, userID, helper.Forbidden("janji_temu.forbidden")
+	}
+	return janjiTemu, userID, nil
+}
+
+func errTransisi(janjiTemu models.JanjiTemu, status string) error {
+	return helper.Conflict("janji_temu.invalid_transition").WithArgs(janjiTemu.Status, status)
+}
+
+func (h *Handler) MasyarakatUsulkanJadwalUlang(c *fiber.Ctx) error {
+	janjiTemu, userID, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	return h.usulkanJadwalUlang(c, janjiTemu, userID, models.PihakMasyarakat)
+}
+
+func (h *Handler) AdminUsulkanJadwalUlang(c *fiber.Ctx) error {
+	janjiTemu, userID, err := h.findJanjiTemuPetugas(c)
+	if err != nil {
+		return err
+	}
+	return h.usulkanJadwalUlang(c, janjiTemu, userID, models.PihakPetugas)
+}
+
+func (h *Handler) MasyarakatTerimaJadwalUlang(c *fiber.Ctx) error {
+	janjiTemu, userID, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	return h.tanggapiJadwalUlang(c, janjiTemu, userID, models.PihakMasyarakat, true)
+}
+
+func (h *Handler) AdminTerimaJadwalUlang(c *fiber.Ctx) error {
+	janjiTemu, userID, err := h.findJanjiTemuPetugas(c)
+	if err != nil {
+		return err
+	}
+	return h.tanggapiJadwalUlang(c, janjiTemu, userID, models.PihakPetugas, true)
+}
+
+func (h *Handler) MasyarakatTolakJadwalUlang(c *fiber.Ctx) error {
+	janjiTemu, userID, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	return h.tanggapiJadwalUlang(c, janjiTemu, userID, models.PihakMasyarakat, false)
+}
+
+func (h *Handler) AdminTolakJadwalUlang(c *fiber.Ctx) error {
+	janjiTemu, userID, err := h.findJanjiTemuPetugas(c)
+	if err != nil {
+		return err
+	}
+	return h.tanggapiJadwalUlang(c, janjiTemu, userID, models.PihakPetugas, false)
+}
+
+func (h *Handler) MasyarakatRiwayatJadwalUlang(c *fiber.Ctx) error {
+	janjiTemu, _, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	return h.riwayatJadwalUlang(c, janjiTemu)
+}
+
+func (h *Handler) AdminRiwayatJadwalUlang(c *fiber.Ctx) error {
+	janjiTemu, _, err := h.findJanjiTemuPetugas(c)
+	if err != nil {
+		return err
+	}
+	return h.riwayatJadwalUlang(c, janjiTemu)
+}
+
+// usulkanJadwalUlang mengajukan waktu baru. Jika pihak lawan sudah
+// mengusulkan waktu yang belum ditanggapi, usulan ini menjadi usulan
+// tandingan; pihak yang sama harus menunggu tanggapan lebih dulu.
+func (h *Handler) usulkanJadwalUlang(c *fiber.Ctx, janjiTemu models.JanjiTemu, userID uint, pihak string) error {
+	var req UsulanJadwalRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+
+	statusSebelumnya := janjiTemu.Status
+	if janjiTemu.Status == models.JanjiTemuJadwalUlang {
+		menunggu, err := h.repos.JanjiTemu.FindUsulanMenunggu(janjiTemu.ID)
+		if err != nil {
+			return helper.InternalError("janji_temu.reschedule_failed")
+		}
+		if menunggu.Pihak == pihak {
+			return helper.Conflict("janji_temu.reschedule_pending")
+		}
+		statusSebelumnya = menunggu.StatusSebelumnya
+	} else if !janjiTemu.BisaMenjadi(models.JanjiTemuJadwalUlang) {
+		return errTransisi(janjiTemu, models.JanjiTemuJadwalUlang)
+	}
+
+	usulan := models.UsulanJadwal{
+		JanjiTemuID:      janjiTemu.ID,
+		DiusulkanOlehID:  userID,
+		Pihak:            pihak,
+		Alasan:           req.Alasan,
+		Status:           models.UsulanMenunggu,
+		StatusSebelumnya: statusSebelumnya,
+	}
+	usulan.WaktuDimulai, _ = validation.ParseDateTime(req.WaktuDimulai)
+	usulan.WaktuSelesai, _ = validation.ParseDateTime(req.WaktuSelesai)
+
+	// Usulan yang pasti bentrok tidak perlu ditanggapi pihak lawan.
+	calon := janjiTemu
+	calon.WaktuDimulai, calon.WaktuSelesai = usulan.WaktuDimulai, usulan.WaktuSelesai
+	if err := h.cekBentrok(calon); err != nil {
+		return err
+	}
+
+	janjiTemu.Status = models.JanjiTemuJadwalUlang
+	if err := h.repos.JanjiTemu.ProposeJadwal(&janjiTemu, &usulan); err != nil {
+		return helper.InternalError("janji_temu.reschedule_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: i18n.T(c, "janji_temu.reschedule_proposed"),
+		Data:    usulan,
+	}
+	return c.Status(http.StatusCreated).JSON(response)
+}
+
+// tanggapiJadwalUlang menerima atau menolak usulan pihak lawan. Keduanya
+// mengembalikan janji temu ke status sebelum penjadwalan ulang; bila diterima,
+// waktunya ikut diganti.
+func (h *Handler) tanggapiJadwalUlang(c *fiber.Ctx, janjiTemu models.JanjiTemu, userID uint, pihak string, terima bool) error {
+	usulan, err := h.repos.JanjiTemu.FindUsulanMenunggu(janjiTemu.ID)
+	if err != nil || janjiTemu.Status != models.JanjiTemuJadwalUlang {
+		return helper.NotFound("janji_temu.reschedule_not_found")
+	}
+	if usulan.Pihak == pihak {
+		return helper.Forbidden("janji_temu.reschedule_own_proposal")
+	}
+	if !janjiTemu.BisaMenjadi(usulan.StatusSebelumnya) {
+		return errTransisi(janjiTemu, usulan.StatusSebelumnya)
+	}
+
+	now := time.Now()
+	usulan.DitanggapiOlehID = &userID
+	usulan.DitanggapiPada = &now
+	usulan.Status = models.UsulanDitolak
+	message := "janji_temu.reschedule_declined"
+	if terima {
+		usulan.Status = models.UsulanDiterima
+		message = "janji_temu.reschedule_accepted"
+		janjiTemu.WaktuDimulai, janjiTemu.WaktuSelesai = usulan.WaktuDimulai, usulan.WaktuSelesai
+	}
+	janjiTemu.Status = usulan.StatusSebelumnya
+
+	clashes, err := h.repos.JanjiTemu.RespondJadwal(&janjiTemu, &usulan)
+	if err != nil {
+		if errors.Is(err, repository.ErrSlotTaken) {
+			return helper.NewError(http.StatusConflict, helper.CodeSlotUnavailable, "janji_temu.slot_unavailable")
+		}
+		return helper.InternalError("janji_temu.reschedule_failed")
+	}
+	if len(clashes) > 0 {
+		return errBentrok(janjiTemu, clashes)
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, message),
+		Data:    janjiTemu,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) riwayatJadwalUlang(c *fiber.Ctx, janjiTemu models.JanjiTemu) error {
+	usulan, err := h.repos.JanjiTemu.ListUsulanJadwal(janjiTemu.ID)
+	if err != nil {
+		return helper.InternalError("janji_temu.reschedule_history_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "janji_temu.reschedule_history"),
+		Data:    usulan,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
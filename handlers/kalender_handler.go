@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/ical"
+	"backend-pedika-fiber/models"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Janji temu yang dibatalkan tetap muncul di feed selama rentang ini supaya
+// aplikasi kalender yang menyinkronkan feed sempat menghapusnya.
+const kalenderBatalRetensi = 30 * 24 * time.Hour
+
+// BuatTokenKalender membuat URL feed kalender baru untuk user yang login.
+// Token hanya ditampilkan sekali; URL feed sebelumnya tidak berlaku lagi.
+func (h *Handler) BuatTokenKalender(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(models.User)
+	// Token dibuat dan disimpan sama seperti token reset password.
+	token, err := generateResetToken()
+	if err != nil {
+		return helper.InternalError("kalender.token_failed")
+	}
+	if err := h.repos.KalenderTokens.Replace(&models.KalenderToken{UserID: user.ID, TokenHash: hashResetToken(token)}); err != nil {
+		return helper.InternalError("kalender.token_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: i18n.T(c, "kalender.token_created"),
+		Data: fiber.Map{
+			"feed_url": fmt.Sprintf("%s/api/kalender/%s.ics", c.BaseURL(), token),
+		},
+	}
+	return c.Status(http.StatusCreated).JSON(response)
+}
+
+func (h *Handler) HapusTokenKalender(c *fiber.Ctx) error {
+	user, _ := c.Locals("user").(models.User)
+	if err := h.repos.KalenderTokens.DeleteByUser(user.ID); err != nil {
+		return helper.InternalError("kalender.token_delete_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "kalender.token_deleted"),
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// FeedKalender mengirim feed iCalendar berisi janji temu user yang disetujui
+// dan semua event. Feed diautentikasi dengan token di URL.
+func (h *Handler) FeedKalender(c *fiber.Ctx) error {
+	token, err := h.repos.KalenderTokens.FindByHash(hashResetToken(c.Params("token")))
+	if err != nil || token.User.IsSuspended {
+		return helper.NotFound("kalender.not_found")
+	}
+	locale := token.User.Locale
+
+	janjiTemus, err := h.repos.JanjiTemu.ListKalender(token.UserID, time.Now().Add(-kalenderBatalRetensi))
+	if err != nil {
+		return helper.InternalError("kalender.feed_failed")
+	}
+	events, err := h.repos.Events.List()
+	if err != nil {
+		return helper.InternalError("kalender.feed_failed")
+	}
+
+	cal := ical.Calendar{Name: i18n.Translate(locale, "kalender.name")}
+	for _, j := range janjiTemus {
+		cal.Events = append(cal.Events, janjiTemuICal(j, locale))
+	}
+	for _, e := range events {
+		cal.Events = append(cal.Events, eventICal(e))
+	}
+	return sendICal(c, cal, "")
+}
+
+func (h *Handler) MasyarakatUnduhJanjiTemuICal(c *fiber.Ctx) error {
+	janjiTemu, _, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	return h.unduhJanjiTemuICal(c, janjiTemu)
+}
+
+func (h *Handler) AdminUnduhJanjiTemuICal(c *fiber.Ctx) error {
+	janjiTemu, _, err := h.findJanjiTemuPetugas(c)
+	if err != nil {
+		return err
+	}
+	return h.unduhJanjiTemuICal(c, janjiTemu)
+}
+
+func (h *Handler) unduhJanjiTemuICal(c *fiber.Ctx, janjiTemu models.JanjiTemu) error {
+	if janjiTemu.Status != models.JanjiTemuDisetujui {
+		return helper.Conflict("kalender.not_approved")
+	}
+	cal := ical.Calendar{Events: []ical.Event{janjiTemuICal(janjiTemu, i18n.Locale(c))}}
+	return sendICal(c, cal, fmt.Sprintf("janji-temu-%d.ics", janjiTemu.ID))
+}
+
+func (h *Handler) UnduhEventICal(c *fiber.Ctx) error {
+	event, err := h.repos.Events.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("event.not_found")
+	}
+	cal := ical.Calendar{Events: []ical.Event{eventICal(event)}}
+	return sendICal(c, cal, fmt.Sprintf("event-%d.ics", event.ID))
+}
+
+// janjiTemuICal sengaja tidak menyertakan keperluan konsultasi karena feed
+// kalender biasanya disinkronkan ke layanan pihak ketiga.
+func janjiTemuICal(j models.JanjiTemu, locale string) ical.Event {
+	status := ical.StatusConfirmed
+	if !j.Aktif() {
+		status = ical.StatusCancelled
+	}
+	return ical.Event{
+		UID:      fmt.Sprintf("janji-temu-%d@pedika", j.ID),
+		Summary:  i18n.Translate(locale, "kalender.janji_temu_summary"),
+		Location: j.Ruangan,
+		Start:    j.WaktuDimulai,
+		End:      j.WaktuSelesai,
+		Status:   status,
+		Updated:  j.UpdatedAt,
+	}
+}
+
+func eventICal(e models.Event) ical.Event {
+	return ical.Event{
+		UID:         fmt.Sprintf("event-%d@pedika", e.ID),
+		Summary:     e.NamaEvent,
+		Description: e.DeskripsiEvent,
+		Start:       e.TanggalPelaksanaan,
+		Status:      ical.StatusConfirmed,
+		Updated:     e.UpdatedAt,
+	}
+}
+
+// sendICal mengirim kalender sebagai text/calendar. filename yang tidak
+// kosong membuat browser mengunduhnya sebagai file .ics.
+func sendICal(c *fiber.Ctx, cal ical.Calendar, filename string) error {
+	if filename != "" {
+		c.Attachment(filename)
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.Status(http.StatusOK).Send(cal.Bytes(time.Now()))
+}
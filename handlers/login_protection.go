@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/models"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Semua state percobaan login disimpan di database (kolom di tabel users dan
+// tabel login_attempts), sehingga batasannya tetap berlaku walaupun aplikasi
+// dijalankan di beberapa replica.
+const (
+	// Setelah loginDelayThreshold kali gagal berturut-turut, percobaan
+	// berikutnya harus menunggu 1, 2, 4, ... detik (maksimal loginMaxDelay).
+	loginDelayThreshold  = 3
+	loginMaxDelay        = 60 * time.Second
+	accountLockThreshold = 10
+	accountLockDuration  = 15 * time.Minute
+
+	ipFailureWindow    = 15 * time.Minute
+	ipFailureThreshold = 30
+)
+
+const (
+	loginReasonSuccess        = "success"
+	loginReasonUnknownAccount = "unknown_account"
+	loginReasonWrongPassword  = "wrong_password"
+	loginReasonWrongTwoFactor = "wrong_two_factor"
+	loginReasonLocked         = "locked"
+	loginReasonThrottled      = "throttled"
+	loginReasonSuspended      = "suspended"
+)
+
+// ipRetryAfter mengembalikan lama waktu tunggu jika IP ini terlalu banyak
+// gagal login dalam ipFailureWindow terakhir.
+func (h *Handler) ipRetryAfter(ip string) time.Duration {
+	failures, err := h.repos.LoginAttempts.CountFailuresByIP(ip, time.Now().Add(-ipFailureWindow))
+	if err != nil {
+		log.Println("Error counting login attempts:", err)
+		return 0
+	}
+	if failures < ipFailureThreshold {
+		return 0
+	}
+
+	last, err := h.repos.LoginAttempts.LastFailureByIP(ip)
+	if err != nil {
+		return 0
+	}
+	return time.Until(last.CreatedAt.Add(ipFailureWindow))
+}
+
+// accountRetryAfter mengembalikan lama waktu tunggu untuk akun ini dan
+// apakah akun sedang terkunci (bukan sekadar jeda progresif).
+func accountRetryAfter(user models.User) (time.Duration, bool) {
+	now := time.Now()
+	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		return user.LockedUntil.Sub(now), true
+	}
+	if user.FailedLoginCount < loginDelayThreshold || user.LastFailedLoginAt == nil {
+		return 0, false
+	}
+	delay := time.Duration(math.Pow(2, float64(user.FailedLoginCount-loginDelayThreshold))) * time.Second
+	if delay > loginMaxDelay {
+		delay = loginMaxDelay
+	}
+	if wait := user.LastFailedLoginAt.Add(delay).Sub(now); wait > 0 {
+		return wait, false
+	}
+	return 0, false
+}
+
+// checkAccountLogin menolak percobaan login selama akun terkunci atau masih
+// dalam jeda progresif. Jika boleh, percobaan ini langsung diklaim (dihitung
+// gagal) sebelum password diperiksa, sehingga request paralel tidak bisa
+// lolos bersama-sama sebelum kegagalan pertama tercatat.
+func (h *Handler) checkAccountLogin(c *fiber.Ctx, user models.User, identifier string) error {
+	if wait, locked := accountRetryAfter(user); wait > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(wait)))
+		if locked {
+			h.recordLoginAttempt(c, &user.ID, identifier, false, loginReasonLocked)
+			return helper.NewError(http.StatusLocked, helper.CodeAccountLocked, "auth.account_locked")
+		}
+		h.recordLoginAttempt(c, &user.ID, identifier, false, loginReasonThrottled)
+		return helper.TooManyRequests("auth.login_throttled")
+	}
+
+	claimed, err := h.repos.Users.ClaimLoginAttempt(user.ID, user.FailedLoginCount, time.Now())
+	if err != nil {
+		log.Println("Error claiming login attempt:", err)
+	}
+	if !claimed {
+		// Percobaan lain untuk akun yang sama baru saja diklaim.
+		c.Set(fiber.HeaderRetryAfter, "1")
+		h.recordLoginAttempt(c, &user.ID, identifier, false, loginReasonThrottled)
+		return helper.TooManyRequests("auth.login_throttled")
+	}
+	return nil
+}
+
+// lockIfExceeded dipanggil setelah password atau kode 2FA salah. Kegagalannya
+// sudah dihitung oleh checkAccountLogin, jadi tinggal mengunci akun jika
+// batasnya tercapai.
+func (h *Handler) lockIfExceeded(userID uint) {
+	if err := h.repos.Users.LockIfExceeded(userID, accountLockThreshold, time.Now().Add(accountLockDuration)); err != nil {
+		log.Println("Error locking account:", err)
+	}
+}
+
+func (h *Handler) releaseLoginAttempt(userID uint) {
+	if err := h.repos.Users.ReleaseLoginAttempt(userID); err != nil {
+		log.Println("Error releasing login attempt:", err)
+	}
+}
+
+func (h *Handler) resetFailedLogins(userID uint) {
+	if err := h.repos.Users.ResetFailedLogins(userID); err != nil {
+		log.Println("Error resetting failed logins:", err)
+	}
+}
+
+func (h *Handler) recordLoginAttempt(c *fiber.Ctx, userID *uint, identifier string, success bool, reason string) {
+	attempt := models.LoginAttempt{
+		UserID:     userID,
+		Identifier: identifier,
+		IPAddress:  c.IP(),
+		UserAgent:  truncate(c.Get(fiber.HeaderUserAgent), 255),
+		Success:    success,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+	if err := h.repos.LoginAttempts.Create(&attempt); err != nil {
+		log.Println("Error recording login attempt:", err)
+	}
+}
+
+func loginIdentifier(credentials models.LoginCredentials) string {
+	switch {
+	case credentials.Email != "":
+		return credentials.Email
+	case credentials.Username != "":
+		return credentials.Username
+	default:
+		return credentials.PhoneNumber
+	}
+}
+
+func retryAfterSeconds(wait time.Duration) int {
+	return int(math.Ceil(wait.Seconds()))
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
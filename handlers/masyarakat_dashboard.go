@@ -1,8 +1,8 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
-	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,10 +15,10 @@ func HelloMasyarakat(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
-func EmergencyContact(c *fiber.Ctx) error {
-	var emergencyContact models.EmergencyContact
-	if err := database.DB.First(&emergencyContact).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(Response{Success: 0, Message: "Failed to get emergency contact", Data: nil})
+func (h *Handler) EmergencyContact(c *fiber.Ctx) error {
+	emergencyContact, err := h.repos.EmergencyContacts.First()
+	if err != nil {
+		return helper.InternalError("emergency_contact.retrieve_failed")
 	}
-	return c.Status(http.StatusOK).JSON(Response{Success: 1, Message: "Emergency contact retrieved successfully", Data: emergencyContact})
+	return c.Status(http.StatusOK).JSON(helper.Response{Code: http.StatusOK, Status: "success", Message: i18n.T(c, "emergency_contact.retrieved"), Data: emergencyContact})
 }
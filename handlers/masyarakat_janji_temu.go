@@ -1,77 +1,130 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/auth"
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/jadwal"
 	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func MasyarakatCreateJanjiTemu(c *fiber.Ctx) error {
+// CreateJanjiTemuRequest: konselor_id opsional untuk memilih konselor yang
+// diinginkan; admin tetap bisa menggantinya saat menyetujui.
+// mode_konsultasi kosong berarti tatap muka.
+type CreateJanjiTemuRequest struct {
+	KonselorID          string `json:"konselor_id" form:"konselor_id" validate:"int"`
+	WaktuDimulai        string `json:"waktu_dimulai" form:"waktu_dimulai" validate:"required,datetime,future"`
+	WaktuSelesai        string `json:"waktu_selesai" form:"waktu_selesai" validate:"required,datetime,after=waktu_dimulai"`
+	KeperluanKonsultasi string `json:"keperluan_konsultasi" form:"keperluan_konsultasi" validate:"required,max=1000"`
+	ModeKonsultasi      string `json:"mode_konsultasi" form:"mode_konsultasi" validate:"oneof=tatap_muka|telepon|video"`
+}
+
+// EditJanjiTemuRequest: keperluan_konsultasi dan mode_konsultasi yang kosong
+// tidak diubah.
+type EditJanjiTemuRequest struct {
+	WaktuDimulai        string `json:"waktu_dimulai" form:"waktu_dimulai" validate:"required,datetime,future"`
+	WaktuSelesai        string `json:"waktu_selesai" form:"waktu_selesai" validate:"required,datetime,after=waktu_dimulai"`
+	KeperluanKonsultasi string `json:"keperluan_konsultasi" form:"keperluan_konsultasi" validate:"max=1000"`
+	ModeKonsultasi      string `json:"mode_konsultasi" form:"mode_konsultasi" validate:"oneof=tatap_muka|telepon|video"`
+}
+
+// BookingJanjiTemuRequest memesan satu slot dari jadwal konselor. Waktu
+// selesai mengikuti panjang slot.
+type BookingJanjiTemuRequest struct {
+	KonselorID          string `json:"konselor_id" form:"konselor_id" validate:"required,int"`
+	WaktuDimulai        string `json:"waktu_dimulai" form:"waktu_dimulai" validate:"required,datetime,future"`
+	KeperluanKonsultasi string `json:"keperluan_konsultasi" form:"keperluan_konsultasi" validate:"required,max=1000"`
+	ModeKonsultasi      string `json:"mode_konsultasi" form:"mode_konsultasi" validate:"oneof=tatap_muka|telepon|video"`
+}
+
+type BatalkanJanjiTemuRequest struct {
+	AlasanDibatalkan string `json:"alasan_dibatalkan" form:"alasan_dibatalkan" validate:"required,max=500"`
+}
+
+// ApproveJanjiTemuRequest: konselor_id kosong berarti konselor yang dipilih
+// sebelumnya, atau konselor yang menyetujui. mode_konsultasi kosong berarti
+// mode yang dipilih masyarakat.
+type ApproveJanjiTemuRequest struct {
+	KonselorID     string `json:"konselor_id" form:"konselor_id" validate:"int"`
+	Ruangan        string `json:"ruangan" form:"ruangan" validate:"max=100"`
+	ModeKonsultasi string `json:"mode_konsultasi" form:"mode_konsultasi" validate:"oneof=tatap_muka|telepon|video"`
+}
+
+// TautanPertemuanResponse hanya dikirim kepada peserta janji temu.
+type TautanPertemuanResponse struct {
+	JanjiTemuID     uint   `json:"janji_temu_id"`
+	ModeKonsultasi  string `json:"mode_konsultasi"`
+	TautanPertemuan string `json:"tautan_pertemuan"`
+}
+
+// JanjiTemuBentrok adalah janji temu yang sudah disetujui dan beririsan
+// waktunya, dikirim di error.details respons 409 SCHEDULE_CONFLICT.
+type JanjiTemuBentrok struct {
+	ID           uint      `json:"id"`
+	WaktuDimulai time.Time `json:"waktu_dimulai"`
+	WaktuSelesai time.Time `json:"waktu_selesai"`
+	KonselorID   *uint     `json:"konselor_id"`
+	Ruangan      string    `json:"ruangan"`
+	// Bentrok berisi "konselor" dan/atau "ruangan".
+	Bentrok []string `json:"bentrok"`
+}
+
+type TolakJanjiTemuRequest struct {
+	AlasanDitolak string `json:"alasan_ditolak" form:"alasan_ditolak" validate:"required,max=500"`
+}
+
+// MasyarakatCreateJanjiTemu mengajukan janji temu pada waktu bebas. Jika
+// konselor_id diisi, pengajuan diperlakukan seperti booking slot: waktunya
+// harus tepat slot kosong jadwal konselor tersebut dan waktu_selesai
+// mengikuti slot.
+func (h *Handler) MasyarakatCreateJanjiTemu(c *fiber.Ctx) error {
 	token := c.Get("Authorization")
-	userID, err := auth.ExtractUserIDFromToken(token)
+	userID, err := h.jwt.ExtractUserIDFromToken(token)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized",
-		}
-		return c.Status(http.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("common.unauthorized")
 	}
 
-	var janjitemu models.JanjiTemu
-	if err := c.BodyParser(&janjitemu); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
+	var req CreateJanjiTemuRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	waktuDimulai, _ := validation.ParseDateTime(req.WaktuDimulai)
+	waktuSelesai, _ := validation.ParseDateTime(req.WaktuSelesai)
+	janjitemu := models.JanjiTemu{
+		WaktuDimulai:        waktuDimulai,
+		WaktuSelesai:        waktuSelesai,
+		Status:              models.JanjiTemuBelumDisetujui,
+		KeperluanKonsultasi: req.KeperluanKonsultasi,
+		ModeKonsultasi:      modeKonsultasi(req.ModeKonsultasi),
+		UserID:              uint(userID),
+	}
+	if req.KonselorID != "" {
+		if err := h.pesanSlot(&janjitemu, parseID(req.KonselorID)); err != nil {
+			return err
 		}
-		return c.Status(http.StatusBadRequest).JSON(response)
-	}
-	waktuDimulai, err := time.Parse("2006-01-02T15:04:05", c.FormValue("waktu_dimulai"))
-	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid format for start time",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
-	}
-	waktuSelesai, err := time.Parse("2006-01-02T15:04:05", c.FormValue("waktu_selesai"))
-	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid format for end time",
+	} else {
+		if err := h.cekBentrok(janjitemu); err != nil {
+			return err
 		}
-		return c.Status(http.StatusBadRequest).JSON(response)
-	}
-	janjitemu.WaktuDimulai = waktuDimulai
-	janjitemu.WaktuSelesai = waktuSelesai
-	janjitemu.Status = "Belum disetujui"
-	janjitemu.KeperluanKonsultasi = c.FormValue("keperluan_konsultasi")
-	janjitemu.UserID = uint(userID)
-	janjitemu.UserIDTolakSetujui = nil
-
-	if err := database.DB.Create(&janjitemu).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to create janjitemu",
+		if err := h.repos.JanjiTemu.Create(&janjitemu); err != nil {
+			return helper.InternalError("janji_temu.create_failed")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
 	}
 
 	responseData := struct {
 		ID                  uint      `json:"id"`
 		UserID              uint      `json:"user_id"`
+		KonselorID          *uint     `json:"konselor_id"`
 		WaktuDimulai        time.Time `json:"waktu_dimulai"`
 		WaktuSelesai        time.Time `json:"waktu_selesai"`
 		KeperluanKonsultasi string    `json:"keperluan_konsultasi"`
+		ModeKonsultasi      string    `json:"mode_konsultasi"`
 		Status              string    `json:"status"`
 		UserTolakSetujui    uint      `json:"user_tolak_setujui"`
 		AlasanDitolak       string    `json:"alasan_ditolak"`
@@ -79,315 +132,454 @@ func MasyarakatCreateJanjiTemu(c *fiber.Ctx) error {
 	}{
 		ID:                  janjitemu.ID,
 		UserID:              janjitemu.UserID,
+		KonselorID:          janjitemu.KonselorID,
 		WaktuDimulai:        janjitemu.WaktuDimulai,
 		WaktuSelesai:        janjitemu.WaktuSelesai,
 		KeperluanKonsultasi: janjitemu.KeperluanKonsultasi,
+		ModeKonsultasi:      janjitemu.ModeKonsultasi,
 		Status:              janjitemu.Status,
 		UserTolakSetujui:    0,
 		AlasanDitolak:       janjitemu.AlasanDitolak,
 		AlasanDibatalkan:    janjitemu.AlasanDibatalkan,
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusCreated,
 		Status:  "success",
-		Message: "Janjitemu created successfully",
+		Message: i18n.T(c, "janji_temu.created"),
 		Data:    responseData,
 	}
 	return c.Status(http.StatusCreated).JSON(response)
 }
 
-func MasyarakatEditJanjiTemu(c *fiber.Ctx) error {
-	janjiTemuID := c.Params("id")
+// MasyarakatBookingJanjiTemu memesan slot kosong dari jadwal konselor. Slot
+// dikunci secara atomic, sehingga dari dua pemesanan bersamaan hanya satu
+// yang berhasil dan yang lain mendapat 409 SLOT_UNAVAILABLE.
+func (h *Handler) MasyarakatBookingJanjiTemu(c *fiber.Ctx) error {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return helper.Unauthorized("common.unauthorized")
+	}
+	var req BookingJanjiTemuRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
 
-	var updateRequest struct {
-		WaktuDimulai        time.Time `json:"waktu_dimulai"`
-		WaktuSelesai        time.Time `json:"waktu_selesai"`
-		KeperluanKonsultasi string    `json:"keperluan_konsultasi"`
+	waktuDimulai, _ := validation.ParseDateTime(req.WaktuDimulai)
+	janjiTemu := models.JanjiTemu{
+		UserID:              userID,
+		WaktuDimulai:        waktuDimulai,
+		KeperluanKonsultasi: req.KeperluanKonsultasi,
+		ModeKonsultasi:      modeKonsultasi(req.ModeKonsultasi),
+		Status:              models.JanjiTemuBelumDisetujui,
 	}
-	if err := c.BodyParser(&updateRequest); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+	if err := h.pesanSlot(&janjiTemu, parseID(req.KonselorID)); err != nil {
+		return err
+	}
+
+	response := helper.Response{
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: i18n.T(c, "janji_temu.booked"),
+		Data:    janjiTemu,
 	}
+	return c.Status(http.StatusCreated).JSON(response)
+}
 
-	waktuDimulai := updateRequest.WaktuDimulai
-	waktuSelesai := updateRequest.WaktuSelesai
+// pesanSlot menyimpan janji temu pada slot kosong konselor yang dimulai
+// tepat pada janjiTemu.WaktuDimulai. Waktu selesai diambil dari slot.
+func (h *Handler) pesanSlot(janjiTemu *models.JanjiTemu, konselorID uint) error {
+	konselor, err := h.findKonselorAktif(konselorID)
+	if err != nil {
+		return err
+	}
+	hari := jadwalTanggal(janjiTemu.WaktuDimulai)
+	slots, err := h.slotKosong(konselor.ID, hari, hari.AddDate(0, 0, 1))
+	if err != nil {
+		return helper.InternalError("jadwal.slots_failed")
+	}
+	slot, ok := jadwal.Cari(slots, konselor.ID, janjiTemu.WaktuDimulai)
+	if !ok {
+		return helper.NewError(http.StatusConflict, helper.CodeSlotUnavailable, "janji_temu.slot_unavailable")
+	}
 
-	var janjiTemu models.JanjiTemu
-	if err := database.DB.First(&janjiTemu, janjiTemuID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Janji temu not found",
+	janjiTemu.KonselorID = &konselor.ID
+	janjiTemu.WaktuDimulai = slot.WaktuDimulai
+	janjiTemu.WaktuSelesai = slot.WaktuSelesai
+	if err := h.repos.JanjiTemu.Book(janjiTemu); err != nil {
+		if errors.Is(err, repository.ErrSlotTaken) {
+			return helper.NewError(http.StatusConflict, helper.CodeSlotUnavailable, "janji_temu.slot_unavailable")
 		}
-		return c.Status(http.StatusNotFound).JSON(response)
+		return helper.InternalError("janji_temu.create_failed")
 	}
-	if janjiTemu.Status != "Belum disetujui" {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusForbidden,
-			Status:  "error",
-			Message: "Forbidden: You can only edit appointments with status 'Belum disetujui'",
-		}
-		return c.Status(http.StatusForbidden).JSON(response)
+	return nil
+}
+
+func (h *Handler) MasyarakatEditJanjiTemu(c *fiber.Ctx) error {
+	var req EditJanjiTemuRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
-	janjiTemu.WaktuDimulai = waktuDimulai
-	janjiTemu.WaktuSelesai = waktuSelesai
-	janjiTemu.KeperluanKonsultasi = c.FormValue("keperluan_konsultasi")
 
-	if err := database.DB.Save(&janjiTemu).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update janji temu",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	janjiTemu, _, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	// Mengubah waktu mengembalikan janji temu ke "Belum disetujui". Janji temu
+	// yang sedang menunggu jadwal ulang harus diselesaikan lewat usulannya.
+	if janjiTemu.Status == models.JanjiTemuJadwalUlang {
+		return helper.Conflict("janji_temu.reschedule_pending")
+	}
+	if janjiTemu.Status != models.JanjiTemuBelumDisetujui && !janjiTemu.BisaMenjadi(models.JanjiTemuBelumDisetujui) {
+		return errTransisi(janjiTemu, models.JanjiTemuBelumDisetujui)
+	}
+	if dariSlot, err := h.repos.JanjiTemu.HasSlot(janjiTemu.ID); err != nil {
+		return helper.InternalError("janji_temu.update_failed")
+	} else if dariSlot {
+		return helper.Forbidden("janji_temu.slot_edit_forbidden")
+	}
+	janjiTemu.WaktuDimulai, _ = validation.ParseDateTime(req.WaktuDimulai)
+	janjiTemu.WaktuSelesai, _ = validation.ParseDateTime(req.WaktuSelesai)
+	if req.KeperluanKonsultasi != "" {
+		janjiTemu.KeperluanKonsultasi = req.KeperluanKonsultasi
+	}
+	if req.ModeKonsultasi != "" {
+		janjiTemu.ModeKonsultasi = req.ModeKonsultasi
+	}
+	if err := h.cekBentrok(janjiTemu); err != nil {
+		return err
+	}
+
+	if err := h.repos.JanjiTemu.Save(&janjiTemu); err != nil {
+		return helper.InternalError("janji_temu.update_failed")
 	}
 
-	response := helper.ResponseWithOutData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Janji temu updated successfully",
+		Message: i18n.T(c, "janji_temu.updated"),
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func GetUserJanjiTemus(c *fiber.Ctx) error {
-	userID, err := auth.ExtractUserIDFromToken(c.Get("Authorization"))
+func (h *Handler) GetUserJanjiTemus(c *fiber.Ctx) error {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized",
-		}
-		return c.Status(http.StatusUnauthorized).JSON(response)
-	}
-	var janjiTemus []models.JanjiTemu
-	if err := database.DB.Preload("User").Preload("UserTolakSetujui").Where("user_id = ?", userID).Find(&janjiTemus).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to get user JanjiTemu records",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.Unauthorized("common.unauthorized")
+	}
+	janjiTemus, err := h.repos.JanjiTemu.ListByUser(userID)
+	if err != nil {
+		return helper.InternalError("janji_temu.list_failed")
 	}
 	if len(janjiTemus) == 0 {
-		response := helper.ResponseWithOutData{
+		response := helper.Response{
 			Code:    http.StatusOK,
 			Status:  "success",
-			Message: "No JanjiTemu records found for the user",
+			Message: i18n.T(c, "janji_temu.list_empty"),
 		}
 		return c.Status(http.StatusOK).JSON(response)
 	}
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "List of JanjiTemu by user",
+		Message: i18n.T(c, "janji_temu.list"),
 		Data:    janjiTemus,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func GetJanjiTemuByID(c *fiber.Ctx) error {
-	janjiTemuID := c.Params("id")
-	var janjiTemu models.JanjiTemu
-	if err := database.DB.Preload("UserTolakSetujui").First(&janjiTemu, janjiTemuID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "JanjiTemu not found",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
-	}
-	if janjiTemu.Status == "Ditolak" && janjiTemu.UserTolakSetujui.ID != 0 {
-		var user models.User
-		if err := database.DB.First(&user, janjiTemu.UserIDTolakSetujui).Error; err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to fetch user detail who rejected the appointment",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
-		}
-		janjiTemu.UserTolakSetujui = user
+// GetJanjiTemuByID hanya memberikan janji temu milik pemanggil.
+func (h *Handler) GetJanjiTemuByID(c *fiber.Ctx) error {
+	milik, _, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	janjiTemu, err := h.repos.JanjiTemu.FindDetail(milik.ID)
+	if err != nil {
+		return helper.NotFound("janji_temu.not_found")
 	}
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "JanjiTemu detail",
+		Message: i18n.T(c, "janji_temu.detail"),
 		Data:    janjiTemu,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func MasyarakatCancelJanjiTemu(c *fiber.Ctx) error {
-	janjiTemuID := c.Params("id")
-
-	var janjiTemu models.JanjiTemu
-	if err := database.DB.First(&janjiTemu, janjiTemuID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Janji temu not found",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
+func (h *Handler) MasyarakatCancelJanjiTemu(c *fiber.Ctx) error {
+	janjiTemu, _, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
 	}
-	if janjiTemu.Status != "Belum disetujui" {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusForbidden,
-			Status:  "error",
-			Message: "Forbidden: You can only cancel appointments with status 'Belum disetujui'",
-		}
-		return c.Status(http.StatusForbidden).JSON(response)
+	if !janjiTemu.BisaMenjadi(models.JanjiTemuDibatalkan) {
+		return errTransisi(janjiTemu, models.JanjiTemuDibatalkan)
+	}
+	var req BatalkanJanjiTemuRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
-	janjiTemu.Status = "Dibatalkan"
-	janjiTemu.AlasanDibatalkan = c.FormValue("alasan_dibatalkan")
+	janjiTemu.Status = models.JanjiTemuDibatalkan
+	janjiTemu.AlasanDibatalkan = req.AlasanDibatalkan
 
-	if err := database.DB.Save(&janjiTemu).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to cancel janji temu",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.JanjiTemu.Save(&janjiTemu); err != nil {
+		return helper.InternalError("janji_temu.cancel_failed")
+	}
+	if janjiTemu.KonselorID != nil {
+		h.notifyJanjiTemu(janjiTemu, *janjiTemu.KonselorID, models.NotificationJanjiTemuDibatalkan, janjiTemu.AlasanDibatalkan)
 	}
 
-	response := helper.ResponseWithOutData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Janji temu canceled successfully",
+		Message: i18n.T(c, "janji_temu.cancelled"),
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func AdminGetAllJanjiTemu(c *fiber.Ctx) error {
-	var janjiTemus []models.JanjiTemu
-	if err := database.DB.Preload("User").Preload("UserTolakSetujui").Find(&janjiTemus).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to retrieve Janji Temu data",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+func (h *Handler) AdminGetAllJanjiTemu(c *fiber.Ctx) error {
+	janjiTemus, err := h.repos.JanjiTemu.List()
+	if err != nil {
+		return helper.InternalError("janji_temu.list_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "List of Janji Temu",
+		Message: i18n.T(c, "janji_temu.list"),
 		Data:    janjiTemus,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func AdminJanjiTemuByID(c *fiber.Ctx) error {
-	janjiTemuID := c.Params("id")
-	var janjiTemu models.JanjiTemu
-	if err := database.DB.Preload("UserTolakSetujui").Preload("User").First(&janjiTemu, janjiTemuID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "JanjiTemu not found",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
+func (h *Handler) AdminJanjiTemuByID(c *fiber.Ctx) error {
+	janjiTemu, err := h.repos.JanjiTemu.FindDetail(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("janji_temu.not_found")
 	}
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "JanjiTemu detail",
+		Message: i18n.T(c, "janji_temu.detail"),
 		Data:    janjiTemu,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func AdminApproveJanjiTemu(c *fiber.Ctx) error {
+// AdminApproveJanjiTemu menyetujui janji temu sekaligus mencatat konselor
+// yang menanganinya dan ruangannya. Persetujuan ditolak dengan 409
+// SCHEDULE_CONFLICT jika konselor atau ruangan sudah dipakai janji temu lain
+// yang disetujui pada waktu yang beririsan. Konsultasi video mendapat tautan
+// pertemuan saat pertama kali disetujui; konsultasi telepon dan video tidak
+// memakai ruangan.
+func (h *Handler) AdminApproveJanjiTemu(c *fiber.Ctx) error {
 	token := c.Get("Authorization")
-	userID, err := auth.ExtractUserIDFromToken(token)
+	userID, err := h.jwt.ExtractUserIDFromToken(token)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized",
-		}
-		return c.Status(http.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("common.unauthorized")
+	}
+	var req ApproveJanjiTemuRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	janjiTemu, err := h.repos.JanjiTemu.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("janji_temu.not_found")
+	}
+	if janjiTemu.Status == models.JanjiTemuJadwalUlang {
+		return helper.Conflict("janji_temu.reschedule_pending")
+	}
+	if !janjiTemu.BisaMenjadi(models.JanjiTemuDisetujui) {
+		return errTransisi(janjiTemu, models.JanjiTemuDisetujui)
+	}
+
+	caller, _ := c.Locals("user").(models.User)
+	var konselorID uint
+	switch {
+	case req.KonselorID != "":
+		konselorID = parseID(req.KonselorID)
+	case janjiTemu.KonselorID != nil:
+		konselorID = *janjiTemu.KonselorID
+	case caller.Role == models.RoleKonselor:
+		konselorID = caller.ID
+	default:
+		return helper.ValidationFailed(requiredField("konselor_id"))
+	}
+	// Konselor hanya boleh menyetujui janji temu untuk dirinya sendiri, baik
+	// lewat konselor_id maupun janji temu yang sudah ditugaskan ke orang lain.
+	if caller.Role == models.RoleKonselor && konselorID != caller.ID {
+		return helper.Forbidden("janji_temu.assign_forbidden")
+	}
+	konselor, err := h.findKonselorAktif(konselorID)
+	if err != nil {
+		return err
 	}
-	id := c.Params("id")
-	var janjiTemu models.JanjiTemu
-	if err := database.DB.First(&janjiTemu, id).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Janji temu tidak ditemukan",
-		})
+	janjiTemu.KonselorID = &konselor.ID
+	if req.ModeKonsultasi != "" {
+		janjiTemu.ModeKonsultasi = req.ModeKonsultasi
+	}
+	if req.Ruangan != "" {
+		janjiTemu.Ruangan = req.Ruangan
+	}
+	if janjiTemu.ModeKonsultasi != models.ModeTatapMuka {
+		janjiTemu.Ruangan = ""
+	}
+	if janjiTemu.ModeKonsultasi != models.ModeVideo {
+		janjiTemu.TautanPertemuan = ""
+	} else if janjiTemu.TautanPertemuan == "" {
+		tautan, err := h.pertemuan.BuatTautan(janjiTemu)
+		if err != nil {
+			return helper.NewError(http.StatusInternalServerError, helper.CodeMeetingLinkFailed, "janji_temu.link_failed")
+		}
+		janjiTemu.TautanPertemuan = tautan
 	}
 	janjiTemu.UserIDTolakSetujui = &userID
-	janjiTemu.Status = "Disetujui"
+	janjiTemu.Status = models.JanjiTemuDisetujui
 
-	if err := database.DB.Save(&janjiTemu).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal menyimpan perubahan status",
-		})
+	clashes, err := h.repos.JanjiTemu.Approve(&janjiTemu)
+	if err != nil {
+		if errors.Is(err, repository.ErrSlotTaken) {
+			return helper.NewError(http.StatusConflict, helper.CodeSlotUnavailable, "janji_temu.slot_unavailable")
+		}
+		return helper.InternalError("janji_temu.status_failed")
 	}
-	response := helper.ResponseWithOutData{
+	if len(clashes) > 0 {
+		return errBentrok(janjiTemu, clashes)
+	}
+	h.notifyJanjiTemu(janjiTemu, janjiTemu.UserID, models.NotificationJanjiTemuDisetujui)
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Janji Temu berhasil disetujui",
+		Message: i18n.T(c, "janji_temu.approved"),
+		Data:    janjiTemu,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func AdminCancelJanjiTemu(c *fiber.Ctx) error {
-	janjiTemuID := c.Params("id")
+func (h *Handler) AdminCancelJanjiTemu(c *fiber.Ctx) error {
 	token := c.Get("Authorization")
-	userID, err := auth.ExtractUserIDFromToken(token)
+	userID, err := h.jwt.ExtractUserIDFromToken(token)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized",
-		}
-		return c.Status(http.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("common.unauthorized")
 	}
-	var cancelRequest struct {
-		AlasanDitolak string `json:"alasan_ditolak"`
+	var req TolakJanjiTemuRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
-	if err := c.BodyParser(&cancelRequest); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
-	}
-	var janjiTemu models.JanjiTemu
-	if err := database.DB.First(&janjiTemu, janjiTemuID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Janji temu not found",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
+	janjiTemu, err := h.repos.JanjiTemu.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("janji_temu.not_found")
 	}
-	janjiTemu.Status = "Ditolak"
+	if !janjiTemu.BisaMenjadi(models.JanjiTemuDitolak) {
+		return errTransisi(janjiTemu, models.JanjiTemuDitolak)
+	}
+	janjiTemu.Status = models.JanjiTemuDitolak
 	janjiTemu.UserIDTolakSetujui = &userID
-	janjiTemu.AlasanDitolak = c.FormValue("alasan_ditolak")
-	if err := database.DB.Save(&janjiTemu).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to cancel janji temu",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	janjiTemu.AlasanDitolak = req.AlasanDitolak
+	if err := h.repos.JanjiTemu.Save(&janjiTemu); err != nil {
+		return helper.InternalError("janji_temu.cancel_failed")
 	}
-	response := helper.ResponseWithOutData{
+	h.notifyJanjiTemu(janjiTemu, janjiTemu.UserID, models.NotificationJanjiTemuDitolak, janjiTemu.AlasanDitolak)
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Janji Temu Sudah Ditolak",
+		Message: i18n.T(c, "janji_temu.rejected"),
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
+
+// MasyarakatTautanJanjiTemu memberikan tautan pertemuan kepada pemilik
+// janji temu.
+func (h *Handler) MasyarakatTautanJanjiTemu(c *fiber.Ctx) error {
+	janjiTemu, _, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	return tautanPertemuan(c, janjiTemu)
+}
+
+// AdminTautanJanjiTemu memberikan tautan pertemuan hanya kepada konselor
+// yang menangani janji temu, bukan kepada semua petugas.
+func (h *Handler) AdminTautanJanjiTemu(c *fiber.Ctx) error {
+	janjiTemu, err := h.repos.JanjiTemu.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("janji_temu.not_found")
+	}
+	caller, _ := c.Locals("user").(models.User)
+	if janjiTemu.KonselorID == nil || *janjiTemu.KonselorID != caller.ID {
+		return helper.Forbidden("janji_temu.link_forbidden")
+	}
+	return tautanPertemuan(c, janjiTemu)
+}
+
+// tautanPertemuan hanya tersedia selama janji temu video masih disetujui.
+func tautanPertemuan(c *fiber.Ctx, janjiTemu models.JanjiTemu) error {
+	if janjiTemu.ModeKonsultasi != models.ModeVideo {
+		return helper.Conflict("janji_temu.link_not_video")
+	}
+	if janjiTemu.Status != models.JanjiTemuDisetujui || janjiTemu.TautanPertemuan == "" {
+		return helper.Conflict("janji_temu.link_unavailable")
+	}
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "janji_temu.link"),
+		Data: TautanPertemuanResponse{
+			JanjiTemuID:     janjiTemu.ID,
+			ModeKonsultasi:  janjiTemu.ModeKonsultasi,
+			TautanPertemuan: janjiTemu.TautanPertemuan,
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func modeKonsultasi(mode string) string {
+	if mode == "" {
+		return models.ModeTatapMuka
+	}
+	return mode
+}
+
+// findKonselorAktif memuat konselor yang masih aktif menerima janji temu.
+func (h *Handler) findKonselorAktif(id uint) (models.User, error) {
+	konselor, err := h.repos.Users.FindByID(id)
+	if err != nil || konselor.Role != models.RoleKonselor || konselor.IsSuspended {
+		return konselor, helper.NotFound("konselor.not_found")
+	}
+	return konselor, nil
+}
+
+// cekBentrok mengembalikan error 409 jika janjiTemu beririsan dengan janji
+// temu lain yang sudah disetujui untuk konselor atau ruangan yang sama.
+func (h *Handler) cekBentrok(janjiTemu models.JanjiTemu) error {
+	clashes, err := h.repos.JanjiTemu.FindClashes(janjiTemu)
+	if err != nil {
+		return helper.InternalError("janji_temu.conflict_check_failed")
+	}
+	if len(clashes) > 0 {
+		return errBentrok(janjiTemu, clashes)
+	}
+	return nil
+}
+
+func errBentrok(janjiTemu models.JanjiTemu, clashes []models.JanjiTemu) error {
+	details := make([]JanjiTemuBentrok, 0, len(clashes))
+	for _, clash := range clashes {
+		item := JanjiTemuBentrok{
+			ID:           clash.ID,
+			WaktuDimulai: clash.WaktuDimulai,
+			WaktuSelesai: clash.WaktuSelesai,
+			KonselorID:   clash.KonselorID,
+			Ruangan:      clash.Ruangan,
+			Bentrok:      []string{},
+		}
+		if janjiTemu.KonselorID != nil && clash.KonselorID != nil && *janjiTemu.KonselorID == *clash.KonselorID {
+			item.Bentrok = append(item.Bentrok, "konselor")
+		}
+		if janjiTemu.Ruangan != "" && janjiTemu.Ruangan == clash.Ruangan {
+			item.Bentrok = append(item.Bentrok, "ruangan")
+		}
+		details = append(details, item)
+	}
+	return helper.NewError(http.StatusConflict, helper.CodeScheduleConflict, "janji_temu.schedule_conflict").WithDetails(details)
+}
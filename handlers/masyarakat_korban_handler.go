@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/validation"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,186 +12,183 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func CreateKorban(c *fiber.Ctx) error {
-	var korban models.Korban
-	if err := c.BodyParser(&korban); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+type CreateKorbanRequest struct {
+	NoRegistrasi         string `json:"no_registrasi" form:"no_registrasi" validate:"required"`
+	NIKKorban            string `json:"nik_korban" form:"nik_korban" validate:"nik"`
+	Nama                 string `json:"nama_korban" form:"nama_korban" validate:"required,max=100"`
+	Usia                 string `json:"usia_korban" form:"usia_korban" validate:"int=0:150"`
+	AlamatKorban         string `json:"alamat_korban" form:"alamat_korban" validate:"max=255"`
+	AlamatDetail         string `json:"alamat_detail" form:"alamat_detail" validate:"max=255"`
+	JenisKelamin         string `json:"jenis_kelamin" form:"jenis_kelamin" validate:"oneof=Laki-laki|Perempuan"`
+	Agama                string `json:"agama" form:"agama" validate:"max=50"`
+	NoTelepon            string `json:"no_telepon" form:"no_telepon" validate:"phone"`
+	Pendidikan           string `json:"pendidikan" form:"pendidikan" validate:"max=100"`
+	Pekerjaan            string `json:"pekerjaan" form:"pekerjaan" validate:"max=100"`
+	StatusPerkawinan     string `json:"status_perkawinan" form:"status_perkawinan" validate:"oneof=Belum Kawin|Kawin|Cerai Hidup|Cerai Mati"`
+	Kebangsaan           string `json:"kebangsaan" form:"kebangsaan" validate:"max=100"`
+	HubunganDenganPelaku string `json:"hubungan_dengan_pelaku" form:"hubungan_dengan_pelaku" validate:"max=100"`
+	KeteranganLainnya    string `json:"keterangan_lainnya" form:"keterangan_lainnya" validate:"max=1000"`
+}
+
+// UpdateKorbanRequest memakai aturan yang sama dengan CreateKorbanRequest,
+// tetapi semua field opsional; field yang kosong tidak diubah.
+type UpdateKorbanRequest struct {
+	NoRegistrasi         string `json:"no_registrasi" form:"no_registrasi"`
+	NIKKorban            string `json:"nik_korban" form:"nik_korban" validate:"nik"`
+	Nama                 string `json:"nama_korban" form:"nama_korban" validate:"max=100"`
+	Usia                 string `json:"usia_korban" form:"usia_korban" validate:"int=0:150"`
+	AlamatKorban         string `json:"alamat_korban" form:"alamat_korban" validate:"max=255"`
+	AlamatDetail         string `json:"alamat_detail" form:"alamat_detail" validate:"max=255"`
+	JenisKelamin         string `json:"jenis_kelamin" form:"jenis_kelamin" validate:"oneof=Laki-laki|Perempuan"`
+	Agama                string `json:"agama" form:"agama" validate:"max=50"`
+	NoTelepon            string `json:"no_telepon" form:"no_telepon" validate:"phone"`
+	Pendidikan           string `json:"pendidikan" form:"pendidikan" validate:"max=100"`
+	Pekerjaan            string `json:"pekerjaan" form:"pekerjaan" validate:"max=100"`
+	StatusPerkawinan     string `json:"status_perkawinan" form:"status_perkawinan" validate:"oneof=Belum Kawin|Kawin|Cerai Hidup|Cerai Mati"`
+	Kebangsaan           string `json:"kebangsaan" form:"kebangsaan" validate:"max=100"`
+	HubunganDenganKorban string `json:"hubungan_dengan_korban" form:"hubungan_dengan_korban" validate:"max=100"`
+	KeteranganLainnya    string `json:"keterangan_lainnya" form:"keterangan_lainnya" validate:"max=1000"`
+}
+
+func (h *Handler) CreateKorban(c *fiber.Ctx) error {
+	var req CreateKorbanRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	if _, err := h.repos.Laporan.FindByNoRegistrasi(req.NoRegistrasi); err != nil {
+		return helper.NotFound("laporan.not_found")
+	}
+	usia, _ := strconv.Atoi(req.Usia)
+	korban := models.Korban{
+		NoRegistrasi:         req.NoRegistrasi,
+		NIKKorban:            req.NIKKorban,
+		Nama:                 req.Nama,
+		Usia:                 usia,
+		AlamatKorban:         req.AlamatKorban,
+		AlamatDetail:         req.AlamatDetail,
+		JenisKelamin:         req.JenisKelamin,
+		Agama:                req.Agama,
+		NoTelepon:            req.NoTelepon,
+		Pendidikan:           req.Pendidikan,
+		Pekerjaan:            req.Pekerjaan,
+		StatusPerkawinan:     req.StatusPerkawinan,
+		Kebangsaan:           req.Kebangsaan,
+		HubunganDenganKorban: req.HubunganDenganPelaku,
+		KeteranganLainnya:    req.KeteranganLainnya,
 	}
-	korban.NoRegistrasi = c.FormValue("no_registrasi")
-	korban.NIKKorban = c.FormValue("nik_korban")
-	korban.Nama = c.FormValue("nama_korban")
-	usia, err := strconv.Atoi(c.FormValue("usia_korban"))
-	if err == nil {
-		korban.Usia = usia
-	}
-	korban.AlamatKorban = c.FormValue("alamat_korban")
-	korban.AlamatDetail = c.FormValue("alamat_detail")
-	korban.JenisKelamin = c.FormValue("jenis_kelamin")
-	korban.Agama = c.FormValue("agama")
-	korban.NoTelepon = c.FormValue("no_telepon")
-	korban.Pendidikan = c.FormValue("pendidikan")
-	korban.Pekerjaan = c.FormValue("pekerjaan")
-	korban.StatusPerkawinan = c.FormValue("status_perkawinan")
-	korban.Kebangsaan = c.FormValue("kebangsaan")
-	korban.HubunganDenganKorban = c.FormValue("hubungan_dengan_pelaku")
-	korban.KeteranganLainnya = c.FormValue("keterangan_lainnya")
 
 	file, err := c.FormFile("dokumentasi_korban")
 	if err == nil {
 		src, err := file.Open()
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to open image file",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.InternalError("common.image_open_failed")
 		}
 		defer src.Close()
 
-		imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+		imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Gagal Mengupload gambar",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 		}
 
 		korban.DokumentasiPelaku = imageURL
 	}
 	korban.CreatedAt = time.Now()
 	korban.UpdatedAt = time.Now()
-	if err := database.DB.Create(&korban).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal Menambah Data Korban",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Korban.Create(&korban); err != nil {
+		return helper.InternalError("korban.create_failed")
 	}
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusCreated,
 		Status:  "success",
-		Message: "Berhasil Menambah Data Korban",
+		Message: i18n.T(c, "korban.created"),
 		Data:    korban,
 	}
 	return c.Status(http.StatusCreated).JSON(response)
 }
 
-func UpdateKorban(c *fiber.Ctx) error {
-	id := c.Params("id")
-	var korban models.Korban
-	if err := database.DB.First(&korban, id).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "korban not found",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
-	}
-	if err := c.BodyParser(&korban); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
+func (h *Handler) UpdateKorban(c *fiber.Ctx) error {
+	korban, err := h.repos.Korban.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("korban.not_found")
+	}
+	var req UpdateKorbanRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	if req.NoRegistrasi != "" && req.NoRegistrasi != korban.NoRegistrasi {
+		if _, err := h.repos.Laporan.FindByNoRegistrasi(req.NoRegistrasi); err != nil {
+			return helper.NotFound("laporan.not_found")
 		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+		korban.NoRegistrasi = req.NoRegistrasi
 	}
-	if value := c.FormValue("no_registrasi"); value != "" {
-		korban.NoRegistrasi = value
+	if req.NIKKorban != "" {
+		korban.NIKKorban = req.NIKKorban
 	}
-	if value := c.FormValue("nik_korban"); value != "" {
-		korban.NIKKorban = value
+	if req.Nama != "" {
+		korban.Nama = req.Nama
 	}
-	if value := c.FormValue("nama_korban"); value != "" {
-		korban.Nama = value
+	if req.Usia != "" {
+		korban.Usia, _ = strconv.Atoi(req.Usia)
 	}
-	if value := c.FormValue("usia_korban"); value != "" {
-		if usia, err := strconv.Atoi(value); err == nil {
-			korban.Usia = usia
-		}
-	}
-	if value := c.FormValue("alamat_korban"); value != "" {
-		korban.AlamatKorban = value
+	if req.AlamatKorban != "" {
+		korban.AlamatKorban = req.AlamatKorban
 	}
-	if value := c.FormValue("alamat_detail"); value != "" {
-		korban.AlamatDetail = value
+	if req.AlamatDetail != "" {
+		korban.AlamatDetail = req.AlamatDetail
 	}
-	if value := c.FormValue("jenis_kelamin"); value != "" {
-		korban.JenisKelamin = value
+	if req.JenisKelamin != "" {
+		korban.JenisKelamin = req.JenisKelamin
 	}
-	if value := c.FormValue("agama"); value != "" {
-		korban.Agama = value
+	if req.Agama != "" {
+		korban.Agama = req.Agama
 	}
-	if value := c.FormValue("no_telepon"); value != "" {
-		korban.NoTelepon = value
+	if req.NoTelepon != "" {
+		korban.NoTelepon = req.NoTelepon
 	}
-	if value := c.FormValue("pendidikan"); value != "" {
-		korban.Pendidikan = value
+	if req.Pendidikan != "" {
+		korban.Pendidikan = req.Pendidikan
 	}
-	if value := c.FormValue("pekerjaan"); value != "" {
-		korban.Pekerjaan = value
+	if req.Pekerjaan != "" {
+		korban.Pekerjaan = req.Pekerjaan
 	}
-	if value := c.FormValue("status_perkawinan"); value != "" {
-		korban.StatusPerkawinan = value
+	if req.StatusPerkawinan != "" {
+		korban.StatusPerkawinan = req.StatusPerkawinan
 	}
-	if value := c.FormValue("kebangsaan"); value != "" {
-		korban.Kebangsaan = value
+	if req.Kebangsaan != "" {
+		korban.Kebangsaan = req.Kebangsaan
 	}
-	if value := c.FormValue("hubungan_dengan_korban"); value != "" {
-		korban.HubunganDenganKorban = value
+	if req.HubunganDenganKorban != "" {
+		korban.HubunganDenganKorban = req.HubunganDenganKorban
 	}
-	if value := c.FormValue("keterangan_lainnya"); value != "" {
-		korban.KeteranganLainnya = value
+	if req.KeteranganLainnya != "" {
+		korban.KeteranganLainnya = req.KeteranganLainnya
 	}
 
 	file, err := c.FormFile("dokumentasi_korban")
 	if err == nil {
 		src, err := file.Open()
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to open image file",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.InternalError("common.image_open_failed")
 		}
 		defer src.Close()
 
-		imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+		imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to upload image",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 		}
 
 		korban.DokumentasiPelaku = imageURL
 	}
 
 	korban.UpdatedAt = time.Now()
-	if err := database.DB.Save(&korban).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update korban",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Korban.Save(&korban); err != nil {
+		return helper.InternalError("korban.update_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Pelaku updated successfully",
+		Message: i18n.T(c, "korban.updated"),
 		Data:    korban,
 	}
 	return c.Status(http.StatusOK).JSON(response)
@@ -1,10 +1,11 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/auth"
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
 	"errors"
 	"fmt"
 	"net/http"
@@ -14,123 +15,95 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/datatypes"
-	"gorm.io/gorm"
 )
 
+type CreateLaporanRequest struct {
+	KategoriKekerasanID string `json:"kategori_kekerasan_id" form:"kategori_kekerasan_id" validate:"required,digits"`
+	TanggalKejadian     string `json:"tanggal_kejadian" form:"tanggal_kejadian" validate:"required,datetime,past"`
+	KategoriLokasiKasus string `json:"kategori_lokasi_kasus" form:"kategori_lokasi_kasus" validate:"required,max=100"`
+	AlamatTKP           string `json:"alamat_tkp" form:"alamat_tkp" validate:"required,max=255"`
+	AlamatDetailTKP     string `json:"alamat_detail_tkp" form:"alamat_detail_tkp" validate:"max=255"`
+	KronologisKasus     string `json:"kronologis_kasus" form:"kronologis_kasus" validate:"required,max=5000"`
+}
+
+// EditLaporanRequest memakai aturan yang sama dengan CreateLaporanRequest,
+// tetapi semua field opsional; field yang kosong tidak diubah.
+type EditLaporanRequest struct {
+	KategoriKekerasanID string `json:"kategori_kekerasan_id" form:"kategori_kekerasan_id" validate:"digits"`
+	TanggalKejadian     string `json:"tanggal_kejadian" form:"tanggal_kejadian" validate:"datetime,past"`
+	KategoriLokasiKasus string `json:"kategori_lokasi_kasus" form:"kategori_lokasi_kasus" validate:"max=100"`
+	AlamatTKP           string `json:"alamat_tkp" form:"alamat_tkp" validate:"max=255"`
+	AlamatDetailTKP     string `json:"alamat_detail_tkp" form:"alamat_detail_tkp" validate:"max=255"`
+	KronologisKasus     string `json:"kronologis_kasus" form:"kronologis_kasus" validate:"max=5000"`
+}
+
+type BatalkanLaporanRequest struct {
+	AlasanDibatalkan string `json:"alasan_dibatalkan" form:"alasan_dibatalkan" validate:"required,max=500"`
+}
+
 /*=========================== USER CREATE LAPORAN =======================*/
 var mu sync.Mutex
 
-func CreateLaporan(c *fiber.Ctx) error {
+func (h *Handler) CreateLaporan(c *fiber.Ctx) error {
 	token := c.Get("Authorization")
-	userID, err := auth.ExtractUserIDFromToken(token)
+	userID, err := h.jwt.ExtractUserIDFromToken(token)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized",
-		}
-		return c.Status(http.StatusUnauthorized).JSON(response)
-	}
-
-	var laporan models.Laporan
-	if err := c.BodyParser(&laporan); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+		return helper.Unauthorized("common.unauthorized")
 	}
 
-	categoryViolenceID, err := strconv.ParseUint(c.FormValue("kategori_kekerasan_id"), 10, 64)
-	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid KategoriKekerasan ID",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+	var req CreateLaporanRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
 
-	var violenceCategory models.ViolenceCategory
-	if err := database.GetGormDBInstance().First(&violenceCategory, categoryViolenceID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Kategori kekerasan yang anda pilih tidak ditemukan",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
+	categoryViolenceID := parseID(req.KategoriKekerasanID)
+	if _, err := h.repos.ViolenceCategory.FindByID(categoryViolenceID); err != nil {
+		return helper.NotFound("category.not_found")
 	}
 
 	form, err := c.MultipartForm()
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to retrieve multipart form",
-		})
+		return helper.InternalError("common.multipart_failed")
 	}
 	files := form.File["dokumentasi"]
-	imageURLs, err := helper.UploadMultipleFileToCloudinary(files)
+	imageURLs, err := helper.UploadMultipleFileToCloudinary(h.uploader, files)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to upload images",
-		})
+		return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 	}
 
-	laporan.Dokumentasi = datatypes.JSONMap{"urls": imageURLs}
-
-	tanggalKejadian, err := time.Parse("2006-01-02T15:04:05", c.FormValue("tanggal_kejadian"))
-	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid format for tanggal kejadian",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
-	}
+	tanggalKejadian, _ := validation.ParseDateTime(req.TanggalKejadian)
 
 	year := time.Now().Year()
 	month := int(time.Now().Month())
-	noRegistrasi, err := generateUniqueNoRegistrasi(month, year)
+	noRegistrasi, err := h.generateUniqueNoRegistrasi(month, year)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to generate registration number",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
-	}
-
-	laporan.NoRegistrasi = noRegistrasi
-	laporan.TanggalPelaporan = time.Now()
-	laporan.TanggalKejadian = tanggalKejadian
-	laporan.KategoriLokasiKasus = c.FormValue("kategori_lokasi_kasus")
-	laporan.AlamatTKP = c.FormValue("alamat_tkp")
-	laporan.AlamatDetailTKP = c.FormValue("alamat_detail_tkp")
-	laporan.KronologisKasus = c.FormValue("kronologis_kasus")
-	laporan.Status = "Laporan masuk"
-	laporan.KategoriKekerasanID = uint(categoryViolenceID)
-	laporan.UserID = uint(userID)
-	laporan.CreatedAt = time.Now()
-	laporan.UpdatedAt = time.Now()
-	laporan.AlasanDibatalkan = ""
-	laporan.WaktuDilihat = nil
-	laporan.WaktuDiproses = nil
-	laporan.WaktuDibatalkan = nil
-	laporan.UserIDMelihat = nil
-
-	if err := database.GetGormDBInstance().Create(&laporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to create laporan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("laporan.registration_failed")
 	}
 
-	response := helper.ResponseWithData{
+	laporan := models.Laporan{
+		NoRegistrasi:        noRegistrasi,
+		TanggalPelaporan:    time.Now(),
+		TanggalKejadian:     tanggalKejadian,
+		KategoriLokasiKasus: req.KategoriLokasiKasus,
+		AlamatTKP:           req.AlamatTKP,
+		AlamatDetailTKP:     req.AlamatDetailTKP,
+		KronologisKasus:     req.KronologisKasus,
+		Status:              "Laporan masuk",
+		KategoriKekerasanID: categoryViolenceID,
+		UserID:              uint(userID),
+		Dokumentasi:         datatypes.JSONMap{"urls": imageURLs},
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+
+	if err := h.repos.Laporan.Create(&laporan); err != nil {
+		return helper.InternalError("laporan.create_failed")
+	}
+
+	response := helper.Response{
 		Code:    http.StatusCreated,
 		Status:  "success",
-		Message: "Laporan created successfully",
+		Message: i18n.T(c, "laporan.created"),
 		Data: fiber.Map{
 			"no_registrasi":         laporan.NoRegistrasi,
 			"user_id":               laporan.UserID,
@@ -152,24 +125,24 @@ func CreateLaporan(c *fiber.Ctx) error {
 	return c.Status(http.StatusCreated).JSON(response)
 }
 
-func generateUniqueNoRegistrasi(month, year int) (string, error) {
+func (h *Handler) generateUniqueNoRegistrasi(month, year int) (string, error) {
 	mu.Lock()
 	defer mu.Unlock()
 
 	romanMonth := convertToRoman(month)
 	regNo := "001-DPMDPPA-" + romanMonth + "-" + strconv.Itoa(year)
-	var existingCount int64
-	if err := database.GetGormDBInstance().Model(&models.Laporan{}).Where("no_registrasi = ?", regNo).Count(&existingCount).Error; err != nil {
+	exists, err := h.repos.Laporan.NoRegistrasiExists(regNo)
+	if err != nil {
 		return "", err
 	}
-	if existingCount > 0 {
+	if exists {
 		for i := 1; i < 1000; i++ {
 			modifiedRegNo := fmt.Sprintf("%03d", i) + "-DPMDPPA-" + romanMonth + "-" + strconv.Itoa(year)
-			var existingCount int64
-			if err := database.GetGormDBInstance().Model(&models.Laporan{}).Where("no_registrasi = ?", modifiedRegNo).Count(&existingCount).Error; err != nil {
+			exists, err := h.repos.Laporan.NoRegistrasiExists(modifiedRegNo)
+			if err != nil {
 				return "", err
 			}
-			if existingCount == 0 {
+			if !exists {
 				return modifiedRegNo, nil
 			}
 		}
@@ -188,116 +161,73 @@ func convertToRoman(month int) string {
 
 /*=========================== USER EDIT LAPORAN =======================*/
 
-func EditLaporan(c *fiber.Ctx) error {
+func (h *Handler) EditLaporan(c *fiber.Ctx) error {
 	token := c.Get("Authorization")
-	userID, err := auth.ExtractUserIDFromToken(token)
+	userID, err := h.jwt.ExtractUserIDFromToken(token)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized",
-		}
-		return c.Status(http.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("common.unauthorized")
 	}
 
 	noRegistrasi := c.Params("no_registrasi")
 
-	var laporan models.Laporan
-	if err := database.GetGormDBInstance().Where("no_registrasi = ?", noRegistrasi).First(&laporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Laporan not found",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
+	laporan, err := h.repos.Laporan.FindByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		return helper.NotFound("laporan.not_found")
 	}
 
 	if laporan.UserID != uint(userID) {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusForbidden,
-			Status:  "error",
-			Message: "You are not authorized to edit this laporan",
-		}
-		return c.Status(http.StatusForbidden).JSON(response)
+		return helper.Forbidden("laporan.edit_forbidden")
 	}
 
-	if err := c.BodyParser(&laporan); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+	var req EditLaporanRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
 
-	if newCategoryID := c.FormValue("kategori_kekerasan_id"); newCategoryID != "" {
-		categoryViolenceID, err := strconv.ParseUint(newCategoryID, 10, 64)
-		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Invalid KategoriKekerasan ID",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
-		}
-
-		var violenceCategory models.ViolenceCategory
-		if err := database.GetGormDBInstance().First(&violenceCategory, categoryViolenceID).Error; err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Violence category not found",
-			}
-			return c.Status(http.StatusNotFound).JSON(response)
+	if req.KategoriKekerasanID != "" {
+		categoryViolenceID := parseID(req.KategoriKekerasanID)
+		if _, err := h.repos.ViolenceCategory.FindByID(categoryViolenceID); err != nil {
+			return helper.NotFound("category.not_found")
 		}
-		laporan.KategoriKekerasanID = uint(categoryViolenceID)
+		laporan.KategoriKekerasanID = categoryViolenceID
 	}
 
-	tanggalKejadian := c.FormValue("tanggal_kejadian")
-	if tanggalKejadian != "" {
-		parsedTanggalKejadian, err := time.Parse("2006-01-02T15:04:05", tanggalKejadian)
-		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Invalid format for tanggal kejadian",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
-		}
-		laporan.TanggalKejadian = parsedTanggalKejadian
+	if req.TanggalKejadian != "" {
+		laporan.TanggalKejadian, _ = validation.ParseDateTime(req.TanggalKejadian)
 	}
 
 	form, err := c.MultipartForm()
 	if err == nil && form.File != nil && len(form.File["dokumentasi"]) > 0 {
 		files := form.File["dokumentasi"]
-		imageURLs, err := helper.UploadMultipleFileToCloudinary(files)
+		imageURLs, err := helper.UploadMultipleFileToCloudinary(h.uploader, files)
 		if err != nil {
-			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to upload images",
-			})
+			return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 		}
 		laporan.Dokumentasi = datatypes.JSONMap{"urls": imageURLs}
 	}
 
-	laporan.KategoriLokasiKasus = c.FormValue("kategori_lokasi_kasus")
-	laporan.AlamatTKP = c.FormValue("alamat_tkp")
-	laporan.AlamatDetailTKP = c.FormValue("alamat_detail_tkp")
-	laporan.KronologisKasus = c.FormValue("kronologis_kasus")
+	if req.KategoriLokasiKasus != "" {
+		laporan.KategoriLokasiKasus = req.KategoriLokasiKasus
+	}
+	if req.AlamatTKP != "" {
+		laporan.AlamatTKP = req.AlamatTKP
+	}
+	if req.AlamatDetailTKP != "" {
+		laporan.AlamatDetailTKP = req.AlamatDetailTKP
+	}
+	if req.KronologisKasus != "" {
+		laporan.KronologisKasus = req.KronologisKasus
+	}
 	laporan.UpdatedAt = time.Now()
 
-	if err := database.GetGormDBInstance().Save(&laporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update laporan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Laporan.Save(&laporan); err != nil {
+		return helper.InternalError("laporan.update_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Laporan updated successfully",
+		Message: i18n.T(c, "laporan.updated"),
 		Data:    laporan,
 	}
 
@@ -305,150 +235,88 @@ func EditLaporan(c *fiber.Ctx) error {
 }
 
 /*=========================== AMBIL SEMUA  LAPORAN SETIAP BERDASARKAN USER YANG LOGIN=======================*/
-func GetUserReports(c *fiber.Ctx) error {
-	userID, err := auth.ExtractUserIDFromToken(c.Get("Authorization"))
+func (h *Handler) GetUserReports(c *fiber.Ctx) error {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized",
-		}
-		return c.Status(http.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("common.unauthorized")
 	}
-	reports, err := GetReportsByUserID(userID)
+	reports, err := h.repos.Laporan.ListByUser(userID)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to get user reports",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("laporan.list_failed")
 	}
 
-	db := database.GetGormDBInstance()
 	var formattedReports []map[string]interface{}
 	for _, report := range reports {
-		var violenceCategory models.ViolenceCategory
-		if err := db.Where("id = ?", report["kategori_kekerasan_id"]).First(&violenceCategory).Error; err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to fetch violence category detail",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
-		}
-		report["violence_category_detail"] = violenceCategory
-		formattedReports = append(formattedReports, report)
+		formattedReports = append(formattedReports, formatUserReport(report))
 	}
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "List of laporan by user",
+		Message: i18n.T(c, "laporan.list"),
 		Data:    formattedReports,
 	}
 
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func GetReportsByUserID(userID uint) ([]map[string]interface{}, error) {
-	var reports []models.Laporan
-	if err := database.GetGormDBInstance().
-		Where("user_id = ?", userID).
-		Find(&reports).Error; err != nil {
-		return nil, err
+func formatUserReport(report models.Laporan) map[string]interface{} {
+	return map[string]interface{}{
+		"no_registrasi":            report.NoRegistrasi,
+		"user_id":                  report.UserID,
+		"kategori_kekerasan_id":    report.KategoriKekerasanID,
+		"tanggal_pelaporan":        report.TanggalPelaporan,
+		"tanggal_kejadian":         report.TanggalKejadian,
+		"kategori_lokasi_kasus":    report.KategoriLokasiKasus,
+		"alamat_tkp":               report.AlamatTKP,
+		"alamat_detail_tkp":        report.AlamatDetailTKP,
+		"kronologis_kasus":         report.KronologisKasus,
+		"status":                   report.Status,
+		"alasan_dibatalkan":        report.AlasanDibatalkan,
+		"waktu_dilihat":            report.WaktuDilihat,
+		"userid_melihat":           report.UserIDMelihat,
+		"waktu_diproses":           report.WaktuDiproses,
+		"waktu_dibatalkan":         report.WaktuDibatalkan,
+		"dokumentasi":              report.Dokumentasi,
+		"created_at":               report.CreatedAt,
+		"updated_at":               report.UpdatedAt,
+		"violence_category_detail": report.ViolenceCategory,
 	}
-
-	var formattedReports []map[string]interface{}
-	for _, report := range reports {
-		formattedReport := map[string]interface{}{
-			"no_registrasi":         report.NoRegistrasi,
-			"user_id":               report.UserID,
-			"kategori_kekerasan_id": report.KategoriKekerasanID,
-			"tanggal_pelaporan":     report.TanggalPelaporan,
-			"tanggal_kejadian":      report.TanggalKejadian,
-			"kategori_lokasi_kasus": report.KategoriLokasiKasus,
-			"alamat_tkp":            report.AlamatTKP,
-			"alamat_detail_tkp":     report.AlamatDetailTKP,
-			"kronologis_kasus":      report.KronologisKasus,
-			"status":                report.Status,
-			"alasan_dibatalkan":     report.AlasanDibatalkan,
-			"waktu_dilihat":         report.WaktuDilihat,
-			"userid_melihat":        report.UserIDMelihat,
-			"waktu_diproses":        report.WaktuDiproses,
-			"waktu_dibatalkan":      report.WaktuDibatalkan,
-			"dokumentasi":           report.Dokumentasi,
-			"created_at":            report.CreatedAt,
-			"updated_at":            report.UpdatedAt,
-		}
-		formattedReports = append(formattedReports, formattedReport)
-	}
-
-	return formattedReports, nil
 }
 
 /*=========================== TAMPILKAN DETAIL LAPORAN USER BERDASARKAN NO_REGISTRASI =======================*/
-func GetReportByNoRegistrasi(c *fiber.Ctx) error {
+func (h *Handler) GetReportByNoRegistrasi(c *fiber.Ctx) error {
 	noRegistrasi := c.Params("no_registrasi")
-	var laporan models.Laporan
-	db := database.GetGormDBInstance()
 
 	// Preload the necessary related data
-	if err := db.
-		Preload("User").
-		Preload("ViolenceCategory").
-		Where("no_registrasi = ?", noRegistrasi).
-		First(&laporan).Error; err != nil {
+	laporan, err := h.repos.Laporan.FindDetail(noRegistrasi)
+	if err != nil {
 		status := http.StatusInternalServerError
 		message := "Failed to fetch report detail"
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, repository.ErrNotFound) {
 			status = http.StatusNotFound
 			message = "Report not found"
 		}
-		response := helper.ResponseWithOutData{
-			Code:    status,
-			Status:  "error",
-			Message: message,
-		}
-		return c.Status(status).JSON(response)
+		return helper.NewStatusError(status, message)
 	}
 
 	// Fetch tracking laporan details
-	var trackingLaporan []models.TrackingLaporan
-	if err := db.Where("no_registrasi = ?", noRegistrasi).Find(&trackingLaporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to fetch tracking laporan details",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
-	}
-	var pelaku []models.Pelaku
-	if err := db.Where("no_registrasi = ?", noRegistrasi).Find(&pelaku).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to fetch pelaku details",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
-	}
-	var korban []models.Korban
-	if err := db.Where("no_registrasi = ?", noRegistrasi).Find(&korban).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to fetch korban details",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	trackingLaporan, err := h.repos.Tracking.ListByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		return helper.InternalError("laporan.tracking_failed")
+	}
+	pelaku, err := h.repos.Pelaku.ListByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		return helper.InternalError("laporan.pelaku_failed")
+	}
+	korban, err := h.repos.Korban.ListByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		return helper.InternalError("laporan.korban_failed")
 	}
 	var userMelihat models.User
 	if laporan.UserIDMelihat != nil {
-		if err := db.First(&userMelihat, *laporan.UserIDMelihat).Error; err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to fetch user detail who viewed the report",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+		userMelihat, err = h.repos.Users.FindByID(*laporan.UserIDMelihat)
+		if err != nil {
+			return helper.InternalError("laporan.viewer_failed")
 		}
 	}
 	responseData := struct {
@@ -468,10 +336,10 @@ func GetReportByNoRegistrasi(c *fiber.Ctx) error {
 		responseData.UserMelihat = &userMelihat
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Report detail retrieved successfully",
+		Message: i18n.T(c, "laporan.detail"),
 		Data:    responseData,
 	}
 
@@ -479,56 +347,39 @@ func GetReportByNoRegistrasi(c *fiber.Ctx) error {
 }
 
 /*=========================== BATALKAN LAPORAN BERDASARKAN NO_REGISTRASI =======================*/
-func BatalkanLaporan(c *fiber.Ctx) error {
+func (h *Handler) BatalkanLaporan(c *fiber.Ctx) error {
 	noRegistrasi := c.Params("no_registrasi")
 
-	var laporan models.Laporan
-	db := database.GetGormDBInstance()
-	if err := db.Where("no_registrasi = ?", noRegistrasi).First(&laporan).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Laporan not found",
-			}
-			return c.Status(http.StatusNotFound).JSON(response)
-		}
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to retrieve laporan",
+	laporan, err := h.repos.Laporan.FindByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("laporan.not_found")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("laporan.retrieve_failed")
 	}
 
-	alasanDibatalkan := c.FormValue("alasan_dibatalkan")
-	if alasanDibatalkan == "" {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Alasan dibatalkan is required",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+	var req BatalkanLaporanRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
 
 	laporan.Status = "Dibatalkan"
-	laporan.AlasanDibatalkan = alasanDibatalkan
+	laporan.AlasanDibatalkan = req.AlasanDibatalkan
 	now := time.Now()
 	laporan.WaktuDibatalkan = &now
 
-	if err := db.Save(&laporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update laporan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Laporan.Save(&laporan); err != nil {
+		return helper.InternalError("laporan.update_failed")
+	}
+	// Petugas yang sudah melihat laporan perlu tahu bahwa laporannya dibatalkan.
+	if laporan.UserIDMelihat != nil {
+		h.notifyLaporan(laporan, *laporan.UserIDMelihat, models.NotificationLaporanDibatalkan, laporan.AlasanDibatalkan)
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Laporan cancelled successfully",
+		Message: i18n.T(c, "laporan.cancelled"),
 		Data: fiber.Map{
 			"no_registrasi":     laporan.NoRegistrasi,
 			"status":            laporan.Status,
@@ -541,44 +392,28 @@ func BatalkanLaporan(c *fiber.Ctx) error {
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func SelesaikanLaporan(c *fiber.Ctx) error {
+func (h *Handler) SelesaikanLaporan(c *fiber.Ctx) error {
 	noRegistrasi := c.Params("no_registrasi")
 
-	var laporan models.Laporan
-	db := database.GetGormDBInstance()
-	if err := db.Where("no_registrasi = ?", noRegistrasi).First(&laporan).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Laporan not found",
-			}
-			return c.Status(http.StatusNotFound).JSON(response)
-		}
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to retrieve laporan",
+	laporan, err := h.repos.Laporan.FindByNoRegistrasi(noRegistrasi)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("laporan.not_found")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("laporan.retrieve_failed")
 	}
 
 	laporan.Status = "Selesai"
 	laporan.UpdatedAt = time.Now()
 
-	if err := db.Save(&laporan).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update laporan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Laporan.Save(&laporan); err != nil {
+		return helper.InternalError("laporan.update_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Laporan completed successfully",
+		Message: i18n.T(c, "laporan.completed"),
 		Data: fiber.Map{
 			"no_registrasi": laporan.NoRegistrasi,
 			"status":        laporan.Status,
@@ -588,4 +423,3 @@ func SelesaikanLaporan(c *fiber.Ctx) error {
 
 	return c.Status(http.StatusOK).JSON(response)
 }
-
@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/validation"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,216 +12,203 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func CreatePelaku(c *fiber.Ctx) error {
-	var pelaku models.Pelaku
-	if err := c.BodyParser(&pelaku); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+// Identitas pelaku sering belum diketahui saat laporan dibuat, jadi hanya
+// no_registrasi yang wajib diisi.
+type CreatePelakuRequest struct {
+	NoRegistrasi         string `json:"no_registrasi" form:"no_registrasi" validate:"required"`
+	NIKPelaku            string `json:"nik_pelaku" form:"nik_pelaku" validate:"nik"`
+	Nama                 string `json:"nama_pelaku" form:"nama_pelaku" validate:"max=100"`
+	Usia                 string `json:"usia_pelaku" form:"usia_pelaku" validate:"int=0:150"`
+	AlamatPelaku         string `json:"alamat_pelaku" form:"alamat_pelaku" validate:"max=255"`
+	AlamatDetail         string `json:"alamat_detail" form:"alamat_detail" validate:"max=255"`
+	JenisKelamin         string `json:"jenis_kelamin" form:"jenis_kelamin" validate:"oneof=Laki-laki|Perempuan"`
+	Agama                string `json:"agama" form:"agama" validate:"max=50"`
+	NoTelepon            string `json:"no_telepon" form:"no_telepon" validate:"phone"`
+	Pendidikan           string `json:"pendidikan" form:"pendidikan" validate:"max=100"`
+	Pekerjaan            string `json:"pekerjaan" form:"pekerjaan" validate:"max=100"`
+	StatusPerkawinan     string `json:"status_perkawinan" form:"status_perkawinan" validate:"oneof=Belum Kawin|Kawin|Cerai Hidup|Cerai Mati"`
+	Kebangsaan           string `json:"kebangsaan" form:"kebangsaan" validate:"max=100"`
+	HubunganDenganKorban string `json:"hubungan_dengan_korban" form:"hubungan_dengan_korban" validate:"max=100"`
+	KeteranganLainnya    string `json:"keterangan_lainnya" form:"keterangan_lainnya" validate:"max=1000"`
+}
+
+// UpdatePelakuRequest: field yang kosong tidak diubah.
+type UpdatePelakuRequest struct {
+	NoRegistrasi         string `json:"no_registrasi" form:"no_registrasi"`
+	NIKPelaku            string `json:"nik_pelaku" form:"nik_pelaku" validate:"nik"`
+	Nama                 string `json:"nama_pelaku" form:"nama_pelaku" validate:"max=100"`
+	Usia                 string `json:"usia_pelaku" form:"usia_pelaku" validate:"int=0:150"`
+	AlamatPelaku         string `json:"alamat_pelaku" form:"alamat_pelaku" validate:"max=255"`
+	AlamatDetail         string `json:"alamat_detail" form:"alamat_detail" validate:"max=255"`
+	JenisKelamin         string `json:"jenis_kelamin" form:"jenis_kelamin" validate:"oneof=Laki-laki|Perempuan"`
+	Agama                string `json:"agama" form:"agama" validate:"max=50"`
+	NoTelepon            string `json:"no_telepon" form:"no_telepon" validate:"phone"`
+	Pendidikan           string `json:"pendidikan" form:"pendidikan" validate:"max=100"`
+	Pekerjaan            string `json:"pekerjaan" form:"pekerjaan" validate:"max=100"`
+	StatusPerkawinan     string `json:"status_perkawinan" form:"status_perkawinan" validate:"oneof=Belum Kawin|Kawin|Cerai Hidup|Cerai Mati"`
+	Kebangsaan           string `json:"kebangsaan" form:"kebangsaan" validate:"max=100"`
+	HubunganDenganKorban string `json:"hubungan_dengan_korban" form:"hubungan_dengan_korban" validate:"max=100"`
+	KeteranganLainnya    string `json:"keterangan_lainnya" form:"keterangan_lainnya" validate:"max=1000"`
+}
+
+func (h *Handler) CreatePelaku(c *fiber.Ctx) error {
+	var req CreatePelakuRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	if _, err := h.repos.Laporan.FindByNoRegistrasi(req.NoRegistrasi); err != nil {
+		return helper.NotFound("laporan.not_found")
+	}
+	usia, _ := strconv.Atoi(req.Usia)
+	pelaku := models.Pelaku{
+		NoRegistrasi:         req.NoRegistrasi,
+		NIKPelaku:            req.NIKPelaku,
+		Nama:                 req.Nama,
+		Usia:                 usia,
+		AlamatPelaku:         req.AlamatPelaku,
+		AlamatDetail:         req.AlamatDetail,
+		JenisKelamin:         req.JenisKelamin,
+		Agama:                req.Agama,
+		NoTelepon:            req.NoTelepon,
+		Pendidikan:           req.Pendidikan,
+		Pekerjaan:            req.Pekerjaan,
+		StatusPerkawinan:     req.StatusPerkawinan,
+		Kebangsaan:           req.Kebangsaan,
+		HubunganDenganKorban: req.HubunganDenganKorban,
+		KeteranganLainnya:    req.KeteranganLainnya,
 	}
-	pelaku.NoRegistrasi = c.FormValue("no_registrasi")
-	pelaku.NIKPelaku = c.FormValue("nik_pelaku")
-	pelaku.Nama = c.FormValue("nama_pelaku")
-	usia, err := strconv.Atoi(c.FormValue("usia_pelaku"))
-	if err == nil {
-		pelaku.Usia = usia
-	}
-	pelaku.AlamatPelaku = c.FormValue("alamat_pelaku")
-	pelaku.AlamatDetail = c.FormValue("alamat_detail")
-	pelaku.JenisKelamin = c.FormValue("jenis_kelamin")
-	pelaku.Agama = c.FormValue("agama")
-	pelaku.NoTelepon = c.FormValue("no_telepon")
-	pelaku.Pendidikan = c.FormValue("pendidikan")
-	pelaku.Pekerjaan = c.FormValue("pekerjaan")
-	pelaku.StatusPerkawinan = c.FormValue("status_perkawinan")
-	pelaku.Kebangsaan = c.FormValue("kebangsaan")
-	pelaku.HubunganDenganKorban = c.FormValue("hubungan_dengan_korban")
-	pelaku.KeteranganLainnya = c.FormValue("keterangan_lainnya")
 
 	file, err := c.FormFile("dokumentasi_pelaku")
 	if err == nil {
 		src, err := file.Open()
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to open image file",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.InternalError("common.image_open_failed")
 		}
 		defer src.Close()
 
-		imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+		imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Gagal Mengupload Gambar",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 		}
 
 		pelaku.DokumentasiPelaku = imageURL
 	}
 	pelaku.CreatedAt = time.Now()
 	pelaku.UpdatedAt = time.Now()
-	if err := database.DB.Create(&pelaku).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "gagal Menambahkan Data pelaku",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Pelaku.Create(&pelaku); err != nil {
+		return helper.InternalError("pelaku.create_failed")
 	}
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusCreated,
 		Status:  "success",
-		Message: "Berhasil Menambah Data Pelaku",
+		Message: i18n.T(c, "pelaku.created"),
 		Data:    pelaku,
 	}
 	return c.Status(http.StatusCreated).JSON(response)
 }
 
-func UpdatePelaku(c *fiber.Ctx) error {
-	id := c.Params("id")
-	var pelaku models.Pelaku
-	if err := database.DB.First(&pelaku, id).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Pelaku Tidak DItemukan",
+func (h *Handler) UpdatePelaku(c *fiber.Ctx) error {
+	pelaku, err := h.repos.Pelaku.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("pelaku.not_found")
+	}
+	var req UpdatePelakuRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	if req.NoRegistrasi != "" && req.NoRegistrasi != pelaku.NoRegistrasi {
+		if _, err := h.repos.Laporan.FindByNoRegistrasi(req.NoRegistrasi); err != nil {
+			return helper.NotFound("laporan.not_found")
 		}
-		return c.Status(http.StatusNotFound).JSON(response)
+		pelaku.NoRegistrasi = req.NoRegistrasi
 	}
-	if err := c.BodyParser(&pelaku); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+	if req.NIKPelaku != "" {
+		pelaku.NIKPelaku = req.NIKPelaku
 	}
-	if value := c.FormValue("no_registrasi"); value != "" {
-		pelaku.NoRegistrasi = value
+	if req.Nama != "" {
+		pelaku.Nama = req.Nama
 	}
-	if value := c.FormValue("nik_pelaku"); value != "" {
-		pelaku.NIKPelaku = value
+	if req.Usia != "" {
+		pelaku.Usia, _ = strconv.Atoi(req.Usia)
 	}
-	if value := c.FormValue("nama_pelaku"); value != "" {
-		pelaku.Nama = value
+	if req.AlamatPelaku != "" {
+		pelaku.AlamatPelaku = req.AlamatPelaku
 	}
-	if value := c.FormValue("usia_pelaku"); value != "" {
-		if usia, err := strconv.Atoi(value); err == nil {
-			pelaku.Usia = usia
-		}
-	}
-	if value := c.FormValue("alamat_pelaku"); value != "" {
-		pelaku.AlamatPelaku = value
+	if req.AlamatDetail != "" {
+		pelaku.AlamatDetail = req.AlamatDetail
 	}
-	if value := c.FormValue("alamat_detail"); value != "" {
-		pelaku.AlamatDetail = value
+	if req.JenisKelamin != "" {
+		pelaku.JenisKelamin = req.JenisKelamin
 	}
-	if value := c.FormValue("jenis_kelamin"); value != "" {
-		pelaku.JenisKelamin = value
+	if req.Agama != "" {
+		pelaku.Agama = req.Agama
 	}
-	if value := c.FormValue("agama"); value != "" {
-		pelaku.Agama = value
+	if req.NoTelepon != "" {
+		pelaku.NoTelepon = req.NoTelepon
 	}
-	if value := c.FormValue("no_telepon"); value != "" {
-		pelaku.NoTelepon = value
+	if req.Pendidikan != "" {
+		pelaku.Pendidikan = req.Pendidikan
 	}
-	if value := c.FormValue("pendidikan"); value != "" {
-		pelaku.Pendidikan = value
+	if req.Pekerjaan != "" {
+		pelaku.Pekerjaan = req.Pekerjaan
 	}
-	if value := c.FormValue("pekerjaan"); value != "" {
-		pelaku.Pekerjaan = value
+	if req.StatusPerkawinan != "" {
+		pelaku.StatusPerkawinan = req.StatusPerkawinan
 	}
-	if value := c.FormValue("status_perkawinan"); value != "" {
-		pelaku.StatusPerkawinan = value
+	if req.Kebangsaan != "" {
+		pelaku.Kebangsaan = req.Kebangsaan
 	}
-	if value := c.FormValue("kebangsaan"); value != "" {
-		pelaku.Kebangsaan = value
+	if req.HubunganDenganKorban != "" {
+		pelaku.HubunganDenganKorban = req.HubunganDenganKorban
 	}
-	if value := c.FormValue("hubungan_dengan_korban"); value != "" {
-		pelaku.HubunganDenganKorban = value
-	}
-	if value := c.FormValue("keterangan_lainnya"); value != "" {
-		pelaku.KeteranganLainnya = value
+	if req.KeteranganLainnya != "" {
+		pelaku.KeteranganLainnya = req.KeteranganLainnya
 	}
 
 	file, err := c.FormFile("dokumentasi_pelaku")
 	if err == nil {
 		src, err := file.Open()
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to open image file",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.InternalError("common.image_open_failed")
 		}
 		defer src.Close()
 
-		imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+		imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Gagal Mengupload Gambat",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 		}
 
 		pelaku.DokumentasiPelaku = imageURL
 	}
 
 	pelaku.UpdatedAt = time.Now()
-	if err := database.DB.Save(&pelaku).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal Mengupdate Data Pelaku",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Pelaku.Save(&pelaku); err != nil {
+		return helper.InternalError("pelaku.update_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Berhasil Mengupdated Data Pelaku",
+		Message: i18n.T(c, "pelaku.updated"),
 		Data:    pelaku,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func DeletePelaku(c *fiber.Ctx) error {
-	id := c.Params("id")
-	var pelaku models.Pelaku
-	if err := database.DB.First(&pelaku, id).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Pelaku Tidak Ditemukan",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
+func (h *Handler) DeletePelaku(c *fiber.Ctx) error {
+	pelaku, err := h.repos.Pelaku.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("pelaku.not_found")
 	}
 
-	if err := database.DB.Delete(&pelaku).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal Menghapus Data Pelaku",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.Pelaku.Delete(&pelaku); err != nil {
+		return helper.InternalError("pelaku.delete_failed")
 	}
 
-	response := helper.ResponseWithOutData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Berhasil Menghapus Data Pelaku",
+		Message: i18n.T(c, "pelaku.deleted"),
 	}
 	return c.Status(http.StatusOK).JSON(response)
-}
\ No newline at end of file
+}
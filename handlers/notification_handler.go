@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const notificationTimeLayout = "02/01/2006 15:04"
+
+// GetNotifications menampilkan kotak masuk user yang login, terbaru lebih
+// dulu. Query unread=true hanya menampilkan yang belum dibaca.
+func (h *Handler) GetNotifications(c *fiber.Ctx) error {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return helper.Unauthorized("common.unauthorized")
+	}
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	notifications, total, err := h.repos.Notifications.ListByUser(userID, c.Query("unread") == "true", repository.Page{Page: page, Limit: limit})
+	if err != nil {
+		return helper.InternalError("notifikasi.list_failed")
+	}
+	unread, err := h.repos.Notifications.CountUnread(userID)
+	if err != nil {
+		return helper.InternalError("notifikasi.list_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "notifikasi.list"),
+		Data: fiber.Map{
+			"notifications": notifications,
+			"unread":        unread,
+			"pagination": fiber.Map{
+				"page":  page,
+				"limit": limit,
+				"total": total,
+			},
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) GetUnreadNotificationCount(c *fiber.Ctx) error {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return helper.Unauthorized("common.unauthorized")
+	}
+	unread, err := h.repos.Notifications.CountUnread(userID)
+	if err != nil {
+		return helper.InternalError("notifikasi.list_failed")
+	}
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "notifikasi.unread_count"),
+		Data:    fiber.Map{"unread": unread},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// ReadNotification menandai satu notifikasi milik user sebagai dibaca.
+func (h *Handler) ReadNotification(c *fiber.Ctx) error {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return helper.Unauthorized("common.unauthorized")
+	}
+	notification, err := h.repos.Notifications.MarkRead(userID, parseID(c.Params("id")), time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("notifikasi.not_found")
+		}
+		return helper.InternalError("notifikasi.update_failed")
+	}
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "notifikasi.read"),
+		Data:    notification,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) ReadAllNotifications(c *fiber.Ctx) error {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return helper.Unauthorized("common.unauthorized")
+	}
+	updated, err := h.repos.Notifications.MarkAllRead(userID, time.Now())
+	if err != nil {
+		return helper.InternalError("notifikasi.update_failed")
+	}
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "notifikasi.read_all"),
+		Data:    fiber.Map{"updated": updated},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// notify membuat notifikasi untuk notification.UserID dengan judul dan isi
+// "notifikasi.<type>_title" dan "notifikasi.<type>_body" dalam bahasa
+// penerima. Perubahan status yang memicunya sudah tersimpan, jadi kegagalan
+// di sini hanya dicatat di log.
+func (h *Handler) notify(notification models.Notification, args ...interface{}) {
+	user, err := h.repos.Users.FindByID(notification.UserID)
+	if err != nil {
+		log.Printf("notifikasi: failed to load user %d: %v", notification.UserID, err)
+		return
+	}
+	notification.Title = i18n.Translate(user.Locale, "notifikasi."+notification.Type+"_title")
+	notification.Body = i18n.Translate(user.Locale, "notifikasi."+notification.Type+"_body", args...)
+	if err := h.repos.Notifications.Create(&notification); err != nil {
+		log.Printf("notifikasi: failed to create %s for user %d: %v", notification.Type, notification.UserID, err)
+	}
+}
+
+func (h *Handler) notifyLaporan(laporan models.Laporan, userID uint, jenis string, args ...interface{}) {
+	noRegistrasi := laporan.NoRegistrasi
+	h.notify(models.Notification{UserID: userID, Type: jenis, NoRegistrasi: &noRegistrasi}, append([]interface{}{noRegistrasi}, args...)...)
+}
+
+func (h *Handler) notifyJanjiTemu(janjiTemu models.JanjiTemu, userID uint, jenis string, args ...interface{}) {
+	id := janjiTemu.ID
+	waktu := janjiTemu.WaktuDimulai.Format(notificationTimeLayout)
+	h.notify(models.Notification{UserID: userID, Type: jenis, JanjiTemuID: &id}, append([]interface{}{waktu}, args...)...)
+}
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/validation"
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PenilaianLayananRequest struct {
+	Rating   string `json:"rating" form:"rating" validate:"required,oneof=1|2|3|4|5"`
+	Komentar string `json:"komentar" form:"komentar" validate:"max=1000"`
+}
+
+// StatistikPenilaianQuery: dari dan sampai adalah tanggal pembuatan penilaian
+// (inklusif); kosong berarti tidak dibatasi.
+type StatistikPenilaianQuery struct {
+	Dari   string `query:"dari" form:"dari" validate:"date"`
+	Sampai string `query:"sampai" form:"sampai" validate:"date"`
+}
+
+// RingkasanPenilaian: distribusi berisi jumlah penilaian untuk rating 1
+// sampai 5, dan persen_puas adalah persentase rating 4 atau 5.
+type RingkasanPenilaian struct {
+	Jumlah     int     `json:"jumlah"`
+	RataRata   float64 `json:"rata_rata"`
+	PersenPuas float64 `json:"persen_puas"`
+	Distribusi [5]int  `json:"distribusi"`
+}
+
+type PenilaianPerKonselor struct {
+	KonselorID   uint   `json:"konselor_id"`
+	NamaKonselor string `json:"nama_konselor"`
+	RingkasanPenilaian
+}
+
+type PenilaianPerKategori struct {
+	KategoriKekerasanID uint   `json:"kategori_kekerasan_id"`
+	NamaKategori        string `json:"nama_kategori"`
+	RingkasanPenilaian
+}
+
+type PenilaianPerBulan struct {
+	Bulan string `json:"bulan"`
+	RingkasanPenilaian
+}
+
+// StatistikPenilaian dihitung dari semua penilaian; per_konselor hanya
+// berisi penilaian janji temu dan per_kategori hanya penilaian laporan.
+type StatistikPenilaian struct {
+	Keseluruhan RingkasanPenilaian     `json:"keseluruhan"`
+	PerKonselor []PenilaianPerKonselor `json:"per_konselor"`
+	PerKategori []PenilaianPerKategori `json:"per_kategori"`
+	PerBulan    []PenilaianPerBulan    `json:"per_bulan"`
+}
+
+// MasyarakatNilaiJanjiTemu menyimpan penilaian pemilik janji temu setelah
+// konsultasinya selesai. Setiap janji temu hanya bisa dinilai sekali.
+func (h *Handler) MasyarakatNilaiJanjiTemu(c *fiber.Ctx) error {
+	var req PenilaianLayananRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	janjiTemu, userID, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	if janjiTemu.Status != models.JanjiTemuSelesai {
+		return helper.Conflict("penilaian.janji_temu_not_completed")
+	}
+	penilaian := models.PenilaianLayanan{
+		UserID:      userID,
+		JanjiTemuID: &janjiTemu.ID,
+		KonselorID:  janjiTemu.KonselorID,
+	}
+	return h.simpanPenilaian(c, req, &penilaian)
+}
+
+// MasyarakatNilaiLaporan menyimpan penilaian pelapor setelah laporannya
+// selesai ditangani. Setiap laporan hanya bisa dinilai sekali.
+func (h *Handler) MasyarakatNilaiLaporan(c *fiber.Ctx) error {
+	var req PenilaianLayananRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	laporan, userID, err := h.findLaporanMilik(c)
+	if err != nil {
+		return err
+	}
+	if laporan.Status != "Selesai" {
+		return helper.Conflict("penilaian.laporan_not_completed")
+	}
+	kategoriID := laporan.KategoriKekerasanID
+	penilaian := models.PenilaianLayanan{
+		UserID:              userID,
+		NoRegistrasi:        &laporan.NoRegistrasi,
+		KategoriKekerasanID: &kategoriID,
+	}
+	return h.simpanPenilaian(c, req, &penilaian)
+}
+
+func (h *Handler) MasyarakatGetPenilaianJanjiTemu(c *fiber.Ctx) error {
+	janjiTemu, _, err := h.findJanjiTemuMilik(c)
+	if err != nil {
+		return err
+	}
+	penilaian, err := h.repos.PenilaianLayanan.FindByJanjiTemu(janjiTemu.ID)
+	return penilaianResponse(c, penilaian, err)
+}
+
+func (h *Handler) MasyarakatGetPenilaianLaporan(c *fiber.Ctx) error {
+	laporan, _, err := h.findLaporanMilik(c)
+	if err != nil {
+		return err
+	}
+	penilaian, err := h.repos.PenilaianLayanan.FindByLaporan(laporan.NoRegistrasi)
+	return penilaianResponse(c, penilaian, err)
+}
+
+// AdminStatistikPenilaian merangkum penilaian kepuasan secara keseluruhan,
+// per konselor, per kategori kekerasan, dan per bulan.
+func (h *Handler) AdminStatistikPenilaian(c *fiber.Ctx) error {
+	var query StatistikPenilaianQuery
+	if err := c.QueryParser(&query); err != nil {
+		return helper.BadRequest("common.invalid_request_body")
+	}
+	if err := validation.Validate(&query); err != nil {
+		return err
+	}
+	dari, _ := validation.ParseDate(query.Dari)
+	sampai, _ := validation.ParseDate(query.Sampai)
+	if !sampai.IsZero() {
+		sampai = sampai.AddDate(0, 0, 1)
+	}
+
+	penilaian, err := h.repos.PenilaianLayanan.List(dari, sampai)
+	if err != nil {
+		return helper.InternalError("penilaian.statistics_failed")
+	}
+	statistik, err := h.hitungStatistik(penilaian)
+	if err != nil {
+		return helper.InternalError("penilaian.statistics_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "penilaian.statistics"),
+		Data:    statistik,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// findLaporanMilik memuat laporan dari parameter :no_registrasi. Laporan
+// milik user lain dianggap tidak ada.
+func (h *Handler) findLaporanMilik(c *fiber.Ctx) (models.Laporan, uint, error) {
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return models.Laporan{}, 0, helper.Unauthorized("common.unauthorized")
+	}
+	laporan, err := h.repos.Laporan.FindByNoRegistrasi(c.Params("no_registrasi"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return laporan, userID, helper.NotFound("laporan.not_found")
+		}
+		return laporan, userID, helper.InternalError("laporan.retrieve_failed")
+	}
+	if laporan.UserID != userID {
+		return laporan, userID, helper.NotFound("laporan.not_found")
+	}
+	return laporan, userID, nil
+}
+
+func (h *Handler) simpanPenilaian(c *fiber.Ctx, req PenilaianLayananRequest, penilaian *models.PenilaianLayanan) error {
+	penilaian.Rating, _ = strconv.Atoi(req.Rating)
+	penilaian.Komentar = req.Komentar
+	if err := h.repos.PenilaianLayanan.Create(penilaian); err != nil {
+		if errors.Is(err, repository.ErrSudahDinilai) {
+			return helper.Conflict("penilaian.already_submitted")
+		}
+		return helper.InternalError("penilaian.create_failed")
+	}
+	response := helper.Response{
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: i18n.T(c, "penilaian.created"),
+		Data:    penilaian,
+	}
+	return c.Status(http.StatusCreated).JSON(response)
+}
+
+func penilaianResponse(c *fiber.Ctx, penilaian models.PenilaianLayanan, err error) error {
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return helper.NotFound("penilaian.not_found")
+		}
+		return helper.InternalError("penilaian.retrieve_failed")
+	}
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "penilaian.detail"),
+		Data:    penilaian,
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) hitungStatistik(penilaian []models.PenilaianLayanan) (StatistikPenilaian, error) {
+	var keseluruhan akumulasiPenilaian
+	perKonselor := map[uint]*akumulasiPenilaian{}
+	perKategori := map[uint]*akumulasiPenilaian{}
+	perBulan := map[string]*akumulasiPenilaian{}
+	tambah := func(m map[uint]*akumulasiPenilaian, id *uint, rating int) {
+		if id == nil {
+			return
+		}
+		if m[*id] == nil {
+			m[*id] = &akumulasiPenilaian{}
+		}
+		m[*id].tambah(rating)
+	}
+	for _, p := range penilaian {
+		keseluruhan.tambah(p.Rating)
+		tambah(perKonselor, p.KonselorID, p.Rating)
+		tambah(perKategori, p.KategoriKekerasanID, p.Rating)
+		bulan := p.CreatedAt.UTC().Format("2006-01")
+		if perBulan[bulan] == nil {
+			perBulan[bulan] = &akumulasiPenilaian{}
+		}
+		perBulan[bulan].tambah(p.Rating)
+	}
+
+	statistik := StatistikPenilaian{
+		Keseluruhan: keseluruhan.ringkasan(),
+		PerKonselor: []PenilaianPerKonselor{},
+		PerKategori: []PenilaianPerKategori{},
+		PerBulan:    []PenilaianPerBulan{},
+	}
+	if len(perKonselor) > 0 {
+		konselorIDs := urutID(perKonselor)
+		konselors, err := h.repos.Users.ListByIDs(konselorIDs)
+		if err != nil {
+			return statistik, err
+		}
+		// Nama konselor yang akunnya sudah dihapus dibiarkan kosong.
+		nama := map[uint]string{}
+		for _, konselor := range konselors {
+			nama[konselor.ID] = konselor.FullName
+		}
+		for _, id := range konselorIDs {
+			statistik.PerKonselor = append(statistik.PerKonselor, PenilaianPerKonselor{
+				KonselorID:         id,
+				NamaKonselor:       nama[id],
+				RingkasanPenilaian: perKonselor[id].ringkasan(),
+			})
+		}
+	}
+	if len(perKategori) > 0 {
+		categories, err := h.repos.ViolenceCategory.List()
+		if err != nil {
+			return statistik, err
+		}
+		nama := map[uint]string{}
+		for _, category := range categories {
+			nama[uint(category.ID)] = category.CategoryName
+		}
+		for _, id := range urutID(perKategori) {
+			statistik.PerKategori = append(statistik.PerKategori, PenilaianPerKategori{
+				KategoriKekerasanID: id,
+				NamaKategori:        nama[id],
+				RingkasanPenilaian:  perKategori[id].ringkasan(),
+			})
+		}
+	}
+	bulan := make([]string, 0, len(perBulan))
+	for b := range perBulan {
+		bulan = append(bulan, b)
+	}
+	sort.Strings(bulan)
+	for _, b := range bulan {
+		statistik.PerBulan = append(statistik.PerBulan, PenilaianPerBulan{
+			Bulan:              b,
+			RingkasanPenilaian: perBulan[b].ringkasan(),
+		})
+	}
+	return statistik, nil
+}
+
+type akumulasiPenilaian struct {
+	jumlah     int
+	total      int
+	distribusi [5]int
+}
+
+func (a *akumulasiPenilaian) tambah(rating int) {
+	if rating < 1 || rating > 5 {
+		return
+	}
+	a.jumlah++
+	a.total += rating
+	a.distribusi[rating-1]++
+}
+
+func (a akumulasiPenilaian) ringkasan() RingkasanPenilaian {
+	ringkasan := RingkasanPenilaian{Jumlah: a.jumlah, Distribusi: a.distribusi}
+	if a.jumlah > 0 {
+		ringkasan.RataRata = bulatkan(float64(a.total) / float64(a.jumlah))
+		puas := a.distribusi[3] + a.distribusi[4]
+		ringkasan.PersenPuas = bulatkan(float64(puas) * 100 / float64(a.jumlah))
+	}
+	return ringkasan
+}
+
+func urutID(m map[uint]*akumulasiPenilaian) []uint {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// bulatkan membulatkan ke dua angka di belakang koma.
+func bulatkan(x float64) float64 {
+	return math.Round(x*100) / 100
+}
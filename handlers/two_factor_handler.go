@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/models"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+	qrcode "github.com/skip2/go-qrcode"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	twoFactorIssuer         = "PEDIKA"
+	twoFactorChallengeTTL   = 5 * time.Minute
+	twoFactorChallengeClaim = "2fa_challenge"
+	recoveryCodeCount       = 10
+)
+
+type TwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" form:"challenge_token"`
+	Code           string `json:"code" form:"code"`
+	RecoveryCode   string `json:"recovery_code" form:"recovery_code"`
+	Password       string `json:"password" form:"password"`
+}
+
+/*=========================== LOGIN LANGKAH KEDUA (2FA) =======================*/
+func (h *Handler) LoginTwoFactor(c *fiber.Ctx) error {
+	var req TwoFactorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.BadRequest("common.invalid_request_body")
+	}
+
+	userID, err := h.parseTwoFactorChallengeToken(req.ChallengeToken)
+	if err != nil {
+		return helper.Unauthorized("auth.session_expired")
+	}
+
+	user, err := h.repos.Users.FindByID(userID)
+	if err != nil || !user.TwoFactorEnabled || user.IsSuspended {
+		return helper.Unauthorized("auth.session_expired")
+	}
+	i18n.SetLocale(c, user.Locale)
+
+	if err := h.checkAccountLogin(c, user, user.Email); err != nil {
+		return err
+	}
+
+	if !h.verifySecondFactor(&user, req.Code, req.RecoveryCode) {
+		h.lockIfExceeded(user.ID)
+		h.recordLoginAttempt(c, &user.ID, user.Email, false, loginReasonWrongTwoFactor)
+		return helper.NewError(http.StatusUnauthorized, helper.CodeInvalidCredentials, "two_factor.invalid_code")
+	}
+	h.resetFailedLogins(user.ID)
+	h.recordLoginAttempt(c, &user.ID, user.Email, true, loginReasonSuccess)
+
+	token, err := h.generateAuthToken(int64(user.ID), user.Role)
+	if err != nil {
+		return helper.InternalError("auth.token_failed")
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.Response{Code: http.StatusOK, Status: "success", Message: i18n.T(c, "auth.login_success"), Data: fiber.Map{"token": token, "user": user}})
+}
+
+/*=========================== SETUP 2FA =======================*/
+func (h *Handler) TwoFactorSetup(c *fiber.Ctx) error {
+	user, status, message := h.currentUser(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	if user.TwoFactorEnabled {
+		return helper.BadRequest("two_factor.already_enabled")
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return helper.InternalError("two_factor.secret_failed")
+	}
+	uri := auth.TOTPProvisioningURI(twoFactorIssuer, user.Email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		return helper.InternalError("two_factor.qr_failed")
+	}
+
+	// Secret disimpan tapi 2FA belum aktif sampai user mengonfirmasi satu kode.
+	if err := h.repos.TwoFactor.SaveSecret(user.ID, secret); err != nil {
+		return helper.InternalError("two_factor.save_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "two_factor.setup_started"),
+		Data: fiber.Map{
+			"secret":      secret,
+			"otpauth_uri": uri,
+			"qr_code":     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) TwoFactorEnable(c *fiber.Ctx) error {
+	user, status, message := h.currentUser(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	var req TwoFactorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.BadRequest("common.invalid_request_body")
+	}
+	if user.TwoFactorEnabled || user.TwoFactorSecret == "" {
+		return helper.BadRequest("two_factor.setup_first")
+	}
+
+	step, ok := auth.ValidateTOTP(user.TwoFactorSecret, req.Code, time.Now(), user.TwoFactorLastStep)
+	if !ok {
+		return helper.BadRequest("two_factor.invalid_code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err == nil {
+		err = h.repos.TwoFactor.Enable(user.ID, step, hashes)
+	}
+	if err != nil {
+		return helper.InternalError("two_factor.enable_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "two_factor.enabled"),
+		Data: fiber.Map{
+			"recovery_codes": codes,
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+func (h *Handler) TwoFactorDisable(c *fiber.Ctx) error {
+	user, status, message := h.currentUser(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	if h.jwt.RequiresTwoFactor(user.Role) {
+		return helper.NewError(http.StatusForbidden, helper.CodeTwoFactorRequired, "two_factor.required_for_role")
+	}
+	var req TwoFactorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.BadRequest("common.invalid_request_body")
+	}
+	if !user.TwoFactorEnabled {
+		return helper.BadRequest("two_factor.not_enabled")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil ||
+		!h.verifySecondFactor(&user, req.Code, req.RecoveryCode) {
+		return helper.NewError(http.StatusUnauthorized, helper.CodeInvalidCredentials, "two_factor.invalid_password_or_code")
+	}
+
+	if err := h.repos.TwoFactor.Disable(user.ID); err != nil {
+		return helper.InternalError("two_factor.disable_failed")
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "two_factor.disabled"),
+	})
+}
+
+func (h *Handler) TwoFactorRegenerateRecoveryCodes(c *fiber.Ctx) error {
+	user, status, message := h.currentUser(c)
+	if status != 0 {
+		return helper.NewStatusError(status, message)
+	}
+	var req TwoFactorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return helper.BadRequest("common.invalid_request_body")
+	}
+	if !user.TwoFactorEnabled {
+		return helper.BadRequest("two_factor.not_enabled")
+	}
+	if !h.verifySecondFactor(&user, req.Code, "") {
+		return helper.NewError(http.StatusUnauthorized, helper.CodeInvalidCredentials, "two_factor.invalid_code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err == nil {
+		err = h.repos.TwoFactor.ReplaceRecoveryCodes(user.ID, hashes)
+	}
+	if err != nil {
+		return helper.InternalError("two_factor.recovery_failed")
+	}
+
+	response := helper.Response{
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: i18n.T(c, "two_factor.recovery_regenerated"),
+		Data: fiber.Map{
+			"recovery_codes": codes,
+		},
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
+
+// verifySecondFactor menerima kode TOTP atau salah satu recovery code yang
+// belum terpakai. Keduanya ditandai terpakai supaya tidak bisa di-replay.
+func (h *Handler) verifySecondFactor(user *models.User, code, recoveryCode string) bool {
+	if code != "" {
+		step, ok := auth.ValidateTOTP(user.TwoFactorSecret, code, time.Now(), user.TwoFactorLastStep)
+		if !ok {
+			return false
+		}
+		advanced, err := h.repos.TwoFactor.AdvanceStep(user.ID, step)
+		if err != nil || !advanced {
+			return false
+		}
+		user.TwoFactorLastStep = step
+		return true
+	}
+
+	recoveryCode = strings.TrimSpace(strings.ToLower(recoveryCode))
+	if recoveryCode == "" {
+		return false
+	}
+	codes, err := h.repos.TwoFactor.UnusedRecoveryCodes(user.ID)
+	if err != nil {
+		return false
+	}
+	for _, rc := range codes {
+		if bcrypt.CompareHashAndPassword([]byte(rc.CodeHash), []byte(recoveryCode)) == nil {
+			used, err := h.repos.TwoFactor.UseRecoveryCode(rc.ID, time.Now())
+			return err == nil && used
+		}
+	}
+	return false
+}
+
+// generateRecoveryCodes mengembalikan recovery code untuk ditampilkan ke user
+// beserta hash-nya untuk disimpan.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		bytes := make([]byte, 5)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, nil, err
+		}
+		code := hex.EncodeToString(bytes)
+		code = code[:5] + "-" + code[5:]
+		hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, string(hash))
+	}
+	return codes, hashes, nil
+}
+
+func (h *Handler) generateTwoFactorChallengeToken(userID int64) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"purpose": twoFactorChallengeClaim,
+		"exp":     time.Now().Add(twoFactorChallengeTTL).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(h.jwt.Secret())
+}
+
+func (h *Handler) parseTwoFactorChallengeToken(tokenString string) (uint, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid token signing method")
+		}
+		return h.jwt.Secret(), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, errors.New("invalid challenge token")
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	if claims["purpose"] != twoFactorChallengeClaim {
+		return 0, errors.New("invalid challenge token")
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, errors.New("user_id not found in token claims")
+	}
+	return uint(userID), nil
+}
+
+func (h *Handler) currentUser(c *fiber.Ctx) (models.User, int, string) {
+	var user models.User
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
+	if err != nil {
+		return user, http.StatusUnauthorized, "common.unauthorized"
+	}
+	user, err = h.repos.Users.FindByID(userID)
+	if err != nil {
+		return user, http.StatusInternalServerError, "user.retrieve_failed"
+	}
+	return user, 0, ""
+}
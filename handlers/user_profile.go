@@ -2,183 +2,112 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
-	"backend-pedika-fiber/auth"
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
-	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/validation"
 
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
 )
 
-func GetUserProfile(c *fiber.Ctx) error {
+func (h *Handler) GetUserProfile(c *fiber.Ctx) error {
 	tokenString := c.Get("Authorization")
-	userID, err := auth.ExtractUserIDFromToken(tokenString)
+	userID, err := h.jwt.ExtractUserIDFromToken(tokenString)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized",
-		}
-		return c.Status(http.StatusUnauthorized).JSON(response)
-	}
-	var user models.User
-	if err := database.GetGormDBInstance().First(&user, userID).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to retrieve user profile",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.Unauthorized("common.unauthorized")
+	}
+	user, err := h.repos.Users.FindByID(userID)
+	if err != nil {
+		return helper.InternalError("profile.retrieve_failed")
 	}
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "User profile retrieved successfully",
+		Message: i18n.T(c, "profile.retrieved"),
 		Data:    user,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func checkUsernameExists(db *gorm.DB, username string) bool {
-	var count int64
-	db.Model(&models.User{}).Where("username = ?", username).Count(&count)
-	return count > 0
+// UpdateProfileRequest: field yang kosong tidak diubah.
+type UpdateProfileRequest struct {
+	FullName     string `json:"full_name" form:"full_name" validate:"max=100"`
+	Username     string `json:"username" form:"username" validate:"min=3,max=50"`
+	Email        string `json:"email" form:"email" validate:"email,max=255"`
+	PhoneNumber  string `json:"phone_number" form:"phone_number" validate:"phone"`
+	NIK          string `json:"nik" form:"nik" validate:"nik"`
+	TempatLahir  string `json:"tempat_lahir" form:"tempat_lahir" validate:"max=100"`
+	TanggalLahir string `json:"tanggal_lahir" form:"tanggal_lahir" validate:"datetime=02-01-2006,past"`
+	JenisKelamin string `json:"jenis_kelamin" form:"jenis_kelamin" validate:"oneof=Laki-laki|Perempuan"`
+	Alamat       string `json:"alamat" form:"alamat" validate:"max=255"`
+	Locale       string `json:"locale" form:"locale" validate:"oneof=id|en"`
+	// PengingatEvent nil berarti tidak diubah, karena false juga pilihan.
+	PengingatEvent *bool `json:"pengingat_event" form:"pengingat_event"`
 }
 
-func checkEmailExists(db *gorm.DB, email string) bool {
-	var count int64
-	db.Model(&models.User{}).Where("email = ?", email).Count(&count)
-	return count > 0
-}
-
-func checkPhoneNumberExists(db *gorm.DB, phoneNumber string) bool {
-	var count int64
-	db.Model(&models.User{}).Where("phone_number = ?", phoneNumber).Count(&count)
-	return count > 0
-}
-
-func UpdateUserProfile(c *fiber.Ctx) error {
-	var updateUser models.User
-	if err := c.BodyParser(&updateUser); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+func (h *Handler) UpdateUserProfile(c *fiber.Ctx) error {
+	var updateUser UpdateProfileRequest
+	if err := validation.Bind(c, &updateUser); err != nil {
+		return err
 	}
 
-	userID, err := auth.ExtractUserIDFromToken(c.Get("Authorization"))
+	userID, err := h.jwt.ExtractUserIDFromToken(c.Get("Authorization"))
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to get user ID",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("common.current_user_failed")
 	}
 
-	tx := database.GetGormDBInstance().Begin()
-
-	var existingUser models.User
-	if err := tx.First(&existingUser, userID).Error; err != nil {
-		tx.Rollback()
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to find user",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	existingUser, err := h.repos.Users.FindByID(userID)
+	if err != nil {
+		return helper.InternalError("user.retrieve_failed")
 	}
 
 	if updateUser.Username != "" && updateUser.Username != existingUser.Username {
-		if checkUsernameExists(tx, updateUser.Username) {
-			tx.Rollback()
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Username ini sudah ada, coba yang lain",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
+		if h.isUsernameExists(updateUser.Username) {
+			return helper.BadRequest("auth.username_taken")
 		}
 		existingUser.Username = updateUser.Username
 	}
 
-	
-
 	if updateUser.Email != "" && updateUser.Email != existingUser.Email {
-		if checkEmailExists(tx, updateUser.Email) {
-			tx.Rollback()
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Email yang anda masukkan sudah pernah terdaftar",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
+		if h.isEmailExists(updateUser.Email) {
+			return helper.BadRequest("auth.email_registered")
 		}
 		existingUser.Email = updateUser.Email
+		existingUser.EmailVerifiedAt = nil
 	}
 
 	if updateUser.PhoneNumber != "" && updateUser.PhoneNumber != existingUser.PhoneNumber {
-		if checkPhoneNumberExists(tx, updateUser.PhoneNumber) {
-			tx.Rollback()
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Nomor telepon yang anda masukkan sudah pernah terdaftar",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
+		if h.isPhoneNumberExists(updateUser.PhoneNumber) {
+			return helper.BadRequest("auth.phone_registered")
 		}
 		existingUser.PhoneNumber = updateUser.PhoneNumber
+		existingUser.PhoneVerifiedAt = nil
 	}
 
-	if updateUser.NIK != 0 && updateUser.NIK != existingUser.NIK {
-		existingUser.NIK = updateUser.NIK
+	if updateUser.NIK != "" {
+		nik, _ := strconv.ParseUint(updateUser.NIK, 10, 64)
+		existingUser.NIK = uint(nik)
 	}
 
 	file, err := c.FormFile("photo_profile")
 	if err == nil {
 		src, err := file.Open()
 		if err != nil {
-			tx.Rollback()
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to open photo profile",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.InternalError("common.image_open_failed")
 		}
 		defer src.Close()
 
-		imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+		imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 		if err != nil {
-			tx.Rollback()
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to upload photo profile",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 		}
 		existingUser.PhotoProfile = imageURL
 	}
 
-	tanggalLahirStr := c.FormValue("tanggal_lahir")
-	if tanggalLahirStr != "" {
-		tanggalLahir, err := time.Parse("02-01-2006", tanggalLahirStr)
-		if err != nil {
-			tx.Rollback()
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Invalid date format, use dd-MM-yyyy",
-			}
-			return c.Status(http.StatusBadRequest).JSON(response)
-		}
-		existingUser.TanggalLahir = tanggalLahir
+	if updateUser.TanggalLahir != "" {
+		existingUser.TanggalLahir, _ = validation.ParseDateTime(updateUser.TanggalLahir, "02-01-2006")
 	}
 
 	if updateUser.Alamat != "" {
@@ -189,10 +118,6 @@ func UpdateUserProfile(c *fiber.Ctx) error {
 		existingUser.FullName = updateUser.FullName
 	}
 
-	if updateUser.FullName != "" {
-		existingUser.FullName = updateUser.FullName
-	}
-
 	if updateUser.TempatLahir != "" {
 		existingUser.TempatLahir = updateUser.TempatLahir
 	}
@@ -201,24 +126,26 @@ func UpdateUserProfile(c *fiber.Ctx) error {
 		existingUser.JenisKelamin = updateUser.JenisKelamin
 	}
 
-	existingUser.UpdatedAt = time.Now()
+	if updateUser.Locale != "" {
+		existingUser.Locale = updateUser.Locale
+	}
 
-	if err := tx.Save(&existingUser).Error; err != nil {
-		tx.Rollback()
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update user profile",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if updateUser.PengingatEvent != nil {
+		existingUser.PengingatEvent = *updateUser.PengingatEvent
 	}
 
-	tx.Commit()
+	existingUser.UpdatedAt = time.Now()
+
+	if err := h.repos.Users.Save(&existingUser); err != nil {
+		return helper.InternalError("profile.update_failed")
+	}
+	// Respons ini sudah memakai bahasa yang baru dipilih.
+	i18n.SetLocale(c, existingUser.Locale)
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Profil Anda berhasil diupdate",
+		Message: i18n.T(c, "profile.updated"),
 		Data:    existingUser,
 	}
 	return c.Status(http.StatusOK).JSON(response)
@@ -1,9 +1,11 @@
 package handlers
 
 import (
-	"backend-pedika-fiber/database"
 	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
 	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/validation"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -11,197 +13,143 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func GetAllViolenceCategories(c *fiber.Ctx) error {
-	var categories []models.ViolenceCategory
-	if err := database.DB.Find(&categories).Error; err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Internal Server Error",
-		})
+func (h *Handler) GetAllViolenceCategories(c *fiber.Ctx) error {
+	categories, err := h.repos.ViolenceCategory.List()
+	if err != nil {
+		return helper.InternalError("common.internal_error")
 	}
-	return c.Status(http.StatusOK).JSON(helper.ResponseWithData{
+	return c.Status(http.StatusOK).JSON(helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "List of violence categories",
+		Message: i18n.T(c, "category.list"),
 		Data:    categories,
 	})
 }
 
-func GetViolenceCategoryByID(c *fiber.Ctx) error {
-	categoryID := c.Params("id")
-
-	var category models.ViolenceCategory
-	if err := database.DB.First(&category, categoryID).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Violence category not found",
-		})
+func (h *Handler) GetViolenceCategoryByID(c *fiber.Ctx) error {
+	category, err := h.repos.ViolenceCategory.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("category.not_found")
 	}
 
-	return c.Status(http.StatusOK).JSON(helper.ResponseWithData{
+	return c.Status(http.StatusOK).JSON(helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Violence category details",
+		Message: i18n.T(c, "category.detail"),
 		Data:    category,
 	})
 }
 
-func CreateViolenceCategory(c *fiber.Ctx) error {
-	var category models.ViolenceCategory
-	if err := c.BodyParser(&category); err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request body",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+type CreateViolenceCategoryRequest struct {
+	CategoryName string `json:"category_name" form:"category_name" validate:"required,max=100"`
+}
+
+// UpdateViolenceCategoryRequest: category_name yang kosong tidak diubah.
+type UpdateViolenceCategoryRequest struct {
+	CategoryName string `json:"category_name" form:"category_name" validate:"max=100"`
+}
+
+func (h *Handler) CreateViolenceCategory(c *fiber.Ctx) error {
+	var req CreateViolenceCategoryRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
 	}
 
 	file, err := c.FormFile("image")
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Image file not provided",
-		}
-		return c.Status(http.StatusBadRequest).JSON(response)
+		return helper.BadRequest("common.image_required")
 	}
 
 	src, err := file.Open()
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to open image file",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("common.image_open_failed")
 	}
 	defer src.Close()
 
-	imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+	imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 	if err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal Menguplaod gambar, coba beberapa saat",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+		return helper.InternalError("common.image_upload_failed")
 	}
 
-	category.Image = imageURL
-	category.CategoryName = c.FormValue("category_name")
+	category := models.ViolenceCategory{
+		CategoryName: req.CategoryName,
+		Image:        imageURL,
+	}
 
-	if err := database.DB.Create(&category).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal memuat kategori kekerasan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.ViolenceCategory.Create(&category); err != nil {
+		return helper.InternalError("category.create_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusCreated,
 		Status:  "success",
-		Message: "Kategori Kekerasan Berhasil Dibuat",
+		Message: i18n.T(c, "category.created"),
 		Data:    category,
 	}
 	return c.Status(http.StatusCreated).JSON(response)
 }
 
-func UpdateViolenceCategory(c *fiber.Ctx) error {
-	id := c.Params("id")
-	var category models.ViolenceCategory
-	if err := database.DB.First(&category, id).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Category not found",
-		}
-		return c.Status(http.StatusNotFound).JSON(response)
+func (h *Handler) UpdateViolenceCategory(c *fiber.Ctx) error {
+	category, err := h.repos.ViolenceCategory.FindByID(parseID(c.Params("id")))
+	if err != nil {
+		return helper.NotFound("category.not_found")
 	}
-	categoryName := c.FormValue("category_name")
-	if categoryName != "" {
-		category.CategoryName = categoryName
+	var req UpdateViolenceCategoryRequest
+	if err := validation.Bind(c, &req); err != nil {
+		return err
+	}
+	if req.CategoryName != "" {
+		category.CategoryName = req.CategoryName
 	}
 	file, err := c.FormFile("image")
 	if err == nil {
 		src, err := file.Open()
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Failed to open image file",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.InternalError("common.image_open_failed")
 		}
 		defer src.Close()
 
-		imageURL, err := helper.UploadFileToCloudinary(src, file.Filename)
+		imageURL, err := helper.UploadFileToCloudinary(h.uploader, src, file.Filename)
 		if err != nil {
-			response := helper.ResponseWithOutData{
-				Code:    http.StatusInternalServerError,
-				Status:  "error",
-				Message: "Gagal Mengupload Gambar",
-			}
-			return c.Status(http.StatusInternalServerError).JSON(response)
+			return helper.NewError(http.StatusInternalServerError, helper.CodeUploadFailed, "common.image_upload_failed")
 		}
 
 		category.Image = imageURL
 	}
 	category.UpdatedAt = time.Now()
 
-	if err := database.DB.Save(&category).Error; err != nil {
-		response := helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal Mengupdate kategori Kekerasan",
-		}
-		return c.Status(http.StatusInternalServerError).JSON(response)
+	if err := h.repos.ViolenceCategory.Save(&category); err != nil {
+		return helper.InternalError("category.update_failed")
 	}
 
-	response := helper.ResponseWithData{
+	response := helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Berhasil Mengupdate ketegori kekerasan",
+		Message: i18n.T(c, "category.updated"),
 		Data:    category,
 	}
 	return c.Status(http.StatusOK).JSON(response)
 }
 
-func DeleteViolenceCategory(c *fiber.Ctx) error {
-	categoryID := c.Params("id")
+func (h *Handler) DeleteViolenceCategory(c *fiber.Ctx) error {
+	categoryID := parseID(c.Params("id"))
 
-	var deletedCategory models.ViolenceCategory
-	if err := database.DB.First(&deletedCategory, categoryID).Error; err != nil {
-		return c.Status(http.StatusNotFound).JSON(helper.ResponseWithOutData{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Kategori kekerasan tidak ditemukan",
-		})
+	if _, err := h.repos.ViolenceCategory.FindByID(categoryID); err != nil {
+		return helper.NotFound("category.not_found")
 	}
 
-	if err := database.DB.Where("id = ?", categoryID).Delete(&models.ViolenceCategory{}).Error; err != nil {
+	if err := h.repos.ViolenceCategory.Delete(categoryID); err != nil {
 		// Handle foreign key constraint errors
 		if strings.Contains(err.Error(), "foreign key constraint fails") {
-			return c.Status(http.StatusBadRequest).JSON(helper.ResponseWithOutData{
-				Code:    http.StatusBadRequest,
-				Status:  "error",
-				Message: "Tidak dapat menghapus kategori: sedang digunakan dalam catatan lain",
-			})
+			return helper.BadRequest("category.in_use")
 		}
-		return c.Status(http.StatusInternalServerError).JSON(helper.ResponseWithOutData{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Gagal menghapus kategori kekerasan: " + err.Error(),
-		})
+		log.Println("Error deleting violence category:", err)
+		return helper.InternalError("category.delete_failed")
 	}
 
-	return c.Status(http.StatusOK).JSON(helper.ResponseWithData{
+	return c.Status(http.StatusOK).JSON(helper.Response{
 		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Kategori kekerasan berhasil dihapus",
+		Message: i18n.T(c, "category.deleted"),
 	})
 }
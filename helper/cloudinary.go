@@ -1,68 +1,90 @@
 package helper
 
 import (
+	"backend-pedika-fiber/config"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
-	"os"
+	"sync"
 
 	"github.com/cloudinary/cloudinary-go"
 	"github.com/cloudinary/cloudinary-go/api/uploader"
-	"github.com/joho/godotenv"
 )
 
-func init() {
-	err := godotenv.Load()
-	if err != nil {	
-		log.Fatal("Error loading .env file")
-	}
+// Uploader menyimpan file dan mengembalikan URL publiknya. Implementasi
+// production memakai Cloudinary dan diberikan ke handler saat start.
+type Uploader interface {
+	Upload(file io.Reader, filename string) (string, error)
 }
 
-func UploadFileToCloudinary(file io.Reader, filename string) (string, error) {
-	cloudName := os.Getenv("CLOUD_NAME")
-	apiKey := os.Getenv("API_KEY")
-	apiSecret := os.Getenv("API_SECRET")
+type CloudinaryUploader struct {
+	service *cloudinary.Cloudinary
+}
 
-	cldService, err := cloudinary.NewFromParams(cloudName, apiKey, apiSecret)
+func NewCloudinaryUploader(cfg config.StorageConfig) (*CloudinaryUploader, error) {
+	cldService, err := cloudinary.NewFromParams(cfg.CloudName, cfg.APIKey, cfg.APISecret)
 	if err != nil {
-		return "", fmt.Errorf("failed to create Cloudinary service: %v", err)
+		return nil, fmt.Errorf("failed to create Cloudinary service: %v", err)
 	}
-	ctx := context.Background()
-	resp, err := cldService.Upload.Upload(ctx, file, uploader.UploadParams{})
+	return &CloudinaryUploader{service: cldService}, nil
+}
+
+func (u *CloudinaryUploader) Upload(file io.Reader, filename string) (string, error) {
+	resp, err := u.service.Upload.Upload(context.Background(), file, uploader.UploadParams{})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload image to Cloudinary: %v", err)
 	}
 	return resp.SecureURL, nil
 }
 
-func UploadMultipleFileToCloudinary(files []*multipart.FileHeader) ([]string, error) {
-	cloudName := os.Getenv("CLOUD_NAME")
-	apiKey := os.Getenv("API_KEY")
-	apiSecret := os.Getenv("API_SECRET")
-
-	cldService, err := cloudinary.NewFromParams(cloudName, apiKey, apiSecret)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Cloudinary service: %v", err)
+func UploadFileToCloudinary(u Uploader, file io.Reader, filename string) (string, error) {
+	if u == nil {
+		return "", errors.New("file storage is not configured")
 	}
+	return u.Upload(file, filename)
+}
 
+func UploadMultipleFileToCloudinary(u Uploader, files []*multipart.FileHeader) ([]string, error) {
 	var imageURLs []string
-	ctx := context.Background()
-
 	for _, fileHeader := range files {
-		file, err := fileHeader.Open()
-		if err != nil {
-			return nil, fmt.Errorf("failed to open image file: %v", err)
-		}
-		defer file.Close()
-
-		resp, err := cldService.Upload.Upload(ctx, file, uploader.UploadParams{})
+		url, err := uploadFileHeader(u, fileHeader)
 		if err != nil {
-			return nil, fmt.Errorf("failed to upload image to Cloudinary: %v", err)
+			return nil, err
 		}
-		imageURLs = append(imageURLs, resp.SecureURL)
+		imageURLs = append(imageURLs, url)
 	}
 
 	return imageURLs, nil
 }
+
+func uploadFileHeader(u Uploader, fileHeader *multipart.FileHeader) (string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open image file: %v", err)
+	}
+	defer file.Close()
+
+	return UploadFileToCloudinary(u, file, fileHeader.Filename)
+}
+
+// FakeUploader tidak mengirim file ke mana pun dan mengembalikan URL palsu
+// yang berurutan, dipakai untuk development dan test.
+type FakeUploader struct {
+	mu    sync.Mutex
+	files []string
+}
+
+func (f *FakeUploader) Upload(file io.Reader, filename string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files = append(f.files, filename)
+	return fmt.Sprintf("https://storage.test/%d/%s", len(f.files), filename), nil
+}
+
+func (f *FakeUploader) Files() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.files...)
+}
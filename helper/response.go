@@ -1,14 +1,201 @@
 package helper
 
-type ResponseWithData struct {
-	Code    int    `json:"code"`
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Data    any
+import (
+	"backend-pedika-fiber/i18n"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Response adalah satu-satunya bentuk body JSON yang dikirim API, baik untuk
+// sukses maupun error. Data selalu ada (null jika kosong), sedangkan Error
+// hanya diisi untuk respons error.
+type Response struct {
+	Code    int          `json:"code"`
+	Status  string       `json:"status"`
+	Message string       `json:"message"`
+	Data    interface{}  `json:"data"`
+	Error   *ErrorDetail `json:"error,omitempty"`
+}
+
+// ErrorDetail berisi kode error yang stabil untuk diproses aplikasi client,
+// beserta daftar kesalahan per field jika error berasal dari validasi input.
+// Details dipakai error yang perlu menyertakan data, misalnya daftar janji
+// temu yang bentrok.
+type ErrorDetail struct {
+	Code    string       `json:"code"`
+	Fields  []FieldError `json:"fields,omitempty"`
+	Details interface{}  `json:"details,omitempty"`
+}
+
+// FieldError.Message berisi key katalog i18n; Args adalah argumen formatnya
+// dan tidak ikut dikirim ke client.
+type FieldError struct {
+	Field   string        `json:"field"`
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Args    []interface{} `json:"-"`
+}
+
+// Kode error yang dikirim di error.code. Nilainya tidak boleh diubah karena
+// dipakai aplikasi mobile untuk menentukan penanganan error.
+const (
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeInvalidCredentials = "INVALID_CREDENTIALS"
+	CodeForbidden          = "FORBIDDEN"
+	CodeAccountSuspended   = "ACCOUNT_SUSPENDED"
+	CodeAccountUnverified  = "ACCOUNT_UNVERIFIED"
+	CodeTwoFactorRequired  = "TWO_FACTOR_REQUIRED"
+	CodeNotFound           = "NOT_FOUND"
+	CodeConflict           = "CONFLICT"
+	CodeAccountLocked      = "ACCOUNT_LOCKED"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeOTPInvalid         = "OTP_INVALID"
+	CodeSlotUnavailable    = "SLOT_UNAVAILABLE"
+	CodeScheduleConflict   = "SCHEDULE_CONFLICT"
+	CodeUploadFailed       = "UPLOAD_FAILED"
+	CodeMeetingLinkFailed  = "MEETING_LINK_FAILED"
+	CodeInternal           = "INTERNAL_ERROR"
+)
+
+// Error adalah error yang dikembalikan handler dan middleware. ErrorHandler
+// mengubahnya menjadi Response dengan status HTTP dan kode yang sesuai.
+// Message berisi key katalog i18n yang diterjemahkan sesuai bahasa request.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Args    []interface{}
+	Fields  []FieldError
+	Details interface{}
+}
+
+func (e *Error) Error() string {
+	return i18n.Translate(i18n.Default, e.Message, e.Args...)
+}
+
+// WithArgs mengisi argumen format untuk pesan seperti "auth.rate_limited".
+func (e *Error) WithArgs(args ...interface{}) *Error {
+	e.Args = args
+	return e
+}
+
+// WithDetails menyertakan data tambahan di error.details.
+func (e *Error) WithDetails(details interface{}) *Error {
+	e.Details = details
+	return e
 }
 
-type ResponseWithOutData struct {
-	Code    int    `json:"code"`
-	Status  string `json:"status"`
-	Message string `json:"message"`
+func NewError(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// NewStatusError memilih kode error umum berdasarkan status HTTP.
+func NewStatusError(status int, message string) *Error {
+	return NewError(status, codeForStatus(status), message)
+}
+
+func BadRequest(message string) *Error {
+	return NewError(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+func Unauthorized(message string) *Error {
+	return NewError(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return NewError(http.StatusForbidden, CodeForbidden, message)
+}
+
+func NotFound(message string) *Error {
+	return NewError(http.StatusNotFound, CodeNotFound, message)
+}
+
+func Conflict(message string) *Error {
+	return NewError(http.StatusConflict, CodeConflict, message)
+}
+
+func TooManyRequests(message string) *Error {
+	return NewError(http.StatusTooManyRequests, CodeRateLimited, message)
+}
+
+func InternalError(message string) *Error {
+	return NewError(http.StatusInternalServerError, CodeInternal, message)
+}
+
+// ValidationFailed dipakai jika satu atau lebih field tidak valid. Semua
+// kesalahan dikirim sekaligus di error.fields.
+func ValidationFailed(fields ...FieldError) *Error {
+	return &Error{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    CodeValidationFailed,
+		Message: "validation.failed",
+		Fields:  fields,
+	}
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusLocked:
+		return CodeAccountLocked
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	}
+	if status >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// ErrorHandler dipasang di fiber.Config supaya semua error, termasuk route
+// yang tidak ditemukan dan error yang tidak dikenal, dikirim dengan Response
+// yang sama.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var appErr *Error
+	if !errors.As(err, &appErr) {
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			appErr = NewStatusError(fiberErr.Code, fiberErr.Message)
+		} else {
+			log.Printf("Unhandled error on %s %s: %v\n", c.Method(), c.Path(), err)
+			appErr = InternalError("common.internal_error")
+		}
+	}
+
+	locale := i18n.Locale(c)
+	fields := make([]FieldError, len(appErr.Fields))
+	for i, f := range appErr.Fields {
+		f.Message = i18n.Translate(locale, f.Message, f.Args...)
+		fields[i] = f
+	}
+	if len(fields) == 0 {
+		fields = nil
+	}
+
+	return c.Status(appErr.Status).JSON(Response{
+		Code:    appErr.Status,
+		Status:  "error",
+		Message: i18n.Translate(locale, appErr.Message, appErr.Args...),
+		Error: &ErrorDetail{
+			Code:    appErr.Code,
+			Fields:  fields,
+			Details: appErr.Details,
+		},
+	})
 }
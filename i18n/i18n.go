@@ -0,0 +1,115 @@
+// Package i18n menerjemahkan pesan API ke bahasa Indonesia atau Inggris.
+//
+// Handler dan middleware tidak menulis teks pesan secara langsung, melainkan
+// key katalog seperti "laporan.not_found". Teksnya dipilih berdasarkan
+// preferensi bahasa user yang login, header Accept-Language, lalu bahasa
+// default (Indonesia).
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	Indonesian = "id"
+	English    = "en"
+
+	Default = Indonesian
+)
+
+const localsKey = "locale"
+
+var catalogs = map[string]map[string]string{
+	Indonesian: indonesian,
+	English:    english,
+}
+
+// Supported melaporkan apakah locale punya katalog sendiri.
+func Supported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Translate mengembalikan teks key dalam bahasa locale. Key yang tidak ada di
+// katalog locale dicari di katalog default; jika tetap tidak ada, key dipakai
+// apa adanya sehingga pesan error dari library tetap terbaca.
+func Translate(locale, key string, args ...interface{}) string {
+	text, ok := catalogs[locale][key]
+	if !ok {
+		if text, ok = catalogs[Default][key]; !ok {
+			text = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// T menerjemahkan key dengan bahasa request c.
+func T(c *fiber.Ctx, key string, args ...interface{}) string {
+	return Translate(Locale(c), key, args...)
+}
+
+// SetLocale menyimpan preferensi bahasa user untuk sisa request. Dipanggil
+// middleware auth setelah user dimuat; locale yang tidak didukung diabaikan
+// supaya Accept-Language tetap berlaku.
+func SetLocale(c *fiber.Ctx, locale string) {
+	if Supported(locale) {
+		c.Locals(localsKey, locale)
+	}
+}
+
+// Locale mengembalikan bahasa request c: preferensi user yang disimpan
+// SetLocale, lalu Accept-Language, lalu Default.
+func Locale(c *fiber.Ctx) string {
+	if locale, ok := c.Locals(localsKey).(string); ok {
+		return locale
+	}
+	return FromAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+}
+
+// FromAcceptLanguage memilih locale yang didukung dengan bobot q tertinggi
+// dari header Accept-Language, misalnya "en-US,en;q=0.9,id;q=0.8".
+func FromAcceptLanguage(header string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if i := strings.IndexAny(tag, "-_"); i >= 0 {
+			tag = tag[:i]
+		}
+		if !Supported(tag) {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// Keys mengembalikan semua key katalog locale secara terurut. Dipakai test
+// untuk memastikan setiap bahasa memiliki key yang sama.
+func Keys(locale string) []string {
+	keys := make([]string, 0, len(catalogs[locale]))
+	for key := range catalogs[locale] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,334 @@
+package i18n
+
+// Katalog pesan bahasa Inggris. Key dan jumlah argumen format harus sama
+// dengan katalog bahasa Indonesia.
+var english = map[string]string{
+	"common.unauthorized":           "Please log in first",
+	"common.invalid_request_body":   "Request body could not be read",
+	"common.internal_error":         "Something went wrong on the server",
+	"common.id_required":            "ID is required",
+	"common.current_user_failed":    "Failed to read the user from the token",
+	"common.multipart_failed":       "Failed to read the multipart form",
+	"common.image_required":         "An image file is required",
+	"common.image_open_failed":      "Failed to open the image file",
+	"common.image_upload_failed":    "Failed to upload the image, please try again later",
+	"common.document_upload_failed": "Failed to upload the documents",
+
+	"auth.missing_token":             "Missing token, please log in",
+	"auth.invalid_token_format":      "Invalid token format",
+	"auth.invalid_token":             "Invalid or expired token",
+	"auth.account_not_found":         "Account not found",
+	"auth.account_suspended":         "Your account is suspended, please contact an administrator",
+	"auth.staff_only":                "This endpoint is only available to staff",
+	"auth.masyarakat_only":           "This endpoint is only available to citizens",
+	"auth.permission_denied":         "Your role is not allowed to access this endpoint",
+	"auth.two_factor_setup_required": "Enable two-factor authentication first",
+	"auth.unverified":                "Verify your email and phone number first",
+	"auth.rate_limited":              "Too many requests, try again in %d seconds",
+	"auth.email_registered":          "The email address is already registered",
+	"auth.phone_registered":          "The phone number is already registered",
+	"auth.username_taken":            "This username is already taken, try another one",
+	"auth.register_failed":           "Failed to register the user",
+	"auth.registered":                "Registration successful, please verify your email and phone number with the OTP code we sent",
+	"auth.invalid_credentials":       "Incorrect email, username, phone number or password",
+	"auth.login_throttled":           "Too many failed login attempts, please try again later",
+	"auth.account_locked":            "Your account is temporarily locked after too many failed login attempts",
+	"auth.token_failed":              "Failed to generate the token",
+	"auth.two_factor_code_required":  "Enter your two-factor authentication code",
+	"auth.session_expired":           "Your login session has expired, please log in again",
+	"auth.login_success":             "You have logged in successfully",
+
+	"password.hash_failed":         "Failed to process the password",
+	"password.update_failed":       "Failed to update the password",
+	"password.old_incorrect":       "The old password is incorrect",
+	"password.changed":             "Password changed successfully",
+	"password.reset_token_invalid": "The password reset token is invalid or has expired",
+	"password.reset_link_sent":     "If the email is registered, a password reset link has been sent",
+	"password.reset_success":       "Password reset successfully",
+	"password.reset_email_subject": "PEDIKA Password Reset",
+	"password.reset_email_body":    "Click the link to reset your password: %s\n\nThe link expires in %d minutes and can only be used once.",
+	"password.temporary_failed":    "Failed to generate a temporary password",
+	"password.user_reset":          "The user's password has been reset",
+
+	"otp.sent":          "If the account exists and is not yet verified, an OTP code has been sent",
+	"otp.invalid":       "The OTP code is invalid or has expired",
+	"otp.verify_failed": "Failed to verify the OTP code",
+	"otp.verified":      "Verification successful",
+	"otp.email_subject": "PEDIKA Verification Code",
+	"otp.message_body":  "Your PEDIKA verification code: %s. It is valid for %d minutes, do not share it with anyone.",
+
+	"two_factor.invalid_code":             "Incorrect authentication code",
+	"two_factor.already_enabled":          "Two-factor authentication is already enabled",
+	"two_factor.not_enabled":              "Two-factor authentication is not enabled",
+	"two_factor.secret_failed":            "Failed to generate the 2FA secret",
+	"two_factor.qr_failed":                "Failed to generate the QR code",
+	"two_factor.save_failed":              "Failed to save the 2FA secret",
+	"two_factor.setup_started":            "Scan the QR code with an authenticator app, then confirm the code",
+	"two_factor.setup_first":              "Set up 2FA first",
+	"two_factor.enable_failed":            "Failed to enable 2FA",
+	"two_factor.enabled":                  "Two-factor authentication enabled, keep your recovery codes somewhere safe",
+	"two_factor.required_for_role":        "Two-factor authentication is mandatory for your role",
+	"two_factor.invalid_password_or_code": "Incorrect password or authentication code",
+	"two_factor.disable_failed":           "Failed to disable 2FA",
+	"two_factor.disabled":                 "Two-factor authentication disabled",
+	"two_factor.recovery_failed":          "Failed to generate recovery codes",
+	"two_factor.recovery_regenerated":     "New recovery codes generated, the old ones are no longer valid",
+
+	"user.invalid_id":             "Invalid user ID",
+	"user.not_found":              "User not found",
+	"user.retrieve_failed":        "Failed to retrieve the user",
+	"user.list_failed":            "Failed to retrieve users",
+	"user.list":                   "List of users",
+	"user.detail":                 "User detail",
+	"user.self_action":            "You cannot perform this action on your own account",
+	"user.staff_create_forbidden": "Only a super admin can create staff accounts",
+	"user.staff_change_forbidden": "Only a super admin can change staff accounts",
+	"user.create_failed":          "Failed to create the user",
+	"user.created":                "User created successfully",
+	"user.update_failed":          "Failed to update the user",
+	"user.updated":                "User updated successfully",
+	"user.delete_blocked":         "Cannot delete the user: they still have reports or appointments, suspend the account instead",
+	"user.delete_failed":          "Failed to delete the user",
+	"user.deleted":                "User deleted successfully",
+	"user.suspend_failed":         "Failed to suspend the user",
+	"user.suspended":              "The user account has been suspended",
+	"user.reactivate_failed":      "Failed to reactivate the user",
+	"user.reactivated":            "The user account has been reactivated",
+
+	"role.list":          "List of roles",
+	"role.unknown":       "Unknown role",
+	"role.self_change":   "You cannot change the role of your own account",
+	"role.update_failed": "Failed to update the user's role",
+	"role.updated":       "User role updated successfully",
+
+	"login_security.locked_list_failed": "Failed to retrieve locked accounts",
+	"login_security.locked_list":        "List of locked accounts",
+	"login_security.unlocked":           "Account unlocked successfully",
+	"login_security.attempts_failed":    "Failed to retrieve login attempts",
+	"login_security.attempts_list":      "List of login attempts",
+
+	"profile.retrieve_failed": "Failed to retrieve the user profile",
+	"profile.retrieved":       "User profile retrieved successfully",
+	"profile.update_failed":   "Failed to update the user profile",
+	"profile.updated":         "Your profile has been updated",
+
+	"emergency_contact.retrieve_failed": "Failed to retrieve the emergency contact",
+	"emergency_contact.retrieved":       "Emergency contact retrieved successfully",
+	"emergency_contact.not_found":       "Emergency contact not found",
+	"emergency_contact.update_failed":   "Failed to update the emergency contact",
+	"emergency_contact.updated":         "Emergency contact updated successfully",
+
+	"laporan.not_found":           "Report not found",
+	"laporan.retrieve_failed":     "Failed to retrieve the report",
+	"laporan.update_failed":       "Failed to update the report",
+	"laporan.latest_failed":       "Failed to retrieve the latest reports",
+	"laporan.latest":              "Latest reports retrieved successfully",
+	"laporan.tracking_failed":     "Failed to retrieve the report tracking",
+	"laporan.pelaku_failed":       "Failed to retrieve the perpetrators",
+	"laporan.korban_failed":       "Failed to retrieve the victims",
+	"laporan.viewer_failed":       "Failed to retrieve the officer who viewed the report",
+	"laporan.detail":              "Report detail retrieved successfully",
+	"laporan.status_updated":      "Report status updated successfully",
+	"laporan.processed":           "The report is now being processed",
+	"laporan.registration_failed": "Failed to generate the registration number",
+	"laporan.create_failed":       "Failed to create the report",
+	"laporan.created":             "Report created successfully",
+	"laporan.edit_forbidden":      "You are not allowed to edit this report",
+	"laporan.updated":             "Report updated successfully",
+	"laporan.list_failed":         "Failed to retrieve the reports",
+	"laporan.list":                "List of your reports",
+	"laporan.cancelled":           "Report cancelled successfully",
+	"laporan.completed":           "Report completed successfully",
+
+	"tracking.create_failed": "Failed to create the report tracking",
+	"tracking.created":       "Report tracking created successfully",
+	"tracking.not_found":     "Report tracking not found",
+	"tracking.update_failed": "Failed to update the report tracking",
+	"tracking.updated":       "Report tracking updated successfully",
+	"tracking.delete_failed": "Failed to delete the report tracking",
+	"tracking.deleted":       "Report tracking deleted successfully",
+
+	"korban.create_failed": "Failed to add the victim",
+	"korban.created":       "Victim added successfully",
+	"korban.not_found":     "Victim not found",
+	"korban.update_failed": "Failed to update the victim",
+	"korban.updated":       "Victim updated successfully",
+
+	"pelaku.create_failed": "Failed to add the perpetrator",
+	"pelaku.created":       "Perpetrator added successfully",
+	"pelaku.not_found":     "Perpetrator not found",
+	"pelaku.update_failed": "Failed to update the perpetrator",
+	"pelaku.updated":       "Perpetrator updated successfully",
+	"pelaku.delete_failed": "Failed to delete the perpetrator",
+	"pelaku.deleted":       "Perpetrator deleted successfully",
+
+	"janji_temu.create_failed":             "Failed to create the appointment",
+	"janji_temu.created":                   "Appointment created successfully",
+	"janji_temu.not_found":                 "Appointment not found",
+	"janji_temu.forbidden":                 "Counselors can only manage appointments they handle",
+	"janji_temu.update_failed":             "Failed to update the appointment",
+	"janji_temu.updated":                   "Appointment updated successfully",
+	"janji_temu.list_failed":               "Failed to retrieve the appointments",
+	"janji_temu.list_empty":                "You have no appointments yet",
+	"janji_temu.list":                      "List of appointments",
+	"janji_temu.detail":                    "Appointment detail",
+	"janji_temu.cancel_failed":             "Failed to cancel the appointment",
+	"janji_temu.cancelled":                 "Appointment cancelled successfully",
+	"janji_temu.status_failed":             "Failed to save the status change",
+	"janji_temu.assign_forbidden":          "Counselors can only approve appointments for themselves",
+	"janji_temu.approved":                  "Appointment approved",
+	"janji_temu.rejected":                  "Appointment rejected",
+	"janji_temu.slot_unavailable":          "The selected slot is no longer available, please choose another one",
+	"janji_temu.booked":                    "Slot booked, waiting for approval",
+	"janji_temu.slot_edit_forbidden":       "The time of a booked counselor slot cannot be changed, cancel it and book another slot",
+	"janji_temu.schedule_conflict":         "The schedule clashes with another approved appointment",
+	"janji_temu.conflict_check_failed":     "Failed to check for schedule conflicts",
+	"janji_temu.invalid_transition":        "The appointment status cannot change from '%s' to '%s'",
+	"janji_temu.reschedule_pending":        "A reschedule proposal is still awaiting a response",
+	"janji_temu.reschedule_not_found":      "There is no reschedule proposal awaiting a response",
+	"janji_temu.reschedule_own_proposal":   "A reschedule proposal must be answered by the other party",
+	"janji_temu.reschedule_failed":         "Failed to save the reschedule proposal",
+	"janji_temu.reschedule_proposed":       "The reschedule proposal has been sent",
+	"janji_temu.reschedule_accepted":       "The reschedule proposal was accepted and the appointment time updated",
+	"janji_temu.reschedule_declined":       "The reschedule proposal was declined; the appointment time is unchanged",
+	"janji_temu.reschedule_history_failed": "Failed to retrieve the reschedule history",
+	"janji_temu.reschedule_history":        "Reschedule proposal history",
+	"janji_temu.link":                      "Appointment meeting link",
+	"janji_temu.link_failed":               "Failed to create the meeting link",
+	"janji_temu.link_forbidden":            "The meeting link is only available to the counselor handling this appointment",
+	"janji_temu.link_not_video":            "This appointment is not a video consultation",
+	"janji_temu.link_unavailable":          "The meeting link is available once the appointment is approved",
+
+	"category.list":          "List of violence categories",
+	"category.detail":        "Violence category detail",
+	"category.not_found":     "Violence category not found",
+	"category.check_failed":  "Failed to check the violence category",
+	"category.create_failed": "Failed to create the violence category",
+	"category.created":       "Violence category created successfully",
+	"category.update_failed": "Failed to update the violence category",
+	"category.updated":       "Violence category updated successfully",
+	"category.in_use":        "Cannot delete the category: it is still used by other records",
+	"category.delete_failed": "Failed to delete the violence category",
+	"category.deleted":       "Violence category deleted successfully",
+
+	"content.list_failed":   "Failed to retrieve the contents",
+	"content.list":          "List of contents",
+	"content.not_found":     "Content not found",
+	"content.detail":        "Content detail",
+	"content.create_failed": "Failed to create the content",
+	"content.reload_failed": "Failed to load the content with its violence category",
+	"content.created":       "Content created successfully",
+	"content.update_failed": "Failed to update the content",
+	"content.updated":       "Content updated successfully",
+	"content.delete_failed": "Failed to delete the content",
+	"content.deleted":       "Content deleted successfully",
+
+	"event.list":          "List of events",
+	"event.not_found":     "Event not found",
+	"event.detail":        "Event detail",
+	"event.create_failed": "Failed to create the event",
+	"event.created":       "Event created successfully",
+	"event.update_failed": "Failed to update the event",
+	"event.updated":       "Event updated successfully",
+	"event.delete_failed": "Failed to delete the event",
+	"event.deleted":       "Event deleted successfully",
+
+	"konselor.not_found": "Counselor not found",
+
+	"jadwal.forbidden":               "Counselors can only manage their own schedule",
+	"jadwal.retrieve_failed":         "Failed to retrieve the counselor schedule",
+	"jadwal.detail":                  "Counselor schedule",
+	"jadwal.overlap":                 "The schedule overlaps another schedule on the same day",
+	"jadwal.create_failed":           "Failed to add the counselor schedule",
+	"jadwal.created":                 "Counselor schedule added successfully",
+	"jadwal.not_found":               "Counselor schedule not found",
+	"jadwal.delete_failed":           "Failed to delete the counselor schedule",
+	"jadwal.deleted":                 "Counselor schedule deleted successfully",
+	"jadwal.exception_create_failed": "Failed to add the schedule exception",
+	"jadwal.exception_created":       "Schedule exception added successfully",
+	"jadwal.exception_not_found":     "Schedule exception not found",
+	"jadwal.exception_delete_failed": "Failed to delete the schedule exception",
+	"jadwal.exception_deleted":       "Schedule exception deleted successfully",
+	"jadwal.range_too_long":          "The date range can be at most %d days",
+	"jadwal.slots_failed":            "Failed to retrieve counselor slots",
+	"jadwal.slots":                   "List of available counselor slots",
+
+	"kalender.token_failed":        "Failed to create the calendar feed URL",
+	"kalender.token_created":       "Calendar feed URL created; save it because it is only shown once",
+	"kalender.token_delete_failed": "Failed to disable the calendar feed",
+	"kalender.token_deleted":       "The calendar feed has been disabled",
+	"kalender.not_found":           "Calendar feed not found",
+	"kalender.feed_failed":         "Failed to build the calendar feed",
+	"kalender.not_approved":        "Only approved appointments can be downloaded to a calendar",
+	"kalender.name":                "PEDIKA Schedule",
+	"kalender.janji_temu_summary":  "PEDIKA consultation appointment",
+
+	"pengingat.janji_temu_subject": "PEDIKA appointment reminder",
+	"pengingat.janji_temu_body":    "Your consultation appointment is scheduled for %s at %s.",
+	"pengingat.lokasi_menyusul":    "a location the staff will confirm",
+	"pengingat.event_subject":      "Event reminder: %s",
+	"pengingat.event_body":         "The event %s takes place on %s.",
+	"pengingat.lokasi_telepon":     "by phone, the counselor will call your number",
+
+	"catatan_konsultasi.forbidden":       "You can only access notes for appointments you handle",
+	"catatan_konsultasi.not_started":     "The appointment has not started yet and cannot be completed",
+	"catatan_konsultasi.save_failed":     "Failed to save the consultation notes",
+	"catatan_konsultasi.saved":           "The appointment is complete and the consultation notes have been saved",
+	"catatan_konsultasi.not_found":       "Consultation notes not found",
+	"catatan_konsultasi.retrieve_failed": "Failed to retrieve the consultation notes",
+	"catatan_konsultasi.detail":          "Consultation note details",
+
+	"penilaian.created":                  "Thank you, your feedback has been saved",
+	"penilaian.detail":                   "Service feedback",
+	"penilaian.not_found":                "This service has not been rated yet",
+	"penilaian.retrieve_failed":          "Failed to retrieve the feedback",
+	"penilaian.create_failed":            "Failed to save the feedback",
+	"penilaian.already_submitted":        "You have already rated this service",
+	"penilaian.janji_temu_not_completed": "An appointment can only be rated once it is completed",
+	"penilaian.laporan_not_completed":    "A report can only be rated once it is completed",
+	"penilaian.statistics":               "Service satisfaction statistics",
+	"penilaian.statistics_failed":        "Failed to compute satisfaction statistics",
+
+	"notifikasi.list":                        "Notification list",
+	"notifikasi.list_failed":                 "Failed to retrieve notifications",
+	"notifikasi.unread_count":                "Unread notification count",
+	"notifikasi.not_found":                   "Notification not found",
+	"notifikasi.update_failed":               "Failed to update the notification",
+	"notifikasi.read":                        "Notification marked as read",
+	"notifikasi.read_all":                    "All notifications marked as read",
+	"notifikasi.laporan_dilihat_title":       "Your report has been viewed",
+	"notifikasi.laporan_dilihat_body":        "Report %s has been viewed by staff.",
+	"notifikasi.laporan_diproses_title":      "Your report is being processed",
+	"notifikasi.laporan_diproses_body":       "Report %s is being processed by staff.",
+	"notifikasi.laporan_dibatalkan_title":    "Report cancelled by the reporter",
+	"notifikasi.laporan_dibatalkan_body":     "Report %s was cancelled by the reporter. Reason: %s",
+	"notifikasi.tracking_laporan_title":      "New progress on your report",
+	"notifikasi.tracking_laporan_body":       "Report %s: %s",
+	"notifikasi.janji_temu_disetujui_title":  "Appointment approved",
+	"notifikasi.janji_temu_disetujui_body":   "Your consultation appointment on %s has been approved.",
+	"notifikasi.janji_temu_ditolak_title":    "Appointment rejected",
+	"notifikasi.janji_temu_ditolak_body":     "Your consultation appointment on %s was rejected. Reason: %s",
+	"notifikasi.janji_temu_dibatalkan_title": "Appointment cancelled",
+	"notifikasi.janji_temu_dibatalkan_body":  "The appointment on %s was cancelled by the citizen. Reason: %s",
+
+	"validation.failed":    "The submitted data is invalid",
+	"validation.required":  "%s is required",
+	"validation.min":       "%s must be at least %s characters",
+	"validation.max":       "%s must be at most %s characters",
+	"validation.len":       "%s must be exactly %s characters",
+	"validation.digits":    "%s may only contain digits",
+	"validation.nik":       "%s must be 16 digits",
+	"validation.phone":     "%s is not a valid Indonesian phone number (example: 081234567890)",
+	"validation.email":     "%s is not a valid email address",
+	"validation.oneof":     "%s must be one of: %s",
+	"validation.int":       "%s must be a number",
+	"validation.int_range": "%s must be a number between %d and %d",
+	"validation.datetime":  "%s must use the format %s",
+	"validation.date":      "%s must use the format %s",
+	"validation.time":      "%s must use the format %s",
+	"validation.past":      "%s cannot be in the future",
+	"validation.future":    "%s must be in the future",
+	"validation.after":     "%s must be after %s",
+	"validation.eqfield":   "%s must match %s",
+	"validation.invalid":   "%s is invalid",
+}
@@ -0,0 +1,334 @@
+package i18n
+
+// Katalog pesan bahasa Indonesia. Ini adalah bahasa default, jadi setiap key
+// yang dipakai di kode wajib ada di sini.
+var indonesian = map[string]string{
+	"common.unauthorized":           "Silakan login terlebih dahulu",
+	"common.invalid_request_body":   "Body request tidak dapat dibaca",
+	"common.internal_error":         "Terjadi kesalahan pada server",
+	"common.id_required":            "ID wajib diisi",
+	"common.current_user_failed":    "Gagal membaca user dari token",
+	"common.multipart_failed":       "Gagal membaca form multipart",
+	"common.image_required":         "File gambar wajib diunggah",
+	"common.image_open_failed":      "Gagal membuka file gambar",
+	"common.image_upload_failed":    "Gagal mengunggah gambar, coba beberapa saat lagi",
+	"common.document_upload_failed": "Gagal mengunggah dokumen",
+
+	"auth.missing_token":             "Token tidak ditemukan, silakan login",
+	"auth.invalid_token_format":      "Format token tidak valid",
+	"auth.invalid_token":             "Token tidak valid atau sudah kedaluwarsa",
+	"auth.account_not_found":         "Akun tidak ditemukan",
+	"auth.account_suspended":         "Akun anda sedang dinonaktifkan, silakan hubungi admin",
+	"auth.staff_only":                "Endpoint ini hanya dapat diakses oleh staff",
+	"auth.masyarakat_only":           "Endpoint ini hanya dapat diakses oleh masyarakat",
+	"auth.permission_denied":         "Role anda tidak memiliki izin untuk endpoint ini",
+	"auth.two_factor_setup_required": "Aktifkan autentikasi dua faktor terlebih dahulu",
+	"auth.unverified":                "Verifikasi email dan nomor telepon anda terlebih dahulu",
+	"auth.rate_limited":              "Terlalu banyak permintaan, coba lagi dalam %d detik",
+	"auth.email_registered":          "Email yang anda masukkan sudah pernah terdaftar",
+	"auth.phone_registered":          "Nomor telepon yang anda masukkan sudah pernah terdaftar",
+	"auth.username_taken":            "Username ini sudah ada, coba yang lain",
+	"auth.register_failed":           "Gagal mendaftarkan user",
+	"auth.registered":                "Pendaftaran berhasil, silakan verifikasi email dan nomor telepon dengan kode OTP yang dikirim",
+	"auth.invalid_credentials":       "Email, username, nomor telepon, atau password salah",
+	"auth.login_throttled":           "Terlalu banyak percobaan login gagal, coba lagi beberapa saat",
+	"auth.account_locked":            "Akun anda dikunci sementara karena terlalu banyak percobaan login gagal",
+	"auth.token_failed":              "Gagal membuat token",
+	"auth.two_factor_code_required":  "Masukkan kode autentikasi dua faktor",
+	"auth.session_expired":           "Sesi login sudah kedaluwarsa, silakan login ulang",
+	"auth.login_success":             "Anda berhasil login",
+
+	"password.hash_failed":         "Gagal memproses password",
+	"password.update_failed":       "Gagal mengubah password",
+	"password.old_incorrect":       "Password lama salah",
+	"password.changed":             "Password berhasil diubah",
+	"password.reset_token_invalid": "Token reset password tidak valid atau sudah kedaluwarsa",
+	"password.reset_link_sent":     "Jika email terdaftar, link reset password telah dikirim",
+	"password.reset_success":       "Password berhasil direset",
+	"password.reset_email_subject": "Reset Password PEDIKA",
+	"password.reset_email_body":    "Klik link berikut untuk mereset password anda: %s\n\nLink berlaku %d menit dan hanya dapat digunakan sekali.",
+	"password.temporary_failed":    "Gagal membuat password sementara",
+	"password.user_reset":          "Password user berhasil direset",
+
+	"otp.sent":          "Jika akun terdaftar dan belum terverifikasi, kode OTP telah dikirim",
+	"otp.invalid":       "Kode OTP tidak valid atau sudah kedaluwarsa",
+	"otp.verify_failed": "Gagal memverifikasi kode OTP",
+	"otp.verified":      "Verifikasi berhasil",
+	"otp.email_subject": "Kode Verifikasi PEDIKA",
+	"otp.message_body":  "Kode verifikasi PEDIKA anda: %s. Berlaku %d menit, jangan berikan kode ini kepada siapa pun.",
+
+	"two_factor.invalid_code":             "Kode autentikasi salah",
+	"two_factor.already_enabled":          "Autentikasi dua faktor sudah aktif",
+	"two_factor.not_enabled":              "Autentikasi dua faktor belum aktif",
+	"two_factor.secret_failed":            "Gagal membuat secret 2FA",
+	"two_factor.qr_failed":                "Gagal membuat QR code",
+	"two_factor.save_failed":              "Gagal menyimpan secret 2FA",
+	"two_factor.setup_started":            "Scan QR code dengan aplikasi authenticator lalu konfirmasi kodenya",
+	"two_factor.setup_first":              "Lakukan setup 2FA terlebih dahulu",
+	"two_factor.enable_failed":            "Gagal mengaktifkan 2FA",
+	"two_factor.enabled":                  "Autentikasi dua faktor berhasil diaktifkan, simpan recovery code di tempat yang aman",
+	"two_factor.required_for_role":        "Autentikasi dua faktor wajib untuk role anda",
+	"two_factor.invalid_password_or_code": "Password atau kode autentikasi salah",
+	"two_factor.disable_failed":           "Gagal menonaktifkan 2FA",
+	"two_factor.disabled":                 "Autentikasi dua faktor berhasil dinonaktifkan",
+	"two_factor.recovery_failed":          "Gagal membuat recovery code",
+	"two_factor.recovery_regenerated":     "Recovery code baru berhasil dibuat, recovery code lama tidak berlaku lagi",
+
+	"user.invalid_id":             "ID user tidak valid",
+	"user.not_found":              "User tidak ditemukan",
+	"user.retrieve_failed":        "Gagal mengambil data user",
+	"user.list_failed":            "Gagal mengambil daftar user",
+	"user.list":                   "Daftar user",
+	"user.detail":                 "Detail user",
+	"user.self_action":            "Anda tidak dapat melakukan aksi ini pada akun anda sendiri",
+	"user.staff_create_forbidden": "Hanya super admin yang dapat membuat akun staff",
+	"user.staff_change_forbidden": "Hanya super admin yang dapat mengubah akun staff",
+	"user.create_failed":          "Gagal membuat user",
+	"user.created":                "User berhasil dibuat",
+	"user.update_failed":          "Gagal mengubah user",
+	"user.updated":                "User berhasil diupdate",
+	"user.delete_blocked":         "Tidak dapat menghapus user: masih memiliki laporan atau janji temu, nonaktifkan akun sebagai gantinya",
+	"user.delete_failed":          "Gagal menghapus user",
+	"user.deleted":                "User berhasil dihapus",
+	"user.suspend_failed":         "Gagal menonaktifkan user",
+	"user.suspended":              "Akun user berhasil dinonaktifkan",
+	"user.reactivate_failed":      "Gagal mengaktifkan kembali user",
+	"user.reactivated":            "Akun user berhasil diaktifkan kembali",
+
+	"role.list":          "Daftar role",
+	"role.unknown":       "Role tidak dikenal",
+	"role.self_change":   "Anda tidak dapat mengubah role akun anda sendiri",
+	"role.update_failed": "Gagal mengubah role user",
+	"role.updated":       "Role user berhasil diubah",
+
+	"login_security.locked_list_failed": "Gagal mengambil daftar akun yang dikunci",
+	"login_security.locked_list":        "Daftar akun yang dikunci",
+	"login_security.unlocked":           "Akun berhasil dibuka kembali",
+	"login_security.attempts_failed":    "Gagal mengambil riwayat percobaan login",
+	"login_security.attempts_list":      "Riwayat percobaan login",
+
+	"profile.retrieve_failed": "Gagal mengambil profil user",
+	"profile.retrieved":       "Profil user berhasil diambil",
+	"profile.update_failed":   "Gagal mengubah profil user",
+	"profile.updated":         "Profil anda berhasil diupdate",
+
+	"emergency_contact.retrieve_failed": "Gagal mengambil kontak darurat",
+	"emergency_contact.retrieved":       "Kontak darurat berhasil diambil",
+	"emergency_contact.not_found":       "Kontak darurat tidak ditemukan",
+	"emergency_contact.update_failed":   "Gagal mengubah kontak darurat",
+	"emergency_contact.updated":         "Kontak darurat berhasil diupdate",
+
+	"laporan.not_found":           "Laporan tidak ditemukan",
+	"laporan.retrieve_failed":     "Gagal mengambil data laporan",
+	"laporan.update_failed":       "Gagal mengubah laporan",
+	"laporan.latest_failed":       "Gagal mengambil laporan terbaru",
+	"laporan.latest":              "Laporan terbaru berhasil diambil",
+	"laporan.tracking_failed":     "Gagal mengambil tracking laporan",
+	"laporan.pelaku_failed":       "Gagal mengambil data pelaku",
+	"laporan.korban_failed":       "Gagal mengambil data korban",
+	"laporan.viewer_failed":       "Gagal mengambil data petugas yang melihat laporan",
+	"laporan.detail":              "Detail laporan berhasil diambil",
+	"laporan.status_updated":      "Status laporan berhasil diubah",
+	"laporan.processed":           "Laporan berhasil diproses",
+	"laporan.registration_failed": "Gagal membuat nomor registrasi",
+	"laporan.create_failed":       "Gagal membuat laporan",
+	"laporan.created":             "Laporan berhasil dibuat",
+	"laporan.edit_forbidden":      "Anda tidak berhak mengubah laporan ini",
+	"laporan.updated":             "Laporan berhasil diupdate",
+	"laporan.list_failed":         "Gagal mengambil daftar laporan",
+	"laporan.list":                "Daftar laporan anda",
+	"laporan.cancelled":           "Laporan berhasil dibatalkan",
+	"laporan.completed":           "Laporan berhasil diselesaikan",
+
+	"tracking.create_failed": "Gagal membuat tracking laporan",
+	"tracking.created":       "Tracking laporan berhasil dibuat",
+	"tracking.not_found":     "Tracking laporan tidak ditemukan",
+	"tracking.update_failed": "Gagal mengubah tracking laporan",
+	"tracking.updated":       "Tracking laporan berhasil diupdate",
+	"tracking.delete_failed": "Gagal menghapus tracking laporan",
+	"tracking.deleted":       "Tracking laporan berhasil dihapus",
+
+	"korban.create_failed": "Gagal menambah data korban",
+	"korban.created":       "Berhasil menambah data korban",
+	"korban.not_found":     "Korban tidak ditemukan",
+	"korban.update_failed": "Gagal mengubah data korban",
+	"korban.updated":       "Berhasil mengubah data korban",
+
+	"pelaku.create_failed": "Gagal menambah data pelaku",
+	"pelaku.created":       "Berhasil menambah data pelaku",
+	"pelaku.not_found":     "Pelaku tidak ditemukan",
+	"pelaku.update_failed": "Gagal mengubah data pelaku",
+	"pelaku.updated":       "Berhasil mengubah data pelaku",
+	"pelaku.delete_failed": "Gagal menghapus data pelaku",
+	"pelaku.deleted":       "Berhasil menghapus data pelaku",
+
+	"janji_temu.create_failed":             "Gagal membuat janji temu",
+	"janji_temu.created":                   "Janji temu berhasil dibuat",
+	"janji_temu.not_found":                 "Janji temu tidak ditemukan",
+	"janji_temu.forbidden":                 "Konselor hanya bisa mengelola janji temu yang ditanganinya",
+	"janji_temu.update_failed":             "Gagal mengubah janji temu",
+	"janji_temu.updated":                   "Janji temu berhasil diupdate",
+	"janji_temu.list_failed":               "Gagal mengambil daftar janji temu",
+	"janji_temu.list_empty":                "Belum ada janji temu",
+	"janji_temu.list":                      "Daftar janji temu",
+	"janji_temu.detail":                    "Detail janji temu",
+	"janji_temu.cancel_failed":             "Gagal membatalkan janji temu",
+	"janji_temu.cancelled":                 "Janji temu berhasil dibatalkan",
+	"janji_temu.status_failed":             "Gagal menyimpan perubahan status",
+	"janji_temu.assign_forbidden":          "Konselor hanya bisa menyetujui janji temu untuk dirinya sendiri",
+	"janji_temu.approved":                  "Janji temu berhasil disetujui",
+	"janji_temu.rejected":                  "Janji temu sudah ditolak",
+	"janji_temu.slot_unavailable":          "Slot yang dipilih sudah tidak tersedia, silakan pilih slot lain",
+	"janji_temu.booked":                    "Slot berhasil dipesan dan menunggu persetujuan",
+	"janji_temu.slot_edit_forbidden":       "Waktu janji temu dari slot konselor tidak dapat diubah, batalkan lalu pesan slot lain",
+	"janji_temu.schedule_conflict":         "Jadwal bentrok dengan janji temu lain yang sudah disetujui",
+	"janji_temu.conflict_check_failed":     "Gagal memeriksa bentrokan jadwal",
+	"janji_temu.invalid_transition":        "Status janji temu tidak dapat diubah dari '%s' menjadi '%s'",
+	"janji_temu.reschedule_pending":        "Masih ada usulan jadwal ulang yang menunggu tanggapan",
+	"janji_temu.reschedule_not_found":      "Tidak ada usulan jadwal ulang yang menunggu tanggapan",
+	"janji_temu.reschedule_own_proposal":   "Usulan jadwal ulang harus ditanggapi oleh pihak lain",
+	"janji_temu.reschedule_failed":         "Gagal menyimpan usulan jadwal ulang",
+	"janji_temu.reschedule_proposed":       "Usulan jadwal ulang berhasil dikirim",
+	"janji_temu.reschedule_accepted":       "Usulan jadwal ulang diterima, waktu janji temu sudah diperbarui",
+	"janji_temu.reschedule_declined":       "Usulan jadwal ulang ditolak, waktu janji temu tidak berubah",
+	"janji_temu.reschedule_history_failed": "Gagal mengambil riwayat jadwal ulang",
+	"janji_temu.reschedule_history":        "Riwayat usulan jadwal ulang",
+	"janji_temu.link":                      "Tautan pertemuan janji temu",
+	"janji_temu.link_failed":               "Gagal membuat tautan pertemuan",
+	"janji_temu.link_forbidden":            "Tautan pertemuan hanya untuk konselor yang menangani janji temu ini",
+	"janji_temu.link_not_video":            "Janji temu ini bukan konsultasi video",
+	"janji_temu.link_unavailable":          "Tautan pertemuan tersedia setelah janji temu disetujui",
+
+	"category.list":          "Daftar kategori kekerasan",
+	"category.detail":        "Detail kategori kekerasan",
+	"category.not_found":     "Kategori kekerasan tidak ditemukan",
+	"category.check_failed":  "Gagal memeriksa kategori kekerasan",
+	"category.create_failed": "Gagal membuat kategori kekerasan",
+	"category.created":       "Kategori kekerasan berhasil dibuat",
+	"category.update_failed": "Gagal mengubah kategori kekerasan",
+	"category.updated":       "Kategori kekerasan berhasil diupdate",
+	"category.in_use":        "Tidak dapat menghapus kategori: sedang digunakan dalam catatan lain",
+	"category.delete_failed": "Gagal menghapus kategori kekerasan",
+	"category.deleted":       "Kategori kekerasan berhasil dihapus",
+
+	"content.list_failed":   "Gagal mengambil daftar konten",
+	"content.list":          "Daftar konten",
+	"content.not_found":     "Konten tidak ditemukan",
+	"content.detail":        "Detail konten",
+	"content.create_failed": "Gagal membuat konten",
+	"content.reload_failed": "Gagal memuat konten beserta kategori kekerasan",
+	"content.created":       "Konten berhasil dibuat",
+	"content.update_failed": "Gagal mengubah konten",
+	"content.updated":       "Konten berhasil diupdate",
+	"content.delete_failed": "Gagal menghapus konten",
+	"content.deleted":       "Konten berhasil dihapus",
+
+	"event.list":          "Daftar event",
+	"event.not_found":     "Event tidak ditemukan",
+	"event.detail":        "Detail event",
+	"event.create_failed": "Gagal membuat event",
+	"event.created":       "Event berhasil dibuat",
+	"event.update_failed": "Gagal mengubah event",
+	"event.updated":       "Event berhasil diupdate",
+	"event.delete_failed": "Gagal menghapus event",
+	"event.deleted":       "Event berhasil dihapus",
+
+	"konselor.not_found": "Konselor tidak ditemukan",
+
+	"jadwal.forbidden":               "Konselor hanya dapat mengatur jadwalnya sendiri",
+	"jadwal.retrieve_failed":         "Gagal mengambil jadwal konselor",
+	"jadwal.detail":                  "Jadwal konselor",
+	"jadwal.overlap":                 "Jadwal bertabrakan dengan jadwal lain pada hari yang sama",
+	"jadwal.create_failed":           "Gagal menambah jadwal konselor",
+	"jadwal.created":                 "Jadwal konselor berhasil ditambahkan",
+	"jadwal.not_found":               "Jadwal konselor tidak ditemukan",
+	"jadwal.delete_failed":           "Gagal menghapus jadwal konselor",
+	"jadwal.deleted":                 "Jadwal konselor berhasil dihapus",
+	"jadwal.exception_create_failed": "Gagal menambah pengecualian jadwal",
+	"jadwal.exception_created":       "Pengecualian jadwal berhasil ditambahkan",
+	"jadwal.exception_not_found":     "Pengecualian jadwal tidak ditemukan",
+	"jadwal.exception_delete_failed": "Gagal menghapus pengecualian jadwal",
+	"jadwal.exception_deleted":       "Pengecualian jadwal berhasil dihapus",
+	"jadwal.range_too_long":          "Rentang tanggal maksimal %d hari",
+	"jadwal.slots_failed":            "Gagal mengambil slot konselor",
+	"jadwal.slots":                   "Daftar slot konselor yang tersedia",
+
+	"kalender.token_failed":        "Gagal membuat URL feed kalender",
+	"kalender.token_created":       "URL feed kalender berhasil dibuat, simpan karena hanya ditampilkan sekali",
+	"kalender.token_delete_failed": "Gagal menonaktifkan feed kalender",
+	"kalender.token_deleted":       "Feed kalender berhasil dinonaktifkan",
+	"kalender.not_found":           "Feed kalender tidak ditemukan",
+	"kalender.feed_failed":         "Gagal menyusun feed kalender",
+	"kalender.not_approved":        "Hanya janji temu yang sudah disetujui yang dapat diunduh ke kalender",
+	"kalender.name":                "Jadwal PEDIKA",
+	"kalender.janji_temu_summary":  "Janji temu konsultasi PEDIKA",
+
+	"pengingat.janji_temu_subject": "Pengingat janji temu PEDIKA",
+	"pengingat.janji_temu_body":    "Janji temu konsultasi Anda dijadwalkan pada %s di %s.",
+	"pengingat.lokasi_menyusul":    "lokasi yang akan diinformasikan petugas",
+	"pengingat.event_subject":      "Pengingat event: %s",
+	"pengingat.event_body":         "Event %s akan dilaksanakan pada %s.",
+	"pengingat.lokasi_telepon":     "melalui telepon, konselor akan menghubungi nomor Anda",
+
+	"catatan_konsultasi.forbidden":       "Anda hanya dapat mengakses catatan janji temu yang Anda tangani",
+	"catatan_konsultasi.not_started":     "Janji temu belum dimulai sehingga belum dapat diselesaikan",
+	"catatan_konsultasi.save_failed":     "Gagal menyimpan catatan konsultasi",
+	"catatan_konsultasi.saved":           "Janji temu selesai dan catatan konsultasi berhasil disimpan",
+	"catatan_konsultasi.not_found":       "Catatan konsultasi tidak ditemukan",
+	"catatan_konsultasi.retrieve_failed": "Gagal mengambil catatan konsultasi",
+	"catatan_konsultasi.detail":          "Detail catatan konsultasi",
+
+	"penilaian.created":                  "Terima kasih, penilaian Anda berhasil disimpan",
+	"penilaian.detail":                   "Penilaian layanan",
+	"penilaian.not_found":                "Layanan ini belum dinilai",
+	"penilaian.retrieve_failed":          "Gagal mengambil penilaian",
+	"penilaian.create_failed":            "Gagal menyimpan penilaian",
+	"penilaian.already_submitted":        "Layanan ini sudah Anda nilai",
+	"penilaian.janji_temu_not_completed": "Janji temu hanya bisa dinilai setelah selesai",
+	"penilaian.laporan_not_completed":    "Laporan hanya bisa dinilai setelah selesai",
+	"penilaian.statistics":               "Statistik kepuasan layanan",
+	"penilaian.statistics_failed":        "Gagal menghitung statistik kepuasan",
+
+	"notifikasi.list":                        "Daftar notifikasi",
+	"notifikasi.list_failed":                 "Gagal mengambil notifikasi",
+	"notifikasi.unread_count":                "Jumlah notifikasi belum dibaca",
+	"notifikasi.not_found":                   "Notifikasi tidak ditemukan",
+	"notifikasi.update_failed":               "Gagal memperbarui notifikasi",
+	"notifikasi.read":                        "Notifikasi ditandai sudah dibaca",
+	"notifikasi.read_all":                    "Semua notifikasi ditandai sudah dibaca",
+	"notifikasi.laporan_dilihat_title":       "Laporan Anda sudah dilihat",
+	"notifikasi.laporan_dilihat_body":        "Laporan %s sudah dilihat oleh petugas.",
+	"notifikasi.laporan_diproses_title":      "Laporan Anda sedang diproses",
+	"notifikasi.laporan_diproses_body":       "Laporan %s sedang diproses oleh petugas.",
+	"notifikasi.laporan_dibatalkan_title":    "Laporan dibatalkan pelapor",
+	"notifikasi.laporan_dibatalkan_body":     "Laporan %s dibatalkan oleh pelapor dengan alasan: %s",
+	"notifikasi.tracking_laporan_title":      "Perkembangan baru laporan Anda",
+	"notifikasi.tracking_laporan_body":       "Laporan %s: %s",
+	"notifikasi.janji_temu_disetujui_title":  "Janji temu disetujui",
+	"notifikasi.janji_temu_disetujui_body":   "Janji temu konsultasi Anda pada %s sudah disetujui.",
+	"notifikasi.janji_temu_ditolak_title":    "Janji temu ditolak",
+	"notifikasi.janji_temu_ditolak_body":     "Janji temu konsultasi Anda pada %s ditolak dengan alasan: %s",
+	"notifikasi.janji_temu_dibatalkan_title": "Janji temu dibatalkan",
+	"notifikasi.janji_temu_dibatalkan_body":  "Janji temu pada %s dibatalkan oleh masyarakat dengan alasan: %s",
+
+	"validation.failed":    "Data yang dikirim tidak valid",
+	"validation.required":  "%s wajib diisi",
+	"validation.min":       "%s minimal %s karakter",
+	"validation.max":       "%s maksimal %s karakter",
+	"validation.len":       "%s harus %s karakter",
+	"validation.digits":    "%s hanya boleh berisi angka",
+	"validation.nik":       "%s harus terdiri dari 16 digit angka",
+	"validation.phone":     "%s bukan nomor telepon Indonesia yang valid (contoh: 081234567890)",
+	"validation.email":     "%s bukan alamat email yang valid",
+	"validation.oneof":     "%s harus salah satu dari: %s",
+	"validation.int":       "%s harus berupa angka",
+	"validation.int_range": "%s harus berupa angka antara %d dan %d",
+	"validation.datetime":  "%s harus berformat %s",
+	"validation.date":      "%s harus berformat %s",
+	"validation.time":      "%s harus berformat %s",
+	"validation.past":      "%s tidak boleh di masa depan",
+	"validation.future":    "%s harus di masa depan",
+	"validation.after":     "%s harus setelah %s",
+	"validation.eqfield":   "%s harus sama dengan %s",
+	"validation.invalid":   "%s tidak valid",
+}
@@ -0,0 +1,126 @@
+// Package ical menulis kalender iCalendar (RFC 5545) untuk janji temu dan
+// event, supaya bisa dilanggan dari Google Calendar, Outlook, dan aplikasi
+// kalender lain.
+//
+// Waktu tanpa zona dari database diperlakukan sebagai UTC, sama seperti
+// WaktuDimulai janji temu.
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	prodID        = "-//PEDIKA//Layanan Perlindungan Anak//ID"
+	dateLayout    = "20060102"
+	utcLayout     = "20060102T150405Z"
+	maxLineOctets = 75
+)
+
+// Calendar adalah satu VCALENDAR berisi beberapa VEVENT.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Event adalah satu VEVENT. UID harus tetap sama untuk item yang sama supaya
+// aplikasi kalender memperbarui event lama, bukan membuat event baru.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	// End boleh kosong; event lalu hanya punya waktu mulai.
+	End    time.Time
+	Status string
+	// Updated menentukan LAST-MODIFIED dan SEQUENCE, sehingga perubahan
+	// waktu atau pembatalan terbaca sebagai versi event yang lebih baru.
+	Updated time.Time
+}
+
+// Bytes menghasilkan isi file .ics dengan baris CRLF dan pelipatan baris
+// sesuai RFC 5545. now dipakai untuk DTSTAMP.
+func (cal Calendar) Bytes(now time.Time) []byte {
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(fold(name + ":" + value))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+	for _, e := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", utc(now))
+		line("DTSTART", utc(e.Start))
+		if !e.End.IsZero() {
+			line("DTEND", utc(e.End))
+		}
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", escape(e.Location))
+		}
+		if e.Status != "" {
+			line("STATUS", e.Status)
+		}
+		if !e.Updated.IsZero() {
+			line("LAST-MODIFIED", utc(e.Updated))
+			line("SEQUENCE", fmt.Sprint(e.Updated.Unix()))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return []byte(b.String())
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format(utcLayout)
+}
+
+// escape meng-escape nilai TEXT (RFC 5545 bagian 3.3.11).
+func escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// fold memecah baris yang lebih dari 75 oktet tanpa memotong karakter UTF-8.
+// Baris lanjutan diawali satu spasi.
+func fold(s string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Spasi pembuka ikut dihitung dalam batas baris lanjutan.
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,239 @@
+package integration
+
+import (
+	"backend-pedika-fiber/models"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegisterVerifyAndLogin(t *testing.T) {
+	ta := newTestApp(t)
+
+	resp := ta.do(http.MethodPost, "/api/user/register", "", jsonBody(map[string]string{
+		"full_name":    "Warga Baru",
+		"email":        "warga@test.local",
+		"phone_number": "081200000001",
+		"password":     "rahasia123",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["role"]); got != "masyarakat" {
+		t.Fatalf("registered role = %q, want masyarakat", got)
+	}
+
+	resp = ta.do(http.MethodPost, "/api/user/register", "", jsonBody(map[string]string{
+		"full_name":    "Warga Lain",
+		"email":        "warga@test.local",
+		"phone_number": "081200000002",
+		"password":     "rahasia123",
+	}))
+	expectStatus(t, resp, http.StatusBadRequest)
+
+	resp = ta.do(http.MethodPost, "/api/user/login", "", jsonBody(map[string]string{
+		"email":    "warga@test.local",
+		"password": "salah",
+	}))
+	expectError(t, resp, http.StatusUnauthorized, "INVALID_CREDENTIALS")
+
+	resp = ta.do(http.MethodPost, "/api/user/login", "", jsonBody(map[string]string{
+		"email":    "warga@test.local",
+		"password": "rahasia123",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	token := str(resp.data()["token"])
+
+	// Akun yang belum diverifikasi belum boleh membuat laporan.
+	resp = ta.do(http.MethodPost, "/api/masyarakat/buat-laporan", token, formBody(map[string]string{
+		"kategori_kekerasan_id": "1",
+		"tanggal_kejadian":      "2024-05-01T10:00:00",
+	}, nil))
+	expectError(t, resp, http.StatusForbidden, "ACCOUNT_UNVERIFIED")
+
+	for channel, target := range map[string]string{"email": "warga@test.local", "phone": "081200000001"} {
+		resp = ta.do(http.MethodPost, "/api/user/verify-otp", "", jsonBody(map[string]string{
+			"channel": channel,
+			"target":  target,
+			"code":    ta.lastOTP(target),
+		}))
+		expectStatus(t, resp, http.StatusOK)
+	}
+
+	resp = ta.do(http.MethodGet, "/api/masyarakat/profile", token, noBody())
+	expectStatus(t, resp, http.StatusOK)
+}
+
+func TestRegisterReportsAllMissingFields(t *testing.T) {
+	ta := newTestApp(t)
+
+	resp := ta.do(http.MethodPost, "/api/user/register", "", jsonBody(map[string]string{
+		"full_name": "Tanpa Kontak",
+	}))
+	expectError(t, resp, http.StatusUnprocessableEntity, "VALIDATION_FAILED")
+
+	detail, _ := resp.Body["error"].(map[string]interface{})
+	fields, _ := detail["fields"].([]interface{})
+	var names []string
+	for _, f := range fields {
+		field, _ := f.(map[string]interface{})
+		names = append(names, str(field["field"]))
+	}
+	if want := []string{"email", "password", "phone_number"}; fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Fatalf("invalid fields = %v, want %v", names, want)
+	}
+}
+
+func TestUnknownRouteUsesErrorEnvelope(t *testing.T) {
+	ta := newTestApp(t)
+
+	resp := ta.do(http.MethodGet, "/api/tidak-ada", "", noBody())
+	expectError(t, resp, http.StatusNotFound, "NOT_FOUND")
+}
+
+func TestOTPDoesNotRevealAccounts(t *testing.T) {
+	ta := newTestApp(t)
+
+	resp := ta.do(http.MethodPost, "/api/user/register", "", jsonBody(map[string]string{
+		"full_name":    "Warga Baru",
+		"email":        "warga@test.local",
+		"phone_number": "081200000001",
+		"password":     "rahasia123",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+
+	// Target tidak terdaftar, sudah terverifikasi, dan masih cooldown
+	// mendapat jawaban yang sama persis.
+	var messages []string
+	for _, target := range []string{"tidak-ada@test.local", masyarakatEmail, "warga@test.local"} {
+		resp = ta.do(http.MethodPost, "/api/user/send-otp", "", jsonBody(map[string]string{
+			"channel": "email",
+			"target":  target,
+		}))
+		expectStatus(t, resp, http.StatusOK)
+		messages = append(messages, message(resp))
+	}
+	if messages[0] != messages[1] || messages[1] != messages[2] {
+		t.Fatalf("send-otp messages differ: %q", messages)
+	}
+
+	for _, target := range []string{"tidak-ada@test.local", masyarakatEmail, "warga@test.local"} {
+		resp = ta.do(http.MethodPost, "/api/user/verify-otp", "", jsonBody(map[string]string{
+			"channel": "email",
+			"target":  target,
+			"code":    "000000",
+		}))
+		expectError(t, resp, http.StatusBadRequest, "OTP_INVALID")
+		if got := message(resp); got != "Kode OTP tidak valid atau sudah kedaluwarsa" {
+			t.Fatalf("verify-otp message for %s = %q", target, got)
+		}
+	}
+}
+
+func TestParallelOTPGuessesCannotSkipAttemptLimit(t *testing.T) {
+	ta := newTestApp(t)
+	expectStatus(t, ta.do(http.MethodPost, "/api/user/register", "", jsonBody(map[string]string{
+		"full_name":    "Warga Baru",
+		"email":        "warga@test.local",
+		"phone_number": "081200000001",
+		"password":     "rahasia123",
+	})), http.StatusOK)
+	code := ta.lastOTP("warga@test.local")
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ta.do(http.MethodPost, "/api/user/verify-otp", "", jsonBody(map[string]string{
+				"channel": "email",
+				"target":  "warga@test.local",
+				"code":    wrong,
+			}))
+		}()
+	}
+	wg.Wait()
+
+	var otp models.OTPCode
+	if err := ta.db.Where("target = ?", "warga@test.local").Last(&otp).Error; err != nil {
+		t.Fatal(err)
+	}
+	if otp.Attempts != 5 {
+		t.Fatalf("attempts after parallel guesses = %d, want 5", otp.Attempts)
+	}
+	// Batas sudah habis, kode yang benar pun ditolak.
+	resp := ta.do(http.MethodPost, "/api/user/verify-otp", "", jsonBody(map[string]string{
+		"channel": "email",
+		"target":  "warga@test.local",
+		"code":    code,
+	}))
+	expectError(t, resp, http.StatusBadRequest, "OTP_INVALID")
+}
+
+func TestParallelLoginsCannotSkipProgressiveDelay(t *testing.T) {
+	ta := newTestApp(t)
+
+	// Akun sudah 3 kali gagal, jeda 1 detiknya sudah lewat, jadi tepat satu
+	// percobaan berikutnya boleh memeriksa password.
+	lastFailure := time.Now().Add(-10 * time.Second)
+	if err := ta.db.Model(&models.User{}).Where("email = ?", masyarakatEmail).Updates(map[string]interface{}{
+		"failed_login_count":   3,
+		"last_failed_login_at": lastFailure,
+	}).Error; err != nil {
+		t.Fatalf("prepare failed logins: %v", err)
+	}
+
+	const attempts = 8
+	statuses := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := ta.do(http.MethodPost, "/api/user/login", "", jsonBody(map[string]string{
+				"email":    masyarakatEmail,
+				"password": "salah-semua",
+			}))
+			statuses <- resp.Status
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	checked := 0
+	for status := range statuses {
+		switch status {
+		case http.StatusUnauthorized:
+			checked++
+		case http.StatusTooManyRequests:
+		default:
+			t.Fatalf("unexpected status %d", status)
+		}
+	}
+	if checked != 1 {
+		t.Fatalf("expected exactly 1 password check, got %d", checked)
+	}
+}
+
+func TestIPThrottleUsesForwardedClientIP(t *testing.T) {
+	ta := newTestApp(t)
+
+	login := func(ip string) response {
+		return ta.do(http.MethodPost, "/api/user/login", "", jsonBody(map[string]string{
+			"email":    "tidak-ada@test.local",
+			"password": "salah",
+		}).fromIP(ip))
+	}
+
+	// Semua request lewat load balancer yang sama, tetapi hanya client yang
+	// terus gagal yang dihambat.
+	for i := 0; i < 30; i++ {
+		expectStatus(t, login("203.0.113.10"), http.StatusUnauthorized)
+	}
+	expectError(t, login("203.0.113.10"), http.StatusTooManyRequests, "RATE_LIMITED")
+	expectStatus(t, login("203.0.113.20"), http.StatusUnauthorized)
+}
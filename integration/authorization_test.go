@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+type routeCase struct {
+	method string
+	path   string
+}
+
+// Satu endpoint perwakilan per kelompok izin di /api/admin.
+var adminRoutes = map[string]routeCase{
+	"profile":           {http.MethodGet, "/api/admin/profile"},
+	"emergency contact": {http.MethodPut, "/api/admin/emergency-contact-edit"},
+	"laporan":           {http.MethodGet, "/api/admin/laporans"},
+	"violence category": {http.MethodPost, "/api/admin/create-violence-category"},
+	"content":           {http.MethodPost, "/api/admin/create-content"},
+	"event":             {http.MethodPost, "/api/admin/create-event"},
+	"janji temu":        {http.MethodPut, "/api/admin/approve-janjitemu/1"},
+	"user":              {http.MethodGet, "/api/admin/users"},
+	"role":              {http.MethodGet, "/api/admin/roles"},
+}
+
+var masyarakatRoutes = map[string]routeCase{
+	"profile":    {http.MethodGet, "/api/masyarakat/profile"},
+	"laporan":    {http.MethodGet, "/api/masyarakat/laporans"},
+	"korban":     {http.MethodPost, "/api/masyarakat/create-korban-kekerasan"},
+	"janji temu": {http.MethodGet, "/api/masyarakat/janjitemus"},
+}
+
+func TestRouteGroupsRequireToken(t *testing.T) {
+	ta := newTestApp(t)
+
+	for _, routes := range []map[string]routeCase{adminRoutes, masyarakatRoutes} {
+		for name, route := range routes {
+			t.Run(route.path, func(t *testing.T) {
+				resp := ta.do(route.method, route.path, "", noBody())
+				if resp.Status != http.StatusUnauthorized {
+					t.Fatalf("%s without token: status %d, want 401", name, resp.Status)
+				}
+				resp = ta.do(route.method, route.path, "bukan-token", noBody())
+				if resp.Status != http.StatusUnauthorized {
+					t.Fatalf("%s with invalid token: status %d, want 401", name, resp.Status)
+				}
+			})
+		}
+	}
+}
+
+func TestMasyarakatCannotAccessAdminRoutes(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+
+	for name, route := range adminRoutes {
+		resp := ta.do(route.method, route.path, warga, noBody())
+		if resp.Status != http.StatusForbidden {
+			t.Errorf("%s: status %d, want 403", name, resp.Status)
+		}
+	}
+}
+
+func TestStaffCannotAccessMasyarakatRoutes(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.login(adminEmail)
+
+	for name, route := range masyarakatRoutes {
+		resp := ta.do(route.method, route.path, admin, noBody())
+		if resp.Status != http.StatusForbidden {
+			t.Errorf("%s: status %d, want 403", name, resp.Status)
+		}
+	}
+}
+
+func TestAdminPermissionsPerRole(t *testing.T) {
+	ta := newTestApp(t)
+	tokens := map[string]string{
+		"admin":    ta.login(adminEmail),
+		"konselor": ta.login(konselorEmail),
+		"editor":   ta.login(editorEmail),
+	}
+
+	// Kelompok yang tidak tercantum untuk sebuah role harus ditolak 403.
+	allowed := map[string][]string{
+		"admin":    {"profile", "emergency contact", "laporan", "violence category", "content", "event", "janji temu", "user"},
+		"konselor": {"profile", "laporan", "janji temu"},
+		"editor":   {"profile", "violence category", "content", "event"},
+	}
+
+	for role, token := range tokens {
+		permitted := map[string]bool{}
+		for _, name := range allowed[role] {
+			permitted[name] = true
+		}
+		for name, route := range adminRoutes {
+			resp := ta.do(route.method, route.path, token, noBody())
+			if permitted[name] && resp.Status == http.StatusForbidden {
+				t.Errorf("%s should access %s, got 403: %v", role, name, resp.Body)
+			}
+			if !permitted[name] && resp.Status != http.StatusForbidden {
+				t.Errorf("%s should not access %s, got %d", role, name, resp.Status)
+			}
+		}
+	}
+}
+
+func TestPublicRoutesWithoutLogin(t *testing.T) {
+	ta := newTestApp(t)
+
+	for _, path := range []string{
+		"/api/emergency-contact",
+		"/api/publik-content",
+		"/api/publik-event",
+		"/api/publik/kategori-kekerasan",
+	} {
+		resp := ta.do(http.MethodGet, path, "", noBody())
+		if resp.Status != http.StatusOK {
+			t.Errorf("GET %s: status %d, want 200", path, resp.Status)
+		}
+	}
+}
+
+func TestSuspendedUserIsRejected(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+
+	user, err := ta.repos.Users.FindByEmail(masyarakatEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.IsSuspended = true
+	if err := ta.repos.Users.Save(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := ta.do(http.MethodGet, "/api/masyarakat/profile", warga, noBody())
+	expectError(t, resp, http.StatusForbidden, "ACCOUNT_SUSPENDED")
+}
+
+func TestAdminContactChangeRequiresVerification(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.login(adminEmail)
+	warga := ta.login(masyarakatEmail)
+
+	resp := ta.do(http.MethodPut, "/api/admin/users/"+ta.userID(masyarakatEmail), admin, jsonBody(map[string]string{
+		"phone_number": "081299999999",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	if resp.data()["phone_verified_at"] != nil || resp.data()["email_verified_at"] == nil {
+		t.Fatalf("verification after phone change = %v", resp.data())
+	}
+
+	resp = ta.do(http.MethodPost, "/api/masyarakat/buat-laporan", warga, formBody(map[string]string{
+		"kategori_kekerasan_id": "1",
+		"tanggal_kejadian":      "2024-05-01T10:00:00",
+	}, nil))
+	expectError(t, resp, http.StatusForbidden, "ACCOUNT_UNVERIFIED")
+}
@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"backend-pedika-fiber/models"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// mulaiJanjiTemu memindahkan janji temu ke masa lalu, karena API hanya
+// menerima janji temu di masa depan sedangkan penyelesaian butuh janji temu
+// yang sudah dimulai.
+func (ta *testApp) mulaiJanjiTemu(t *testing.T, id string) {
+	t.Helper()
+	mulai := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	err := ta.db.Model(&models.JanjiTemu{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"waktu_dimulai": mulai,
+		"waktu_selesai": mulai.Add(time.Hour),
+	}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSelesaikanJanjiTemuDenganCatatan(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+	konselor2 := ta.login(konselor2Email)
+	admin := ta.login(adminEmail)
+	editor := ta.login(editorEmail)
+	noRegistrasi := createLaporan(t, ta, warga)
+
+	id := createJanjiTemu(t, ta, warga)
+	selesai := func(token string, body map[string]string) response {
+		return ta.do(http.MethodPut, "/api/admin/selesai-janjitemu/"+id, token, jsonBody(body))
+	}
+	hadir := map[string]string{
+		"kehadiran":           "hadir",
+		"kategori_hasil":      "pendampingan_lanjutan",
+		"catatan":             "Anak menunjukkan tanda trauma, perlu pendampingan psikolog",
+		"perlu_tindak_lanjut": "true",
+		"tindak_lanjut":       "Rujuk ke psikolog minggu depan",
+		"no_registrasi":       noRegistrasi,
+	}
+
+	// Janji temu yang belum disetujui tidak bisa diselesaikan.
+	expectError(t, selesai(admin, hadir), http.StatusConflict, "CONFLICT")
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody()), http.StatusOK)
+	expectError(t, selesai(konselor, hadir), http.StatusConflict, "CONFLICT")
+	ta.mulaiJanjiTemu(t, id)
+
+	expectFieldErrors(t, selesai(konselor, map[string]string{"kehadiran": "hadir", "perlu_tindak_lanjut": "ya"}),
+		map[string]string{"perlu_tindak_lanjut": "oneof"})
+	expectFieldErrors(t, selesai(konselor, map[string]string{"kehadiran": "hadir"}),
+		map[string]string{"kategori_hasil": "required"})
+	expectFieldErrors(t, selesai(konselor, map[string]string{
+		"kehadiran":           "hadir",
+		"kategori_hasil":      "rujukan",
+		"perlu_tindak_lanjut": "true",
+	}), map[string]string{"tindak_lanjut": "required"})
+	tidakAda := map[string]string{"kehadiran": "hadir", "kategori_hasil": "rujukan", "no_registrasi": "TIDAK-ADA"}
+	expectError(t, selesai(konselor, tidakAda), http.StatusNotFound, "NOT_FOUND")
+
+	// Hanya konselor yang menangani yang boleh mencatat.
+	expectError(t, selesai(konselor2, hadir), http.StatusForbidden, "FORBIDDEN")
+	expectError(t, selesai(editor, hadir), http.StatusForbidden, "FORBIDDEN")
+
+	resp := selesai(konselor, hadir)
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["no_registrasi"]); got != noRegistrasi {
+		t.Fatalf("no_registrasi = %q", got)
+	}
+	if got := janjiTemuStatus(t, ta, warga, id); got != "Selesai" {
+		t.Fatalf("status after completion = %q", got)
+	}
+	expectError(t, selesai(konselor, hadir), http.StatusConflict, "CONFLICT")
+
+	// Catatan hanya terbaca oleh staff yang berwenang.
+	resp = ta.do(http.MethodGet, "/api/admin/catatan-janjitemu/"+id, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["catatan"]); got != hadir["catatan"] {
+		t.Fatalf("catatan = %q", got)
+	}
+	expectError(t, ta.do(http.MethodGet, "/api/admin/catatan-janjitemu/"+id, konselor2, noBody()), http.StatusForbidden, "FORBIDDEN")
+	expectError(t, ta.do(http.MethodGet, "/api/admin/catatan-janjitemu/"+id, editor, noBody()), http.StatusForbidden, "FORBIDDEN")
+	expectError(t, ta.do(http.MethodGet, "/api/admin/catatan-janjitemu/"+id, warga, noBody()), http.StatusForbidden, "FORBIDDEN")
+	resp = ta.do(http.MethodGet, "/api/masyarakat/detail-janjitemu/"+id, warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if _, ok := resp.data()["catatan"]; ok {
+		t.Fatalf("appointment detail leaks notes: %v", resp.data())
+	}
+}
+
+func TestJanjiTemuTidakHadir(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+	id := createJanjiTemu(t, ta, warga)
+	admin := ta.login(adminEmail)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody()), http.StatusOK)
+	ta.mulaiJanjiTemu(t, id)
+
+	// Admin yang menyelesaikan tercatat sebagai pembuat, bukan konselor.
+	resp := ta.do(http.MethodPut, "/api/admin/selesai-janjitemu/"+id, admin, jsonBody(map[string]string{
+		"kehadiran":      "tidak_hadir",
+		"kategori_hasil": "tertangani",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["kategori_hasil"]); got != "" {
+		t.Fatalf("kategori_hasil for no-show = %q", got)
+	}
+	if got := str(resp.data()["konselor_id"]); got != ta.userID(konselorEmail) {
+		t.Fatalf("konselor_id = %q, want the assigned konselor", got)
+	}
+	if got := str(resp.data()["dibuat_oleh_id"]); got != ta.userID(adminEmail) {
+		t.Fatalf("dibuat_oleh_id = %q, want the admin", got)
+	}
+	if got := janjiTemuStatus(t, ta, warga, id); got != "Tidak hadir" {
+		t.Fatalf("status after no-show = %q", got)
+	}
+	expectError(t, ta.do(http.MethodPut, "/api/masyarakat/batal-janjitemu/"+id, warga, formBody(map[string]string{
+		"alasan_dibatalkan": "Lupa",
+	}, nil)), http.StatusConflict, "CONFLICT")
+}
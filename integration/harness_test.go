@@ -0,0 +1,334 @@
+// Package integration menjalankan aplikasi Fiber lengkap dari routes.Setup di
+// atas database SQLite in-memory, storage palsu, pengirim email/SMS palsu, dan
+// provider tautan pertemuan palsu. Setiap test mendapat database, sender,
+// storage, provider, dan store rate limit sendiri yang diberikan lewat
+// routes.Setup, tanpa state package.
+package integration
+
+import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/config"
+	"backend-pedika-fiber/handlers"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/mail"
+	"backend-pedika-fiber/pertemuan"
+	"backend-pedika-fiber/ratelimit"
+	"backend-pedika-fiber/repository"
+	"backend-pedika-fiber/repository/repotest"
+	"backend-pedika-fiber/routes"
+	"backend-pedika-fiber/seed"
+	"backend-pedika-fiber/sms"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Akun dari testdata/fixtures.json, semuanya memakai password testPassword.
+const (
+	adminEmail       = "admin@test.local"
+	konselorEmail    = "konselor@test.local"
+	konselor2Email   = "konselor2@test.local"
+	editorEmail      = "editor@test.local"
+	masyarakatEmail  = "masyarakat@test.local"
+	masyarakat2Email = "masyarakat2@test.local"
+	testPassword     = "password123"
+)
+
+type testApp struct {
+	t       *testing.T
+	app     *fiber.App
+	db      *gorm.DB
+	repos   *repository.Repositories
+	mail    *mail.FakeSender
+	sms     *sms.FakeSender
+	uploads *helper.FakeUploader
+	meeting *pertemuan.FakeProvider
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	return newTestAppWith(t, nil)
+}
+
+// newTestAppWith sama seperti newTestApp, tetapi configure bisa mengubah
+// config sebelum route dipasang, misalnya untuk memperketat rate limit.
+func newTestAppWith(t *testing.T, configure func(cfg *config.Config)) *testApp {
+	t.Helper()
+
+	db := repotest.Open(t)
+	data, err := seed.LoadFile("testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("load seed: %v", err)
+	}
+	if err := seed.Apply(db, data, io.Discard); err != nil {
+		t.Fatalf("apply seed: %v", err)
+	}
+
+	unlimited := config.Limit{Max: 100000, Window: time.Minute}
+	cfg := &config.Config{
+		FrontendURL: "http://frontend.test",
+		JWT:         config.JWTConfig{SecretKey: "integration-test-secret", TTL: time.Hour},
+		Mail:        config.MailConfig{Driver: "log"},
+		// app.Test selalu memakai remote address 0.0.0.0, yang di sini
+		// berperan sebagai load balancer. Lihat request.fromIP.
+		Proxy: config.ProxyConfig{Header: "X-Real-IP", TrustedProxies: []string{"0.0.0.0"}},
+		RateLimit: config.RateLimitConfig{
+			Store:  "memory",
+			Auth:   unlimited,
+			Public: unlimited,
+			Write:  unlimited,
+			Upload: unlimited,
+		},
+	}
+
+	if configure != nil {
+		configure(cfg)
+	}
+
+	ta := &testApp{
+		t:       t,
+		db:      db,
+		repos:   repository.NewGormRepositories(db),
+		mail:    &mail.FakeSender{},
+		sms:     &sms.FakeSender{},
+		uploads: &helper.FakeUploader{},
+		meeting: &pertemuan.FakeProvider{},
+	}
+	deps := handlers.Deps{
+		JWT:       auth.NewJWT(cfg.JWT),
+		Mail:      ta.mail,
+		SMS:       ta.sms,
+		Uploader:  ta.uploads,
+		Pertemuan: ta.meeting,
+	}
+
+	ta.app = routes.NewApp(cfg)
+	routes.Setup(ta.app, cfg, ta.repos, deps, ratelimit.NewMemoryStore())
+	return ta
+}
+
+// response adalah hasil request yang sudah di-decode. Body berisi JSON mentah
+// supaya test bisa membaca field mana pun.
+type response struct {
+	Status int
+	Body   map[string]interface{}
+}
+
+func (r response) payload() interface{} {
+	return r.Body["data"]
+}
+
+// errorCode mengambil error.code dari respons error.
+func (r response) errorCode() string {
+	detail, _ := r.Body["error"].(map[string]interface{})
+	return str(detail["code"])
+}
+
+// fieldErrors memetakan error.fields menjadi nama field -> kode aturan.
+func (r response) fieldErrors() map[string]string {
+	detail, _ := r.Body["error"].(map[string]interface{})
+	fields, _ := detail["fields"].([]interface{})
+	errs := map[string]string{}
+	for _, f := range fields {
+		field, _ := f.(map[string]interface{})
+		errs[str(field["field"])] = str(field["code"])
+	}
+	return errs
+}
+
+func (r response) data() map[string]interface{} {
+	data, _ := r.payload().(map[string]interface{})
+	return data
+}
+
+func (r response) list() []interface{} {
+	list, _ := r.payload().([]interface{})
+	return list
+}
+
+// request adalah body HTTP beserta content type-nya.
+type request struct {
+	body           io.Reader
+	contentType    string
+	acceptLanguage string
+	clientIP       string
+}
+
+// inLanguage mengirim request dengan header Accept-Language.
+func (r request) inLanguage(language string) request {
+	r.acceptLanguage = language
+	return r
+}
+
+// fromIP mengirim request seolah-olah diteruskan load balancer dari client
+// dengan IP tersebut.
+func (r request) fromIP(ip string) request {
+	r.clientIP = ip
+	return r
+}
+
+func jsonBody(v interface{}) request {
+	raw, _ := json.Marshal(v)
+	return request{body: bytes.NewReader(raw), contentType: fiber.MIMEApplicationJSON}
+}
+
+// formBody membuat multipart form. files berisi nama field ke nama file,
+// isi file selalu sama karena storage di test palsu.
+func formBody(fields map[string]string, files map[string]string) request {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, value := range fields {
+		w.WriteField(key, value)
+	}
+	for field, filename := range files {
+		part, _ := w.CreateFormFile(field, filename)
+		part.Write([]byte("fake file content"))
+	}
+	w.Close()
+	return request{body: &buf, contentType: w.FormDataContentType()}
+}
+
+func noBody() request {
+	return request{}
+}
+
+func (ta *testApp) do(method, path, token string, req request) response {
+	ta.t.Helper()
+
+	httpReq := httptest.NewRequest(method, path, req.body)
+	if req.contentType != "" {
+		httpReq.Header.Set(fiber.HeaderContentType, req.contentType)
+	}
+	if token != "" {
+		httpReq.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	if req.acceptLanguage != "" {
+		httpReq.Header.Set(fiber.HeaderAcceptLanguage, req.acceptLanguage)
+	}
+	if req.clientIP != "" {
+		httpReq.Header.Set("X-Real-IP", req.clientIP)
+	}
+	resp, err := ta.app.Test(httpReq, -1)
+	if err != nil {
+		ta.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+	result := response{Status: resp.StatusCode}
+	if len(raw) > 0 && json.Unmarshal(raw, &result.Body) != nil {
+		result.Body = map[string]interface{}{"raw": string(raw)}
+	}
+	return result
+}
+
+func (ta *testApp) login(email string) string {
+	ta.t.Helper()
+
+	resp := ta.do(http.MethodPost, "/api/user/login", "", jsonBody(map[string]string{
+		"email":    email,
+		"password": testPassword,
+	}))
+	token, _ := resp.data()["token"].(string)
+	if resp.Status != http.StatusOK || token == "" {
+		ta.t.Fatalf("login %s: status %d, body %v", email, resp.Status, resp.Body)
+	}
+	return token
+}
+
+// userID mengambil ID akun seed berdasarkan email.
+func (ta *testApp) userID(email string) string {
+	ta.t.Helper()
+
+	user, err := ta.repos.Users.FindByEmail(email)
+	if err != nil {
+		ta.t.Fatalf("find user %s: %v", email, err)
+	}
+	return strconv.FormatUint(uint64(user.ID), 10)
+}
+
+var otpPattern = regexp.MustCompile(`\b(\d{6})\b`)
+
+// lastOTP mengambil kode OTP terakhir yang dikirim ke target lewat email
+// atau SMS palsu.
+func (ta *testApp) lastOTP(target string) string {
+	ta.t.Helper()
+
+	code := ""
+	for _, msg := range ta.mail.Messages() {
+		if msg.To == target {
+			code = otpPattern.FindString(msg.Body)
+		}
+	}
+	for _, msg := range ta.sms.Messages() {
+		if msg.To == target {
+			code = otpPattern.FindString(msg.Body)
+		}
+	}
+	if code == "" {
+		ta.t.Fatalf("no OTP sent to %s", target)
+	}
+	return code
+}
+
+// eventually menunggu sampai cond terpenuhi, untuk pekerjaan yang dijalankan
+// handler di background seperti pengiriman email reset password.
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func expectStatus(t *testing.T, resp response, want int) {
+	t.Helper()
+	if resp.Status != want {
+		t.Fatalf("expected status %d, got %d: %v", want, resp.Status, resp.Body)
+	}
+}
+
+func expectError(t *testing.T, resp response, status int, code string) {
+	t.Helper()
+	expectStatus(t, resp, status)
+	if resp.Body["status"] != "error" || resp.errorCode() != code {
+		t.Fatalf("expected error code %s, got %v", code, resp.Body)
+	}
+}
+
+// expectFieldErrors memastikan respons adalah 422 dengan kesalahan tepat pada
+// field yang diharapkan (nama field -> kode aturan).
+func expectFieldErrors(t *testing.T, resp response, want map[string]string) {
+	t.Helper()
+	expectError(t, resp, http.StatusUnprocessableEntity, "VALIDATION_FAILED")
+	if got := resp.fieldErrors(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("field errors = %v, want %v", got, want)
+	}
+}
+
+func str(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case float64:
+		return fmt.Sprintf("%.0f", value)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
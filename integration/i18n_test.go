@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"backend-pedika-fiber/i18n"
+	"net/http"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func message(resp response) string {
+	return str(resp.Body["message"])
+}
+
+func fieldMessage(resp response, name string) string {
+	detail, _ := resp.Body["error"].(map[string]interface{})
+	fields, _ := detail["fields"].([]interface{})
+	for _, f := range fields {
+		field, _ := f.(map[string]interface{})
+		if str(field["field"]) == name {
+			return str(field["message"])
+		}
+	}
+	return ""
+}
+
+func TestMessagesFollowAcceptLanguage(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+
+	resp := ta.do(http.MethodGet, "/api/masyarakat/janjitemus", "", noBody())
+	expectError(t, resp, http.StatusUnauthorized, "UNAUTHORIZED")
+	if got := message(resp); got != "Token tidak ditemukan, silakan login" {
+		t.Fatalf("default message = %q", got)
+	}
+
+	resp = ta.do(http.MethodGet, "/api/masyarakat/janjitemus", "", noBody().inLanguage("en-US,en;q=0.9"))
+	if got := message(resp); got != "Missing token, please log in" {
+		t.Fatalf("english message = %q", got)
+	}
+
+	// Bahasa yang tidak didukung dilewati, bobot q tertinggi yang dipakai.
+	resp = ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, noBody().inLanguage("fr;q=1, en;q=0.5, id;q=0.8"))
+	expectStatus(t, resp, http.StatusUnprocessableEntity)
+	if got := message(resp); got != "Data yang dikirim tidak valid" {
+		t.Fatalf("message = %q", got)
+	}
+	if got := fieldMessage(resp, "waktu_dimulai"); got != "waktu_dimulai wajib diisi" {
+		t.Fatalf("field message = %q", got)
+	}
+
+	resp = ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, noBody().inLanguage("fr, en;q=0.5"))
+	if got := message(resp); got != "The submitted data is invalid" {
+		t.Fatalf("message = %q", got)
+	}
+	if got := fieldMessage(resp, "waktu_dimulai"); got != "waktu_dimulai is required" {
+		t.Fatalf("field message = %q", got)
+	}
+
+	resp = ta.do(http.MethodGet, "/api/masyarakat/janjitemus", warga, noBody().inLanguage("en"))
+	expectStatus(t, resp, http.StatusOK)
+	if got := message(resp); got != "You have no appointments yet" {
+		t.Fatalf("success message = %q", got)
+	}
+}
+
+func TestUserLocaleOverridesAcceptLanguage(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+
+	resp := ta.do(http.MethodPut, "/api/masyarakat/edit-profile", warga, jsonBody(map[string]string{"locale": "fr"}))
+	expectFieldErrors(t, resp, map[string]string{"locale": "oneof"})
+
+	resp = ta.do(http.MethodPut, "/api/masyarakat/edit-profile", warga, jsonBody(map[string]string{"locale": "en"}))
+	expectStatus(t, resp, http.StatusOK)
+	if got := message(resp); got != "Your profile has been updated" {
+		t.Fatalf("message after switching locale = %q", got)
+	}
+	if got := str(resp.data()["locale"]); got != "en" {
+		t.Fatalf("stored locale = %q", got)
+	}
+
+	resp = ta.do(http.MethodGet, "/api/masyarakat/detail-janjitemu/999", warga, noBody().inLanguage("id"))
+	expectStatus(t, resp, http.StatusNotFound)
+	if got := message(resp); got != "Appointment not found" {
+		t.Fatalf("message with stored locale = %q", got)
+	}
+
+	// Login dan email di luar request user juga memakai bahasa akun.
+	resp = ta.do(http.MethodPost, "/api/user/login", "", jsonBody(map[string]string{
+		"email":    masyarakatEmail,
+		"password": testPassword,
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	if got := message(resp); got != "You have logged in successfully" {
+		t.Fatalf("login message = %q", got)
+	}
+
+	resp = ta.do(http.MethodPost, "/api/user/forgot-password", "", jsonBody(map[string]string{"email": masyarakatEmail}))
+	expectStatus(t, resp, http.StatusOK)
+	if got := message(resp); got != "Jika email terdaftar, link reset password telah dikirim" {
+		t.Fatalf("forgot password message = %q", got)
+	}
+	eventually(t, func() bool { return len(ta.mail.Messages()) > 0 })
+	sent := ta.mail.Messages()
+	if sent[len(sent)-1].Subject != "PEDIKA Password Reset" {
+		t.Fatalf("reset email not sent in english: %+v", sent)
+	}
+}
+
+var formatVerb = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	idKeys, enKeys := i18n.Keys(i18n.Indonesian), i18n.Keys(i18n.English)
+	if !reflect.DeepEqual(idKeys, enKeys) {
+		t.Fatalf("catalog keys differ:\nid: %v\nen: %v", idKeys, enKeys)
+	}
+	for _, key := range idKeys {
+		idVerbs := formatVerb.FindAllString(i18n.Translate(i18n.Indonesian, key), -1)
+		enVerbs := formatVerb.FindAllString(i18n.Translate(i18n.English, key), -1)
+		if !reflect.DeepEqual(idVerbs, enVerbs) {
+			t.Errorf("%s: format verbs differ: id %v, en %v", key, idVerbs, enVerbs)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package integration
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// 2030-02-04 jatuh pada hari Senin (hari=1).
+const seninPertama = "2030-02-04"
+
+// setupJadwalSenin membuat jadwal konselor setiap Senin 09:00-11:00 dengan
+// slot satu jam.
+func setupJadwalSenin(t *testing.T, ta *testApp, token, konselorID string) {
+	t.Helper()
+
+	resp := ta.do(http.MethodPost, "/api/admin/jadwal-konselor/"+konselorID, token, formBody(map[string]string{
+		"hari":         "1",
+		"jam_mulai":    "09:00",
+		"jam_selesai":  "11:00",
+		"durasi_menit": "60",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+}
+
+func slotStarts(t *testing.T, ta *testApp, query string) []string {
+	t.Helper()
+
+	resp := ta.do(http.MethodGet, "/api/publik/slot-konselor?"+query, "", noBody())
+	expectStatus(t, resp, http.StatusOK)
+	starts := []string{}
+	for _, item := range resp.list() {
+		slot, _ := item.(map[string]interface{})
+		starts = append(starts, str(slot["waktu_dimulai"]))
+	}
+	return starts
+}
+
+func expectSlots(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("slots = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("slots = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestJadwalKonselorDanSlotKosong(t *testing.T) {
+	ta := newTestApp(t)
+	konselor := ta.login(konselorEmail)
+	konselorID := ta.userID(konselorEmail)
+
+	resp := ta.do(http.MethodPost, "/api/admin/jadwal-konselor/"+konselorID, konselor, formBody(map[string]string{
+		"hari":        "7",
+		"jam_mulai":   "11:00",
+		"jam_selesai": "09:00",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{"hari": "int", "jam_selesai": "after"})
+
+	setupJadwalSenin(t, ta, konselor, konselorID)
+
+	resp = ta.do(http.MethodPost, "/api/admin/jadwal-konselor/"+konselorID, konselor, formBody(map[string]string{
+		"hari":        "1",
+		"jam_mulai":   "10:30",
+		"jam_selesai": "12:00",
+	}, nil))
+	expectError(t, resp, http.StatusConflict, "CONFLICT")
+
+	// Libur seharian pada Senin kedua, dan hanya jam pertama pada Senin ketiga.
+	resp = ta.do(http.MethodPost, "/api/admin/jadwal-konselor/"+konselorID+"/pengecualian", konselor, formBody(map[string]string{
+		"tanggal":    "2030-02-11",
+		"keterangan": "Cuti",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+	resp = ta.do(http.MethodPost, "/api/admin/jadwal-konselor/"+konselorID+"/pengecualian", konselor, formBody(map[string]string{
+		"tanggal":   "2030-02-18",
+		"jam_mulai": "09:00",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{"jam_selesai": "required"})
+	resp = ta.do(http.MethodPost, "/api/admin/jadwal-konselor/"+konselorID+"/pengecualian", konselor, formBody(map[string]string{
+		"tanggal":     "2030-02-18",
+		"jam_mulai":   "09:00",
+		"jam_selesai": "10:00",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+
+	expectSlots(t, slotStarts(t, ta, "konselor_id="+konselorID+"&dari="+seninPertama+"&sampai=2030-02-18"),
+		"2030-02-04T09:00:00Z",
+		"2030-02-04T10:00:00Z",
+		"2030-02-18T10:00:00Z",
+	)
+
+	resp = ta.do(http.MethodGet, "/api/publik/slot-konselor?dari=2030-01-01&sampai=2030-03-01", "", noBody())
+	expectError(t, resp, http.StatusBadRequest, "BAD_REQUEST")
+
+	resp = ta.do(http.MethodGet, "/api/admin/jadwal-konselor/"+konselorID, konselor, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := len(resp.data()["jadwal"].([]interface{})); got != 1 {
+		t.Fatalf("jadwal count = %d", got)
+	}
+	if got := len(resp.data()["pengecualian"].([]interface{})); got != 2 {
+		t.Fatalf("pengecualian count = %d", got)
+	}
+}
+
+func TestJadwalKonselorHanyaBisaDiaturPemiliknya(t *testing.T) {
+	ta := newTestApp(t)
+	konselor := ta.login(konselorEmail)
+	admin := ta.login(adminEmail)
+	konselor2ID := ta.userID(konselor2Email)
+
+	resp := ta.do(http.MethodPost, "/api/admin/jadwal-konselor/"+konselor2ID, konselor, formBody(map[string]string{
+		"hari":        "1",
+		"jam_mulai":   "09:00",
+		"jam_selesai": "11:00",
+	}, nil))
+	expectError(t, resp, http.StatusForbidden, "FORBIDDEN")
+
+	setupJadwalSenin(t, ta, admin, konselor2ID)
+
+	resp = ta.do(http.MethodGet, "/api/admin/jadwal-konselor/"+ta.userID(adminEmail), admin, noBody())
+	expectError(t, resp, http.StatusNotFound, "NOT_FOUND")
+}
+
+func TestBookingSlotKonselor(t *testing.T) {
+	ta := newTestApp(t)
+	konselor := ta.login(konselorEmail)
+	konselorID := ta.userID(konselorEmail)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	setupJadwalSenin(t, ta, konselor, konselorID)
+
+	book := func(token, start string) response {
+		return ta.do(http.MethodPost, "/api/masyarakat/booking-janjitemu", token, formBody(map[string]string{
+			"konselor_id":          konselorID,
+			"waktu_dimulai":        start,
+			"keperluan_konsultasi": "Konsultasi pendampingan",
+		}, nil))
+	}
+
+	// Waktu di luar slot jadwal tidak bisa dipesan.
+	expectError(t, book(warga, "2030-02-04T09:30:00"), http.StatusConflict, "SLOT_UNAVAILABLE")
+
+	resp := book(warga, "2030-02-04T09:00:00")
+	expectStatus(t, resp, http.StatusCreated)
+	id := str(resp.data()["id"])
+	if got := str(resp.data()["waktu_selesai"]); got != "2030-02-04T10:00:00Z" {
+		t.Fatalf("waktu_selesai = %q", got)
+	}
+	if got := str(resp.data()["status"]); got != "Belum disetujui" {
+		t.Fatalf("status = %q", got)
+	}
+
+	expectError(t, book(warga2, "2030-02-04T09:00:00"), http.StatusConflict, "SLOT_UNAVAILABLE")
+	expectSlots(t, slotStarts(t, ta, "konselor_id="+konselorID+"&dari="+seninPertama+"&sampai="+seninPertama),
+		"2030-02-04T10:00:00Z",
+	)
+
+	// Waktu janji temu dari slot tidak bisa diubah, hanya dibatalkan.
+	resp = ta.do(http.MethodPut, "/api/masyarakat/edit-janjitemu/"+id, warga, jsonBody(map[string]string{
+		"waktu_dimulai": "2030-02-04T10:00:00Z",
+		"waktu_selesai": "2030-02-04T11:00:00Z",
+	}))
+	expectError(t, resp, http.StatusForbidden, "FORBIDDEN")
+
+	resp = ta.do(http.MethodPut, "/api/masyarakat/batal-janjitemu/"+id, warga, formBody(map[string]string{
+		"alasan_dibatalkan": "Berhalangan",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+
+	// Slot yang dibatalkan bisa dipesan lagi.
+	expectStatus(t, book(warga2, "2030-02-04T09:00:00"), http.StatusCreated)
+}
+
+func TestCreateJanjiTemuDenganKonselorMemakaiSlot(t *testing.T) {
+	ta := newTestApp(t)
+	konselor := ta.login(konselorEmail)
+	konselorID := ta.userID(konselorEmail)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	setupJadwalSenin(t, ta, konselor, konselorID)
+
+	create := func(token, mulai, selesai string) response {
+		return ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", token, formBody(map[string]string{
+			"konselor_id":          konselorID,
+			"waktu_dimulai":        mulai,
+			"waktu_selesai":        selesai,
+			"keperluan_konsultasi": "Konsultasi pendampingan",
+		}, nil))
+	}
+
+	expectError(t, create(warga, "2030-02-04T09:30:00", "2030-02-04T10:30:00"), http.StatusConflict, "SLOT_UNAVAILABLE")
+
+	// Waktu selesai mengikuti slot, bukan permintaan.
+	resp := create(warga, "2030-02-04T09:00:00", "2030-02-04T09:15:00")
+	expectStatus(t, resp, http.StatusCreated)
+	if got := str(resp.data()["waktu_selesai"]); got != "2030-02-04T10:00:00Z" {
+		t.Fatalf("waktu_selesai = %q", got)
+	}
+	if got := str(resp.data()["konselor_id"]); got != konselorID {
+		t.Fatalf("konselor_id = %q", got)
+	}
+
+	expectError(t, create(warga2, "2030-02-04T09:00:00", "2030-02-04T10:00:00"), http.StatusConflict, "SLOT_UNAVAILABLE")
+	expectSlots(t, slotStarts(t, ta, "konselor_id="+konselorID+"&dari="+seninPertama+"&sampai="+seninPertama),
+		"2030-02-04T10:00:00Z",
+	)
+}
+
+func TestBookingSlotBersamaanHanyaSatuBerhasil(t *testing.T) {
+	ta := newTestApp(t)
+	konselor := ta.login(konselorEmail)
+	konselorID := ta.userID(konselorEmail)
+	tokens := []string{ta.login(masyarakatEmail), ta.login(masyarakat2Email)}
+	setupJadwalSenin(t, ta, konselor, konselorID)
+
+	const attempts = 6
+	statuses := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+			resp := ta.do(http.MethodPost, "/api/masyarakat/booking-janjitemu", token, formBody(map[string]string{
+				"konselor_id":          konselorID,
+				"waktu_dimulai":        "2030-02-04T09:00:00",
+				"keperluan_konsultasi": "Konsultasi pendampingan",
+			}, nil))
+			statuses <- resp.Status
+		}(tokens[i%len(tokens)])
+	}
+	wg.Wait()
+	close(statuses)
+
+	created := 0
+	for status := range statuses {
+		switch status {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+		default:
+			t.Fatalf("unexpected status %d", status)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("expected exactly 1 booking, got %d", created)
+	}
+}
@@ -0,0 +1,175 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+func usulJadwal(ta *testApp, group, token, id, mulai, selesai string) response {
+	return ta.do(http.MethodPost, "/api/"+group+"/jadwal-ulang-janjitemu/"+id, token, jsonBody(map[string]string{
+		"waktu_dimulai": mulai,
+		"waktu_selesai": selesai,
+		"alasan":        "Berhalangan",
+	}))
+}
+
+func TestJadwalUlangUsulanDanTandingan(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	admin := ta.login(adminEmail)
+	konselorID := ta.userID(konselorEmail)
+
+	id := createJanjiTemu(t, ta, warga)
+	resp := ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, admin, jsonBody(map[string]string{
+		"konselor_id": konselorID,
+		"ruangan":     "Ruang 1",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+
+	// Janji temu milik orang lain tidak terlihat.
+	expectError(t, usulJadwal(ta, "masyarakat", warga2, id, "2030-02-02T09:00:00", "2030-02-02T10:00:00"),
+		http.StatusNotFound, "NOT_FOUND")
+
+	expectStatus(t, usulJadwal(ta, "masyarakat", warga, id, "2030-02-02T09:00:00", "2030-02-02T10:00:00"), http.StatusCreated)
+	if got := janjiTemuStatus(t, ta, warga, id); got != "Menunggu jadwal ulang" {
+		t.Fatalf("status after proposal = %q", got)
+	}
+	expectError(t, usulJadwal(ta, "masyarakat", warga, id, "2030-02-02T11:00:00", "2030-02-02T12:00:00"),
+		http.StatusConflict, "CONFLICT")
+	expectError(t, ta.do(http.MethodPut, "/api/masyarakat/terima-jadwal-ulang-janjitemu/"+id, warga, noBody()),
+		http.StatusForbidden, "FORBIDDEN")
+	expectError(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, admin, noBody()),
+		http.StatusConflict, "CONFLICT")
+
+	// Petugas membalas dengan usulan tandingan, lalu masyarakat menerimanya.
+	expectStatus(t, usulJadwal(ta, "admin", admin, id, "2030-02-03T13:00:00", "2030-02-03T14:00:00"), http.StatusCreated)
+	expectError(t, ta.do(http.MethodPut, "/api/admin/terima-jadwal-ulang-janjitemu/"+id, admin, noBody()),
+		http.StatusForbidden, "FORBIDDEN")
+	resp = ta.do(http.MethodPut, "/api/masyarakat/terima-jadwal-ulang-janjitemu/"+id, warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["status"]); got != "Disetujui" {
+		t.Fatalf("status after accept = %q", got)
+	}
+	if got := str(resp.data()["waktu_dimulai"]); got != "2030-02-03T13:00:00Z" {
+		t.Fatalf("waktu_dimulai after accept = %q", got)
+	}
+
+	// Usulan yang ditolak tidak mengubah waktu.
+	expectStatus(t, usulJadwal(ta, "admin", admin, id, "2030-02-04T13:00:00", "2030-02-04T14:00:00"), http.StatusCreated)
+	resp = ta.do(http.MethodPut, "/api/masyarakat/tolak-jadwal-ulang-janjitemu/"+id, warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["waktu_dimulai"]); got != "2030-02-03T13:00:00Z" {
+		t.Fatalf("waktu_dimulai after decline = %q", got)
+	}
+	expectError(t, ta.do(http.MethodPut, "/api/masyarakat/tolak-jadwal-ulang-janjitemu/"+id, warga, noBody()),
+		http.StatusNotFound, "NOT_FOUND")
+
+	resp = ta.do(http.MethodGet, "/api/masyarakat/riwayat-jadwal-ulang-janjitemu/"+id, warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	var statuses []string
+	for _, item := range resp.list() {
+		usulan, _ := item.(map[string]interface{})
+		statuses = append(statuses, str(usulan["pihak"])+":"+str(usulan["status"]))
+	}
+	want := []string{"masyarakat:Dibalas", "petugas:Diterima", "petugas:Ditolak"}
+	if len(statuses) != len(want) || statuses[0] != want[0] || statuses[1] != want[1] || statuses[2] != want[2] {
+		t.Fatalf("history = %v, want %v", statuses, want)
+	}
+}
+
+func TestJadwalUlangMengikutiStatusDanBentrokan(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	admin := ta.login(adminEmail)
+	konselorID := ta.userID(konselorEmail)
+
+	approve := func(id string) {
+		resp := ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, admin, jsonBody(map[string]string{
+			"konselor_id": konselorID,
+		}))
+		expectStatus(t, resp, http.StatusOK)
+	}
+	pertama := createJanjiTemu(t, ta, warga)
+	approve(pertama)
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga2, formBody(map[string]string{
+		"waktu_dimulai":        "2030-02-01T11:00:00",
+		"waktu_selesai":        "2030-02-01T12:00:00",
+		"keperluan_konsultasi": "Konsultasi pendampingan",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+	kedua := str(resp.data()["id"])
+	approve(kedua)
+
+	// Waktu baru yang bentrok dengan janji temu konselor yang sama ditolak.
+	expectError(t, usulJadwal(ta, "masyarakat", warga2, kedua, "2030-02-01T09:30:00", "2030-02-01T10:30:00"),
+		http.StatusConflict, "SCHEDULE_CONFLICT")
+
+	// Janji temu yang sudah ditolak tidak bisa dijadwalkan ulang atau disetujui.
+	resp = ta.do(http.MethodPut, "/api/admin/cancel-janjitemu/"+pertama, admin, formBody(map[string]string{
+		"alasan_ditolak": "Konselor berhalangan",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+	expectError(t, usulJadwal(ta, "masyarakat", warga, pertama, "2030-02-05T09:00:00", "2030-02-05T10:00:00"),
+		http.StatusConflict, "CONFLICT")
+	expectError(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+pertama, admin, noBody()),
+		http.StatusConflict, "CONFLICT")
+
+	// Menolak janji temu yang sedang dijadwalkan ulang ikut menutup usulannya.
+	expectStatus(t, usulJadwal(ta, "admin", admin, kedua, "2030-02-01T09:30:00", "2030-02-01T10:30:00"), http.StatusCreated)
+	resp = ta.do(http.MethodPut, "/api/admin/cancel-janjitemu/"+kedua, admin, formBody(map[string]string{
+		"alasan_ditolak": "Konselor berhalangan",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+	resp = ta.do(http.MethodGet, "/api/admin/riwayat-jadwal-ulang-janjitemu/"+kedua, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	usulan, _ := resp.list()[0].(map[string]interface{})
+	if got := str(usulan["status"]); got != "Ditolak" {
+		t.Fatalf("pending proposal after reject = %q", got)
+	}
+}
+
+func TestJadwalUlangTetapMenahanWaktuDanPemilik(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	admin := ta.login(adminEmail)
+	konselor2 := ta.login(konselor2Email)
+	konselorID := ta.userID(konselorEmail)
+
+	id := createJanjiTemu(t, ta, warga)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, admin, jsonBody(map[string]string{
+		"konselor_id": konselorID,
+	})), http.StatusOK)
+	expectStatus(t, usulJadwal(ta, "masyarakat", warga, id, "2030-02-02T09:00:00", "2030-02-02T10:00:00"), http.StatusCreated)
+
+	// Selama usulan belum diterima, waktu lama tetap milik janji temu ini.
+	lain := createJanjiTemu(t, ta, warga2)
+	expectError(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+lain, admin, jsonBody(map[string]string{
+		"konselor_id": konselorID,
+	})), http.StatusConflict, "SCHEDULE_CONFLICT")
+
+	// Konselor lain tidak bisa menanggapi usulan janji temu yang bukan miliknya.
+	expectError(t, ta.do(http.MethodPut, "/api/admin/terima-jadwal-ulang-janjitemu/"+id, konselor2, noBody()),
+		http.StatusForbidden, "FORBIDDEN")
+	expectError(t, usulJadwal(ta, "admin", konselor2, id, "2030-02-05T09:00:00", "2030-02-05T10:00:00"),
+		http.StatusForbidden, "FORBIDDEN")
+
+	// Usulan ditolak: janji temu kembali disetujui pada waktu lamanya.
+	resp := ta.do(http.MethodPut, "/api/admin/tolak-jadwal-ulang-janjitemu/"+id, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["status"]); got != "Disetujui" {
+		t.Fatalf("status after decline = %q", got)
+	}
+
+	// Masyarakat hanya bisa membatalkan janji temu miliknya, termasuk yang
+	// sudah disetujui.
+	batal := formBody(map[string]string{"alasan_dibatalkan": "Berhalangan"}, nil)
+	expectError(t, ta.do(http.MethodPut, "/api/masyarakat/batal-janjitemu/"+id, warga2, batal), http.StatusNotFound, "NOT_FOUND")
+	batal = formBody(map[string]string{"alasan_dibatalkan": "Berhalangan"}, nil)
+	expectStatus(t, ta.do(http.MethodPut, "/api/masyarakat/batal-janjitemu/"+id, warga, batal), http.StatusOK)
+	if got := janjiTemuStatus(t, ta, warga, id); got != "Dibatalkan" {
+		t.Fatalf("status after cancel = %q", got)
+	}
+}
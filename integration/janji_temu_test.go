@@ -0,0 +1,207 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func createJanjiTemu(t *testing.T, ta *testApp, token string) string {
+	t.Helper()
+
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", token, formBody(map[string]string{
+		"waktu_dimulai":        "2030-02-01T09:00:00",
+		"waktu_selesai":        "2030-02-01T10:00:00",
+		"keperluan_konsultasi": "Konsultasi pendampingan",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+	if got := str(resp.data()["status"]); got != "Belum disetujui" {
+		t.Fatalf("status after create = %q", got)
+	}
+	return str(resp.data()["id"])
+}
+
+func janjiTemuStatus(t *testing.T, ta *testApp, token, id string) string {
+	t.Helper()
+
+	resp := ta.do(http.MethodGet, "/api/masyarakat/detail-janjitemu/"+id, token, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	return str(resp.data()["status"])
+}
+
+func TestJanjiTemuApproval(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, formBody(map[string]string{
+		"waktu_dimulai": "besok pagi",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{
+		"waktu_dimulai":        "datetime",
+		"waktu_selesai":        "required",
+		"keperluan_konsultasi": "required",
+	})
+
+	id := createJanjiTemu(t, ta, warga)
+
+	resp = ta.do(http.MethodGet, "/api/masyarakat/janjitemus", warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if len(resp.list()) != 1 {
+		t.Fatalf("expected 1 janji temu for user, got %v", resp.payload())
+	}
+
+	resp = ta.do(http.MethodGet, "/api/admin/janjitemus", konselor, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if len(resp.list()) != 1 {
+		t.Fatalf("expected 1 janji temu for konselor, got %v", resp.payload())
+	}
+
+	resp = ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := janjiTemuStatus(t, ta, warga, id); got != "Disetujui" {
+		t.Fatalf("status after approve = %q", got)
+	}
+
+	// Detail hanya untuk pemilik dan tidak membocorkan data akun konselor.
+	resp = ta.do(http.MethodGet, "/api/masyarakat/detail-janjitemu/"+id, warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	detail, _ := resp.data()["konselor"].(map[string]interface{})
+	if str(detail["full_name"]) == "" || str(detail["password"]) != "" || str(detail["email"]) != "" || str(detail["phone_number"]) != "" {
+		t.Fatalf("konselor in appointment detail = %v", detail)
+	}
+	warga2 := ta.login(masyarakat2Email)
+	expectError(t, ta.do(http.MethodGet, "/api/masyarakat/detail-janjitemu/"+id, warga2, noBody()), http.StatusNotFound, "NOT_FOUND")
+
+	// Janji temu yang sudah disetujui tidak bisa diubah lagi oleh masyarakat.
+	resp = ta.do(http.MethodPut, "/api/masyarakat/edit-janjitemu/"+id, warga, jsonBody(map[string]string{
+		"waktu_dimulai": "2030-02-02T09:00:00Z",
+		"waktu_selesai": "2030-02-02T10:00:00Z",
+	}))
+	expectStatus(t, resp, http.StatusConflict)
+
+	resp = ta.do(http.MethodPut, "/api/admin/approve-janjitemu/999", konselor, noBody())
+	expectStatus(t, resp, http.StatusNotFound)
+}
+
+func TestJanjiTemuRejectedByAdmin(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	admin := ta.login(adminEmail)
+	id := createJanjiTemu(t, ta, warga)
+
+	resp := ta.do(http.MethodPut, "/api/admin/cancel-janjitemu/"+id, admin, formBody(map[string]string{
+		"alasan_ditolak": "Jadwal konselor penuh",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+	if got := janjiTemuStatus(t, ta, warga, id); got != "Ditolak" {
+		t.Fatalf("status after reject = %q", got)
+	}
+}
+
+// bentrokan mengembalikan id janji temu dan jenis bentroknya dari
+// error.details respons 409 SCHEDULE_CONFLICT.
+func bentrokan(resp response) map[string][]interface{} {
+	detail, _ := resp.Body["error"].(map[string]interface{})
+	items, _ := detail["details"].([]interface{})
+	got := map[string][]interface{}{}
+	for _, item := range items {
+		clash, _ := item.(map[string]interface{})
+		jenis, _ := clash["bentrok"].([]interface{})
+		got[str(clash["id"])] = jenis
+	}
+	return got
+}
+
+func TestJanjiTemuBentrokPerKonselorDanRuangan(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	admin := ta.login(adminEmail)
+	konselorID := ta.userID(konselorEmail)
+	konselor2ID := ta.userID(konselor2Email)
+
+	create := func(token, mulai, selesai string) string {
+		resp := ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", token, formBody(map[string]string{
+			"waktu_dimulai":        mulai,
+			"waktu_selesai":        selesai,
+			"keperluan_konsultasi": "Konsultasi pendampingan",
+		}, nil))
+		expectStatus(t, resp, http.StatusCreated)
+		return str(resp.data()["id"])
+	}
+	approve := func(id, konselor, ruangan string) response {
+		return ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, admin, jsonBody(map[string]string{
+			"konselor_id": konselor,
+			"ruangan":     ruangan,
+		}))
+	}
+
+	pertama := create(warga, "2030-03-01T09:00:00", "2030-03-01T10:00:00")
+	kedua := create(warga2, "2030-03-01T09:30:00", "2030-03-01T10:30:00")
+	ketiga := create(warga2, "2030-03-01T10:00:00", "2030-03-01T11:00:00")
+
+	// Admin bukan konselor, sehingga konselor harus dipilih.
+	expectFieldErrors(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+pertama, admin, noBody()),
+		map[string]string{"konselor_id": "required"})
+
+	resp := approve(pertama, konselorID, "Ruang 1")
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["konselor_id"]); got != konselorID {
+		t.Fatalf("konselor_id after approve = %q", got)
+	}
+
+	resp = approve(kedua, konselorID, "Ruang 2")
+	expectError(t, resp, http.StatusConflict, "SCHEDULE_CONFLICT")
+	if got := bentrokan(resp); fmt.Sprint(got) != fmt.Sprint(map[string][]interface{}{pertama: {"konselor"}}) {
+		t.Fatalf("clashes = %v", got)
+	}
+	if got := janjiTemuStatus(t, ta, warga2, kedua); got != "Belum disetujui" {
+		t.Fatalf("status after conflict = %q", got)
+	}
+
+	resp = approve(kedua, konselor2ID, "Ruang 1")
+	expectError(t, resp, http.StatusConflict, "SCHEDULE_CONFLICT")
+	if got := bentrokan(resp); fmt.Sprint(got) != fmt.Sprint(map[string][]interface{}{pertama: {"ruangan"}}) {
+		t.Fatalf("clashes = %v", got)
+	}
+
+	expectStatus(t, approve(kedua, konselor2ID, "Ruang 2"), http.StatusOK)
+	// Berurutan tanpa beririsan tidak dianggap bentrok.
+	expectStatus(t, approve(ketiga, konselorID, "Ruang 1"), http.StatusOK)
+
+	// Pengajuan dengan konselor pilihan hanya bisa memakai slot kosong
+	// jadwalnya, bukan waktu bebas.
+	resp = ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, formBody(map[string]string{
+		"konselor_id":          konselorID,
+		"waktu_dimulai":        "2030-03-01T13:00:00",
+		"waktu_selesai":        "2030-03-01T14:00:00",
+		"keperluan_konsultasi": "Konsultasi lanjutan",
+	}, nil))
+	expectError(t, resp, http.StatusConflict, "SLOT_UNAVAILABLE")
+}
+
+func TestKonselorHanyaMenyetujuiUntukDirinya(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+	konselorID := ta.userID(konselorEmail)
+	konselor2ID := ta.userID(konselor2Email)
+
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, formBody(map[string]string{
+		"waktu_dimulai":        "2030-03-01T09:00:00",
+		"waktu_selesai":        "2030-03-01T10:00:00",
+		"keperluan_konsultasi": "Konsultasi pendampingan",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+	id := str(resp.data()["id"])
+
+	resp = ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, jsonBody(map[string]string{"konselor_id": konselor2ID}))
+	expectError(t, resp, http.StatusForbidden, "FORBIDDEN")
+
+	resp = ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["konselor_id"]); got != konselorID {
+		t.Fatalf("konselor_id after approve = %q", got)
+	}
+}
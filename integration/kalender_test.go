@@ -0,0 +1,166 @@
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// feedPath mengubah feed_url absolut menjadi path yang bisa dipakai ta.do.
+func feedPath(t *testing.T, resp response) string {
+	t.Helper()
+	expectStatus(t, resp, http.StatusCreated)
+	url := str(resp.data()["feed_url"])
+	i := strings.Index(url, "/api/kalender/")
+	if i < 0 || !strings.HasSuffix(url, ".ics") {
+		t.Fatalf("feed_url = %q", url)
+	}
+	return url[i:]
+}
+
+func calendar(t *testing.T, resp response) string {
+	t.Helper()
+	expectStatus(t, resp, http.StatusOK)
+	body := str(resp.Body["raw"])
+	if !strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(body, "END:VCALENDAR\r\n") {
+		t.Fatalf("not an iCalendar body: %q", body)
+	}
+	return body
+}
+
+func TestFeedKalenderJanjiTemu(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+
+	disetujui := createJanjiTemu(t, ta, warga)
+	resp := ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+disetujui, konselor, jsonBody(map[string]string{
+		"ruangan": "Ruang Konseling; Lantai 2",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	resp = ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, formBody(map[string]string{
+		"waktu_dimulai":        "2030-02-05T09:00:00",
+		"waktu_selesai":        "2030-02-05T10:00:00",
+		"keperluan_konsultasi": "Rahasia",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+	menunggu := str(resp.data()["id"])
+
+	feed := feedPath(t, ta.do(http.MethodPost, "/api/masyarakat/kalender/token", warga, noBody()))
+	body := calendar(t, ta.do(http.MethodGet, feed, "", noBody()))
+	for _, want := range []string{
+		"UID:janji-temu-" + disetujui + "@pedika\r\n",
+		"DTSTART:20300201T090000Z\r\n",
+		"DTEND:20300201T100000Z\r\n",
+		"LOCATION:Ruang Konseling\\; Lantai 2\r\n",
+		"STATUS:CONFIRMED\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("feed missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "janji-temu-"+menunggu+"@") || strings.Contains(body, "Rahasia") {
+		t.Fatalf("feed contains unapproved or confidential data:\n%s", body)
+	}
+
+	// Konselor yang menangani melihat janji temu yang sama di feed-nya.
+	konselorFeed := feedPath(t, ta.do(http.MethodPost, "/api/admin/kalender/token", konselor, noBody()))
+	if body := calendar(t, ta.do(http.MethodGet, konselorFeed, "", noBody())); !strings.Contains(body, "UID:janji-temu-"+disetujui+"@pedika") {
+		t.Fatalf("counselor feed missing appointment:\n%s", body)
+	}
+
+	// Perubahan waktu dan pembatalan ikut terbaca di feed.
+	expectStatus(t, usulJadwal(ta, "admin", konselor, disetujui, "2030-02-06T13:00:00", "2030-02-06T14:00:00"), http.StatusCreated)
+	expectStatus(t, ta.do(http.MethodPut, "/api/masyarakat/terima-jadwal-ulang-janjitemu/"+disetujui, warga, noBody()), http.StatusOK)
+	if body := calendar(t, ta.do(http.MethodGet, feed, "", noBody())); !strings.Contains(body, "DTSTART:20300206T130000Z\r\n") {
+		t.Fatalf("feed not updated after reschedule:\n%s", body)
+	}
+	resp = ta.do(http.MethodPut, "/api/admin/cancel-janjitemu/"+disetujui, konselor, formBody(map[string]string{
+		"alasan_ditolak": "Konselor berhalangan",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+	if body := calendar(t, ta.do(http.MethodGet, feed, "", noBody())); !strings.Contains(body, "STATUS:CANCELLED\r\n") {
+		t.Fatalf("feed not updated after cancellation:\n%s", body)
+	}
+
+	// Membuat token baru mencabut URL feed lama.
+	feedBaru := feedPath(t, ta.do(http.MethodPost, "/api/masyarakat/kalender/token", warga, noBody()))
+	expectError(t, ta.do(http.MethodGet, feed, "", noBody()), http.StatusNotFound, "NOT_FOUND")
+	calendar(t, ta.do(http.MethodGet, feedBaru, "", noBody()))
+	expectStatus(t, ta.do(http.MethodDelete, "/api/masyarakat/kalender/token", warga, noBody()), http.StatusOK)
+	expectError(t, ta.do(http.MethodGet, feedBaru, "", noBody()), http.StatusNotFound, "NOT_FOUND")
+}
+
+func TestUnduhICalJanjiTemu(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	konselor := ta.login(konselorEmail)
+	id := createJanjiTemu(t, ta, warga)
+
+	expectError(t, ta.do(http.MethodGet, "/api/masyarakat/ics-janjitemu/"+id, warga, noBody()), http.StatusConflict, "CONFLICT")
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody()), http.StatusOK)
+
+	body := calendar(t, ta.do(http.MethodGet, "/api/masyarakat/ics-janjitemu/"+id, warga, noBody()))
+	if strings.Count(body, "BEGIN:VEVENT") != 1 || !strings.Contains(body, "UID:janji-temu-"+id+"@pedika") {
+		t.Fatalf("unexpected ics:\n%s", body)
+	}
+	calendar(t, ta.do(http.MethodGet, "/api/admin/ics-janjitemu/"+id, konselor, noBody()))
+	expectError(t, ta.do(http.MethodGet, "/api/masyarakat/ics-janjitemu/"+id, warga2, noBody()), http.StatusNotFound, "NOT_FOUND")
+}
+
+func TestUnduhICalEvent(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.login(adminEmail)
+
+	resp := ta.do(http.MethodPost, "/api/admin/create-event", admin, formBody(map[string]string{
+		"nama_event":          "Sosialisasi Perlindungan Anak, Balige",
+		"deskripsi_event":     "Sosialisasi untuk orang tua dan guru di seluruh kecamatan, terbuka untuk umum tanpa pendaftaran",
+		"tanggal_pelaksanaan": "2030-03-10 09:00",
+	}, map[string]string{"thumbnail_event": "poster.png"}))
+	expectStatus(t, resp, http.StatusCreated)
+	id := str(resp.data()["id"])
+
+	body := calendar(t, ta.do(http.MethodGet, "/api/ics-event/"+id, "", noBody()))
+	if !strings.Contains(body, "SUMMARY:Sosialisasi Perlindungan Anak\\, Balige\r\n") {
+		t.Fatalf("summary not escaped:\n%s", body)
+	}
+	for _, line := range strings.Split(body, "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line not folded: %q", line)
+		}
+	}
+	expectError(t, ta.do(http.MethodGet, "/api/ics-event/999", "", noBody()), http.StatusNotFound, "NOT_FOUND")
+}
+
+func TestFeedKalenderSelamaJadwalUlang(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+
+	id := createJanjiTemu(t, ta, warga)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody()), http.StatusOK)
+	expectStatus(t, usulJadwal(ta, "masyarakat", warga, id, "2030-02-02T09:00:00", "2030-02-02T10:00:00"), http.StatusCreated)
+
+	// Sampai usulan diterima, janji temu tetap di feed pada waktu lamanya.
+	feed := feedPath(t, ta.do(http.MethodPost, "/api/masyarakat/kalender/token", warga, noBody()))
+	body := calendar(t, ta.do(http.MethodGet, feed, "", noBody()))
+	for _, want := range []string{
+		"UID:janji-temu-" + id + "@pedika\r\n",
+		"DTSTART:20300201T090000Z\r\n",
+		"STATUS:CONFIRMED\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("feed missing %q:\n%s", want, body)
+		}
+	}
+
+	// Janji temu yang belum pernah disetujui tidak masuk feed walaupun
+	// sedang dijadwal ulang.
+	lain := createJanjiTemu(t, ta, warga)
+	expectStatus(t, usulJadwal(ta, "masyarakat", warga, lain, "2030-02-03T09:00:00", "2030-02-03T10:00:00"), http.StatusCreated)
+	body = calendar(t, ta.do(http.MethodGet, feed, "", noBody()))
+	if strings.Contains(body, "UID:janji-temu-"+lain+"@pedika") {
+		t.Fatalf("unapproved appointment in feed:\n%s", body)
+	}
+}
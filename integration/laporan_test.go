@@ -0,0 +1,218 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+func createLaporan(t *testing.T, ta *testApp, token string) string {
+	t.Helper()
+
+	resp := ta.do(http.MethodPost, "/api/masyarakat/buat-laporan", token, formBody(map[string]string{
+		"kategori_kekerasan_id": "1",
+		"tanggal_kejadian":      "2024-05-01T10:00:00",
+		"kategori_lokasi_kasus": "Rumah Tangga",
+		"alamat_tkp":            "Balige",
+		"alamat_detail_tkp":     "Jl. Sisingamangaraja",
+		"kronologis_kasus":      "Kronologis singkat kejadian.",
+	}, map[string]string{"dokumentasi": "bukti.jpg"}))
+	expectStatus(t, resp, http.StatusCreated)
+
+	noRegistrasi := str(resp.data()["no_registrasi"])
+	if noRegistrasi == "" {
+		t.Fatalf("laporan created without no_registrasi: %v", resp.Body)
+	}
+	return noRegistrasi
+}
+
+func laporanStatus(t *testing.T, ta *testApp, token, noRegistrasi string) string {
+	t.Helper()
+
+	resp := ta.do(http.MethodGet, "/api/masyarakat/detail-laporan/"+noRegistrasi, token, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	return str(resp.data()["status"])
+}
+
+func TestLaporanLifecycle(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	admin := ta.login(adminEmail)
+
+	noRegistrasi := createLaporan(t, ta, warga)
+	if files := ta.uploads.Files(); len(files) != 1 || files[0] != "bukti.jpg" {
+		t.Fatalf("uploaded files = %v, want [bukti.jpg]", files)
+	}
+	if got := laporanStatus(t, ta, warga, noRegistrasi); got != "Laporan masuk" {
+		t.Fatalf("status after create = %q", got)
+	}
+
+	resp := ta.do(http.MethodGet, "/api/masyarakat/laporans", warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if len(resp.list()) != 1 {
+		t.Fatalf("expected 1 laporan for user, got %v", resp.payload())
+	}
+
+	resp = ta.do(http.MethodGet, "/api/admin/laporans", admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if len(resp.list()) != 1 {
+		t.Fatalf("expected 1 laporan for admin, got %v", resp.payload())
+	}
+
+	resp = ta.do(http.MethodPut, "/api/admin/lihat-laporan/"+noRegistrasi, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := laporanStatus(t, ta, warga, noRegistrasi); got != "Dilihat" {
+		t.Fatalf("status after lihat = %q", got)
+	}
+
+	resp = ta.do(http.MethodPut, "/api/admin/proses-laporan/"+noRegistrasi, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := laporanStatus(t, ta, warga, noRegistrasi); got != "Diproses" {
+		t.Fatalf("status after proses = %q", got)
+	}
+
+	resp = ta.do(http.MethodPut, "/api/admin/laporan-selesai/"+noRegistrasi, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := laporanStatus(t, ta, warga, noRegistrasi); got != "Selesai" {
+		t.Fatalf("status after selesai = %q", got)
+	}
+
+	resp = ta.do(http.MethodPut, "/api/admin/proses-laporan/TIDAK-ADA", admin, noBody())
+	expectError(t, resp, http.StatusNotFound, "NOT_FOUND")
+}
+
+func TestBatalkanLaporan(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	noRegistrasi := createLaporan(t, ta, warga)
+
+	resp := ta.do(http.MethodPut, "/api/masyarakat/batalkan-laporan/"+noRegistrasi, warga, formBody(nil, nil))
+	expectError(t, resp, http.StatusUnprocessableEntity, "VALIDATION_FAILED")
+
+	resp = ta.do(http.MethodPut, "/api/masyarakat/batalkan-laporan/"+noRegistrasi, warga, formBody(map[string]string{
+		"alasan_dibatalkan": "Salah input",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+	if got := laporanStatus(t, ta, warga, noRegistrasi); got != "Dibatalkan" {
+		t.Fatalf("status after batal = %q", got)
+	}
+}
+
+func TestEditLaporanByOtherUserIsForbidden(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	noRegistrasi := createLaporan(t, ta, warga)
+
+	resp := ta.do(http.MethodPost, "/api/user/register", "", jsonBody(map[string]string{
+		"full_name":    "Warga Lain",
+		"email":        "lain@test.local",
+		"phone_number": "081200000009",
+		"password":     "rahasia123",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	other := ta.do(http.MethodPost, "/api/user/login", "", jsonBody(map[string]string{
+		"email":    "lain@test.local",
+		"password": "rahasia123",
+	}))
+	expectStatus(t, other, http.StatusOK)
+
+	resp = ta.do(http.MethodPut, "/api/masyarakat/edit-laporan/"+noRegistrasi, str(other.data()["token"]), formBody(map[string]string{
+		"kronologis_kasus": "Diubah orang lain",
+	}, nil))
+	expectStatus(t, resp, http.StatusForbidden)
+}
+
+func TestTrackingLaporan(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	admin := ta.login(adminEmail)
+	noRegistrasi := createLaporan(t, ta, warga)
+
+	resp := ta.do(http.MethodPost, "/api/admin/create-tracking-laporan", admin, formBody(map[string]string{
+		"keterangan": "",
+	}, nil))
+	expectError(t, resp, http.StatusUnprocessableEntity, "VALIDATION_FAILED")
+
+	resp = ta.do(http.MethodPost, "/api/admin/create-tracking-laporan", admin, formBody(map[string]string{
+		"no_registrasi": "TIDAK-ADA",
+		"keterangan":    "Tidak ada",
+	}, nil))
+	expectStatus(t, resp, http.StatusBadRequest)
+
+	resp = ta.do(http.MethodPost, "/api/admin/create-tracking-laporan", admin, formBody(map[string]string{
+		"no_registrasi": noRegistrasi,
+		"keterangan":    "Laporan sedang ditindaklanjuti",
+	}, map[string]string{"document": "surat.pdf"}))
+	expectStatus(t, resp, http.StatusCreated)
+	trackingID := str(resp.data()["id"])
+
+	resp = ta.do(http.MethodPut, "/api/admin/edit-tracking-laporan/"+trackingID, admin, formBody(map[string]string{
+		"keterangan": "Pendampingan psikologis dijadwalkan",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+
+	resp = ta.do(http.MethodGet, "/api/masyarakat/detail-laporan/"+noRegistrasi, warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	tracking, _ := resp.data()["tracking_laporan"].([]interface{})
+	if len(tracking) != 1 {
+		t.Fatalf("expected 1 tracking entry, got %v", resp.data()["tracking_laporan"])
+	}
+	entry, _ := tracking[0].(map[string]interface{})
+	if got := str(entry["keterangan"]); got != "Pendampingan psikologis dijadwalkan" {
+		t.Fatalf("tracking keterangan = %q", got)
+	}
+
+	resp = ta.do(http.MethodDelete, "/api/admin/delete-tracking-laporan/"+trackingID, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	resp = ta.do(http.MethodDelete, "/api/admin/delete-tracking-laporan/"+trackingID, admin, noBody())
+	expectStatus(t, resp, http.StatusNotFound)
+}
+
+func TestKorbanDanPelaku(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	admin := ta.login(adminEmail)
+	noRegistrasi := createLaporan(t, ta, warga)
+
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-korban-kekerasan", warga, formBody(map[string]string{
+		"no_registrasi": noRegistrasi,
+		"nik_korban":    "1234567890123456",
+		"nama_korban":   "Korban",
+		"usia_korban":   "17",
+		"jenis_kelamin": "Perempuan",
+	}, map[string]string{"dokumentasi_korban": "korban.jpg"}))
+	expectStatus(t, resp, http.StatusCreated)
+	korbanID := str(resp.data()["id"])
+
+	resp = ta.do(http.MethodPut, "/api/admin/edit-korban-kekerasan/"+korbanID, admin, formBody(map[string]string{
+		"usia_korban": "18",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["usia_korban"]); got != "18" {
+		t.Fatalf("usia korban = %q, want 18", got)
+	}
+
+	resp = ta.do(http.MethodPost, "/api/admin/create-pelaku-kekerasan", admin, formBody(map[string]string{
+		"no_registrasi": noRegistrasi,
+		"nik_pelaku":    "6543210987654321",
+		"nama_pelaku":   "Pelaku",
+		"usia_pelaku":   "40",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+	pelakuID := str(resp.data()["id"])
+
+	resp = ta.do(http.MethodPut, "/api/admin/edit-pelaku-kekerasan/"+pelakuID, admin, formBody(map[string]string{
+		"pekerjaan": "Wiraswasta",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+
+	resp = ta.do(http.MethodGet, "/api/admin/detail-laporan/"+noRegistrasi, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	korban, _ := resp.data()["korban"].([]interface{})
+	pelaku, _ := resp.data()["pelaku"].([]interface{})
+	if len(korban) != 1 || len(pelaku) != 1 {
+		t.Fatalf("expected 1 korban and 1 pelaku, got %d and %d", len(korban), len(pelaku))
+	}
+
+	resp = ta.do(http.MethodDelete, "/api/admin/delete-pelaku-kekerasan/"+pelakuID, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+}
@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"backend-pedika-fiber/migration"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository/repotest"
+	"testing"
+)
+
+// tabelModels adalah semua model yang disimpan di database.
+var tabelModels = []interface{}{
+	&models.User{}, &models.PasswordReset{}, &models.OTPCode{}, &models.RecoveryCode{},
+	&models.LoginAttempt{}, &models.RateLimitCounter{}, &models.ViolenceCategory{},
+	&models.EmergencyContact{}, &models.Content{}, &models.Laporan{}, &models.Korban{},
+	&models.Pelaku{}, &models.TrackingLaporan{}, &models.Event{}, &models.JanjiTemu{},
+	&models.JadwalKonselor{}, &models.PengecualianJadwal{}, &models.SlotJanjiTemu{},
+	&models.UsulanJadwal{}, &models.KalenderToken{}, &models.Pengingat{},
+	&models.CatatanKonsultasi{}, &models.PenilaianLayanan{}, &models.Notification{},
+}
+
+func TestMigrationsCoverModelsAndRollBack(t *testing.T) {
+	db := repotest.Open(t)
+	// Migration tidak memakai struct di package models, jadi setiap kolom
+	// model harus dibuat oleh salah satu migration.
+	cek := func() {
+		t.Helper()
+		for _, model := range tabelModels {
+			stmt := db.Model(model).Statement
+			if err := stmt.Parse(model); err != nil {
+				t.Fatal(err)
+			}
+			if !db.Migrator().HasTable(model) {
+				t.Fatalf("table %s not created by migrations", stmt.Schema.Table)
+			}
+			for _, field := range stmt.Schema.Fields {
+				if field.DBName != "" && !db.Migrator().HasColumn(model, field.DBName) {
+					t.Fatalf("column %s.%s not created by migrations", stmt.Schema.Table, field.DBName)
+				}
+			}
+		}
+	}
+	cek()
+
+	statuses, err := migration.GetStatus(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migration.Down(db, len(statuses)); err != nil {
+		t.Fatalf("rollback all: %v", err)
+	}
+	for _, model := range tabelModels {
+		if db.Migrator().HasTable(model) {
+			t.Fatalf("table for %T left after rollback", model)
+		}
+	}
+	if err := migration.Up(db); err != nil {
+		t.Fatalf("migrate again: %v", err)
+	}
+	cek()
+}
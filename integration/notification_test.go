@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+// notifications mengembalikan isi kotak masuk, terbaru lebih dulu, beserta
+// jumlah yang belum dibaca.
+func (ta *testApp) notifications(t *testing.T, group, token string) ([]map[string]interface{}, string) {
+	t.Helper()
+	resp := ta.do(http.MethodGet, "/api/"+group+"/notifikasi", token, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	items, _ := resp.data()["notifications"].([]interface{})
+	var list []map[string]interface{}
+	for _, item := range items {
+		n, _ := item.(map[string]interface{})
+		list = append(list, n)
+	}
+	return list, str(resp.data()["unread"])
+}
+
+func notificationTypes(list []map[string]interface{}) []string {
+	var types []string
+	for _, n := range list {
+		types = append(types, str(n["type"]))
+	}
+	return types
+}
+
+func TestNotifikasiPerubahanStatusLaporan(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	admin := ta.login(adminEmail)
+	noRegistrasi := createLaporan(t, ta, warga)
+
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/lihat-laporan/"+noRegistrasi, admin, noBody()), http.StatusOK)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/proses-laporan/"+noRegistrasi, admin, noBody()), http.StatusOK)
+	// Memproses ulang tanpa perubahan status tidak menambah notifikasi.
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/proses-laporan/"+noRegistrasi, admin, noBody()), http.StatusOK)
+	expectStatus(t, ta.do(http.MethodPost, "/api/admin/create-tracking-laporan", admin, formBody(map[string]string{
+		"no_registrasi": noRegistrasi,
+		"keterangan":    "Korban sudah didampingi ke rumah sakit",
+	}, map[string]string{"document": "surat.pdf"})), http.StatusCreated)
+
+	list, unread := ta.notifications(t, "masyarakat", warga)
+	types := notificationTypes(list)
+	if len(types) != 3 || types[0] != "tracking_laporan" || types[1] != "laporan_diproses" || types[2] != "laporan_dilihat" || unread != "3" {
+		t.Fatalf("notifications = %v, unread = %s", types, unread)
+	}
+	if got := str(list[0]["body"]); got != "Laporan "+noRegistrasi+": Korban sudah didampingi ke rumah sakit" {
+		t.Fatalf("tracking body = %q", got)
+	}
+	if got := str(list[0]["no_registrasi"]); got != noRegistrasi {
+		t.Fatalf("no_registrasi = %q", got)
+	}
+
+	// Notifikasi hanya bisa dibaca pemiliknya.
+	id := str(list[2]["id"])
+	expectError(t, ta.do(http.MethodPut, "/api/masyarakat/baca-notifikasi/"+id, warga2, noBody()), http.StatusNotFound, "NOT_FOUND")
+	resp := ta.do(http.MethodPut, "/api/masyarakat/baca-notifikasi/"+id, warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if resp.data()["read_at"] == nil {
+		t.Fatalf("read_at not set: %v", resp.data())
+	}
+	resp = ta.do(http.MethodGet, "/api/masyarakat/notifikasi/belum-dibaca", warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["unread"]); got != "2" {
+		t.Fatalf("unread after read = %s", got)
+	}
+
+	resp = ta.do(http.MethodGet, "/api/masyarakat/notifikasi?unread=true", warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if items, _ := resp.data()["notifications"].([]interface{}); len(items) != 2 {
+		t.Fatalf("unread notifications = %v", items)
+	}
+
+	resp = ta.do(http.MethodPut, "/api/masyarakat/baca-semua-notifikasi", warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["updated"]); got != "2" {
+		t.Fatalf("updated = %s", got)
+	}
+	if _, unread := ta.notifications(t, "masyarakat", warga); unread != "0" {
+		t.Fatalf("unread after read all = %s", unread)
+	}
+
+	// Pembatalan oleh pelapor dikabarkan ke petugas yang sudah melihatnya.
+	expectStatus(t, ta.do(http.MethodPut, "/api/masyarakat/batalkan-laporan/"+noRegistrasi, warga, jsonBody(map[string]string{
+		"alasan_dibatalkan": "Sudah diselesaikan secara kekeluargaan",
+	})), http.StatusOK)
+	list, _ = ta.notifications(t, "admin", admin)
+	if types := notificationTypes(list); len(types) != 1 || types[0] != "laporan_dibatalkan" {
+		t.Fatalf("admin notifications = %v", types)
+	}
+}
+
+func TestNotifikasiJanjiTemu(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+	admin := ta.login(adminEmail)
+
+	// Notifikasi ditulis dalam bahasa penerima, bukan bahasa petugas.
+	expectStatus(t, ta.do(http.MethodPut, "/api/masyarakat/edit-profile", warga, jsonBody(map[string]string{"locale": "en"})), http.StatusOK)
+
+	id := createJanjiTemu(t, ta, warga)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody()), http.StatusOK)
+	list, _ := ta.notifications(t, "masyarakat", warga)
+	if len(list) != 1 || str(list[0]["type"]) != "janji_temu_disetujui" || str(list[0]["janji_temu_id"]) != id {
+		t.Fatalf("notifications after approve = %v", list)
+	}
+	if got := str(list[0]["body"]); got != "Your consultation appointment on 01/02/2030 09:00 has been approved." {
+		t.Fatalf("approve body = %q", got)
+	}
+
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/cancel-janjitemu/"+id, admin, jsonBody(map[string]string{
+		"alasan_ditolak": "Konselor berhalangan",
+	})), http.StatusOK)
+	list, _ = ta.notifications(t, "masyarakat", warga)
+	if types := notificationTypes(list); len(types) != 2 || types[0] != "janji_temu_ditolak" {
+		t.Fatalf("notifications after reject = %v", types)
+	}
+
+	// Pembatalan oleh masyarakat dikabarkan ke konselor yang dipilih.
+	setupJadwalSenin(t, ta, admin, ta.userID(konselorEmail))
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, formBody(map[string]string{
+		"konselor_id":          ta.userID(konselorEmail),
+		"waktu_dimulai":        "2030-02-04T09:00:00",
+		"waktu_selesai":        "2030-02-04T10:00:00",
+		"keperluan_konsultasi": "Konsultasi pendampingan",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+	expectStatus(t, ta.do(http.MethodPut, "/api/masyarakat/batal-janjitemu/"+str(resp.data()["id"]), warga, jsonBody(map[string]string{
+		"alasan_dibatalkan": "Sudah ada jadwal lain",
+	})), http.StatusOK)
+	list, unread := ta.notifications(t, "admin", konselor)
+	if types := notificationTypes(list); len(types) != 1 || types[0] != "janji_temu_dibatalkan" || unread != "1" {
+		t.Fatalf("konselor notifications = %v, unread = %s", types, unread)
+	}
+}
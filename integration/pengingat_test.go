@@ -0,0 +1,172 @@
+package integration
+
+import (
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/pengingat"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func at(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02T15:04", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func tick(t *testing.T, s *pengingat.Scheduler, now time.Time) {
+	t.Helper()
+	if err := s.Tick(now); err != nil {
+		t.Fatalf("tick %s: %v", now, err)
+	}
+}
+
+func (ta *testApp) pengingatStatus(t *testing.T, email string) []string {
+	t.Helper()
+	user, _ := ta.repos.Users.FindByEmail(email)
+	list, err := ta.repos.Pengingat.List(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statuses []string
+	for _, p := range list {
+		statuses = append(statuses, p.Jenis+":"+p.Status)
+	}
+	return statuses
+}
+
+func TestPengingatJanjiTemu(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+	id := createJanjiTemu(t, ta, warga)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, jsonBody(map[string]string{
+		"ruangan": "Ruang 1",
+	})), http.StatusOK)
+
+	channel := &pengingat.FakeChannel{}
+	scheduler := pengingat.New(ta.repos, time.Minute, channel)
+
+	// Belum waktunya: pengingat H-24 masuk antrean tetapi belum dikirim.
+	tick(t, scheduler, at(t, "2030-01-31T08:30"))
+	if got := len(channel.Messages()); got != 0 {
+		t.Fatalf("sent %d reminders before due", got)
+	}
+	tick(t, scheduler, at(t, "2030-01-31T09:00"))
+	tick(t, scheduler, at(t, "2030-01-31T09:01"))
+	sent := channel.Messages()
+	if len(sent) != 1 || sent[0].Pesan.Judul != "Pengingat janji temu PEDIKA" {
+		t.Fatalf("sent = %+v", sent)
+	}
+
+	// Antrean tersimpan di database, jadi scheduler baru (setelah restart)
+	// melanjutkan pengingat H-1 jam tanpa mengulang H-24.
+	restarted := pengingat.New(ta.repos, time.Minute, channel)
+	tick(t, restarted, at(t, "2030-02-01T08:00"))
+	sent = channel.Messages()
+	if len(sent) != 2 || sent[1].Pesan.Isi != "Janji temu konsultasi Anda dijadwalkan pada 01/02/2030 09:00 di Ruang 1." {
+		t.Fatalf("sent = %+v", sent)
+	}
+	want := []string{"janji_temu_24_jam:Terkirim", "janji_temu_1_jam:Terkirim"}
+	if got := ta.pengingatStatus(t, masyarakatEmail); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("reminders = %v, want %v", got, want)
+	}
+}
+
+func TestPengingatMengikutiPerubahanJanjiTemu(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+	id := createJanjiTemu(t, ta, warga)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody()), http.StatusOK)
+
+	channel := &pengingat.FakeChannel{}
+	scheduler := pengingat.New(ta.repos, time.Minute, channel)
+	tick(t, scheduler, at(t, "2030-01-31T08:30"))
+
+	// Dijadwalkan ulang sebelum pengingat terkirim: pengingat lama dilewati
+	// dan pengingat untuk waktu baru dibuat.
+	expectStatus(t, usulJadwal(ta, "admin", konselor, id, "2030-02-01T13:00:00", "2030-02-01T14:00:00"), http.StatusCreated)
+	expectStatus(t, ta.do(http.MethodPut, "/api/masyarakat/terima-jadwal-ulang-janjitemu/"+id, warga, noBody()), http.StatusOK)
+	tick(t, scheduler, at(t, "2030-01-31T09:00"))
+	if got := len(channel.Messages()); got != 0 {
+		t.Fatalf("sent %d reminders for the old time", got)
+	}
+	tick(t, scheduler, at(t, "2030-01-31T13:00"))
+	if got := len(channel.Messages()); got != 1 {
+		t.Fatalf("sent %d reminders for the new time", got)
+	}
+
+	// Janji temu yang dibatalkan tidak diingatkan lagi.
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/cancel-janjitemu/"+id, konselor, formBody(map[string]string{
+		"alasan_ditolak": "Konselor berhalangan",
+	}, nil)), http.StatusOK)
+	tick(t, scheduler, at(t, "2030-02-01T12:00"))
+	if got := len(channel.Messages()); got != 1 {
+		t.Fatalf("sent %d reminders after cancellation", got)
+	}
+	want := []string{"janji_temu_24_jam:Dilewati", "janji_temu_24_jam:Terkirim"}
+	if got := ta.pengingatStatus(t, masyarakatEmail); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("reminders = %v, want %v", got, want)
+	}
+}
+
+func TestPengingatEventDanChannelGagal(t *testing.T) {
+	ta := newTestApp(t)
+	admin := ta.login(adminEmail)
+	// Hanya masyarakat yang memilih menerima pengingat event.
+	warga := ta.login(masyarakatEmail)
+	resp := ta.do(http.MethodPut, "/api/masyarakat/edit-profile", warga, jsonBody(map[string]bool{"pengingat_event": true}))
+	expectStatus(t, resp, http.StatusOK)
+	if resp.data()["pengingat_event"] != true {
+		t.Fatalf("pengingat_event after opt-in = %v", resp.data()["pengingat_event"])
+	}
+	resp = ta.do(http.MethodPost, "/api/admin/create-event", admin, formBody(map[string]string{
+		"nama_event":          "Sosialisasi",
+		"deskripsi_event":     "Sosialisasi perlindungan anak",
+		"tanggal_pelaksanaan": "2030-03-10 09:00",
+	}, map[string]string{"thumbnail_event": "poster.png"}))
+	expectStatus(t, resp, http.StatusCreated)
+
+	rusak := &pengingat.FakeChannel{Err: errors.New("provider down")}
+	scheduler := pengingat.New(ta.repos, time.Minute, rusak, pengingat.EmailChannel{Sender: ta.mail}, pengingat.SMSChannel{Sender: ta.sms})
+	tick(t, scheduler, at(t, "2030-03-09T09:00"))
+
+	// Email tetap terkirim meskipun channel lain gagal, tetapi tidak lewat
+	// SMS dan tidak ke masyarakat yang tidak memilih menerimanya.
+	penerima := map[string]bool{}
+	for _, msg := range ta.mail.Messages() {
+		if msg.Subject == "Pengingat event: Sosialisasi" {
+			penerima[msg.To] = true
+		}
+	}
+	if !penerima[masyarakatEmail] || penerima[masyarakat2Email] || penerima[adminEmail] {
+		t.Fatalf("event reminder recipients = %v", penerima)
+	}
+	if got := len(ta.sms.Messages()); got != 0 {
+		t.Fatalf("sent %d event reminders by SMS", got)
+	}
+
+	// Jika semua channel gagal, pengingat dicoba ulang lalu ditandai gagal.
+	resp = ta.do(http.MethodPost, "/api/admin/create-event", admin, formBody(map[string]string{
+		"nama_event":          "Pelatihan",
+		"deskripsi_event":     "Pelatihan kader",
+		"tanggal_pelaksanaan": "2030-03-20 09:00",
+	}, map[string]string{"thumbnail_event": "poster.png"}))
+	expectStatus(t, resp, http.StatusCreated)
+	gagal := pengingat.New(ta.repos, time.Minute, rusak)
+	now := at(t, "2030-03-19T09:00")
+	for i := 0; i < 20; i++ {
+		tick(t, gagal, now.Add(time.Duration(i)*5*time.Minute))
+	}
+	user, _ := ta.repos.Users.FindByEmail(masyarakatEmail)
+	list, _ := ta.repos.Pengingat.List(user.ID)
+	last := list[len(list)-1]
+	if last.Status != models.PengingatGagal || last.Percobaan != 5 || last.GalatTerakhir != "fake: provider down" {
+		t.Fatalf("failed reminder = %+v", last)
+	}
+}
@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPenilaianLayananSekaliSetelahSelesai(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	konselor := ta.login(konselorEmail)
+	admin := ta.login(adminEmail)
+
+	nilai := func(path, token, rating string) response {
+		return ta.do(http.MethodPost, "/api/masyarakat/"+path, token, jsonBody(map[string]string{
+			"rating":   rating,
+			"komentar": "Konselor sangat membantu",
+		}))
+	}
+
+	id := createJanjiTemu(t, ta, warga)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody()), http.StatusOK)
+	expectError(t, nilai("penilaian-janjitemu/"+id, warga, "5"), http.StatusConflict, "CONFLICT")
+
+	ta.mulaiJanjiTemu(t, id)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/selesai-janjitemu/"+id, konselor, jsonBody(map[string]string{
+		"kehadiran":      "hadir",
+		"kategori_hasil": "tertangani",
+	})), http.StatusOK)
+
+	expectStatus(t, ta.do(http.MethodGet, "/api/masyarakat/penilaian-janjitemu/"+id, warga, noBody()), http.StatusNotFound)
+	expectFieldErrors(t, nilai("penilaian-janjitemu/"+id, warga, "6"), map[string]string{"rating": "oneof"})
+	expectStatus(t, nilai("penilaian-janjitemu/"+id, warga2, "1"), http.StatusNotFound)
+	expectStatus(t, nilai("penilaian-janjitemu/"+id, warga, "5"), http.StatusCreated)
+	expectError(t, nilai("penilaian-janjitemu/"+id, warga, "4"), http.StatusConflict, "CONFLICT")
+
+	resp := ta.do(http.MethodGet, "/api/masyarakat/penilaian-janjitemu/"+id, warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["rating"]); got != "5" {
+		t.Fatalf("rating = %q", got)
+	}
+
+	noRegistrasi := createLaporan(t, ta, warga)
+	expectError(t, nilai("penilaian-laporan/"+noRegistrasi, warga, "3"), http.StatusConflict, "CONFLICT")
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/laporan-selesai/"+noRegistrasi, admin, noBody()), http.StatusOK)
+	expectStatus(t, nilai("penilaian-laporan/"+noRegistrasi, warga2, "3"), http.StatusNotFound)
+	expectStatus(t, nilai("penilaian-laporan/"+noRegistrasi, warga, "3"), http.StatusCreated)
+	expectError(t, nilai("penilaian-laporan/"+noRegistrasi, warga, "3"), http.StatusConflict, "CONFLICT")
+}
+
+func TestStatistikPenilaian(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	konselor := ta.login(konselorEmail)
+	admin := ta.login(adminEmail)
+
+	id := createJanjiTemu(t, ta, warga)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, noBody()), http.StatusOK)
+	ta.mulaiJanjiTemu(t, id)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/selesai-janjitemu/"+id, konselor, jsonBody(map[string]string{
+		"kehadiran":      "hadir",
+		"kategori_hasil": "tertangani",
+	})), http.StatusOK)
+	expectStatus(t, ta.do(http.MethodPost, "/api/masyarakat/penilaian-janjitemu/"+id, warga, jsonBody(map[string]string{
+		"rating": "5",
+	})), http.StatusCreated)
+
+	noRegistrasi := createLaporan(t, ta, warga)
+	expectStatus(t, ta.do(http.MethodPut, "/api/admin/laporan-selesai/"+noRegistrasi, admin, noBody()), http.StatusOK)
+	expectStatus(t, ta.do(http.MethodPost, "/api/masyarakat/penilaian-laporan/"+noRegistrasi, warga, jsonBody(map[string]string{
+		"rating": "2",
+	})), http.StatusCreated)
+
+	expectError(t, ta.do(http.MethodGet, "/api/admin/statistik-penilaian", konselor, noBody()), http.StatusForbidden, "FORBIDDEN")
+	expectFieldErrors(t, ta.do(http.MethodGet, "/api/admin/statistik-penilaian?dari=kemarin", admin, noBody()),
+		map[string]string{"dari": "date"})
+
+	resp := ta.do(http.MethodGet, "/api/admin/statistik-penilaian", admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	stats := resp.data()
+	keseluruhan, _ := stats["keseluruhan"].(map[string]interface{})
+	if str(keseluruhan["jumlah"]) != "2" || keseluruhan["rata_rata"] != 3.5 || str(keseluruhan["persen_puas"]) != "50" {
+		t.Fatalf("keseluruhan = %v", keseluruhan)
+	}
+
+	perKonselor, _ := stats["per_konselor"].([]interface{})
+	if len(perKonselor) != 1 {
+		t.Fatalf("per_konselor = %v", perKonselor)
+	}
+	k, _ := perKonselor[0].(map[string]interface{})
+	if str(k["konselor_id"]) != ta.userID(konselorEmail) || str(k["rata_rata"]) != "5" || str(k["nama_konselor"]) == "" {
+		t.Fatalf("per_konselor[0] = %v", k)
+	}
+
+	perKategori, _ := stats["per_kategori"].([]interface{})
+	if len(perKategori) != 1 {
+		t.Fatalf("per_kategori = %v", perKategori)
+	}
+	kategori, _ := perKategori[0].(map[string]interface{})
+	if str(kategori["kategori_kekerasan_id"]) != "1" || str(kategori["rata_rata"]) != "2" {
+		t.Fatalf("per_kategori[0] = %v", kategori)
+	}
+
+	perBulan, _ := stats["per_bulan"].([]interface{})
+	if len(perBulan) != 1 {
+		t.Fatalf("per_bulan = %v", perBulan)
+	}
+	bulan, _ := perBulan[0].(map[string]interface{})
+	if str(bulan["bulan"]) != time.Now().UTC().Format("2006-01") || str(bulan["jumlah"]) != "2" {
+		t.Fatalf("per_bulan[0] = %v", bulan)
+	}
+
+	resp = ta.do(http.MethodGet, "/api/admin/statistik-penilaian?dari=2999-01-01", admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	keseluruhan, _ = resp.data()["keseluruhan"].(map[string]interface{})
+	if str(keseluruhan["jumlah"]) != "0" {
+		t.Fatalf("filtered keseluruhan = %v", keseluruhan)
+	}
+}
@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"backend-pedika-fiber/pengingat"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createJanjiTemuMode(t *testing.T, ta *testApp, token, mulai, selesai, mode string) string {
+	t.Helper()
+
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", token, formBody(map[string]string{
+		"waktu_dimulai":        mulai,
+		"waktu_selesai":        selesai,
+		"keperluan_konsultasi": "Konsultasi daring",
+		"mode_konsultasi":      mode,
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+	if got := str(resp.data()["mode_konsultasi"]); got != mode {
+		t.Fatalf("mode_konsultasi after create = %q, want %q", got, mode)
+	}
+	return str(resp.data()["id"])
+}
+
+func TestTautanPertemuanHanyaUntukPeserta(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	warga2 := ta.login(masyarakat2Email)
+	konselor := ta.login(konselorEmail)
+	konselor2 := ta.login(konselor2Email)
+	admin := ta.login(adminEmail)
+
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, formBody(map[string]string{
+		"waktu_dimulai":        "2030-02-01T09:00:00",
+		"waktu_selesai":        "2030-02-01T10:00:00",
+		"keperluan_konsultasi": "Konsultasi daring",
+		"mode_konsultasi":      "zoom",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{"mode_konsultasi": "oneof"})
+
+	id := createJanjiTemuMode(t, ta, warga, "2030-02-01T09:00:00", "2030-02-01T10:00:00", "video")
+	expectError(t, ta.do(http.MethodGet, "/api/masyarakat/tautan-janjitemu/"+id, warga, noBody()), http.StatusConflict, "CONFLICT")
+
+	resp = ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+id, konselor, jsonBody(map[string]string{
+		"ruangan": "Ruang 1",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	if _, ok := resp.data()["tautan_pertemuan"]; ok {
+		t.Fatalf("approve response leaks the meeting link: %v", resp.data())
+	}
+	if got := str(resp.data()["ruangan"]); got != "" {
+		t.Fatalf("video consultation kept ruangan %q", got)
+	}
+	if calls := ta.meeting.Calls(); len(calls) != 1 {
+		t.Fatalf("provider calls = %v", calls)
+	}
+
+	link := "https://meet.test/janji-temu-" + id
+	resp = ta.do(http.MethodGet, "/api/masyarakat/tautan-janjitemu/"+id, warga, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["tautan_pertemuan"]); got != link {
+		t.Fatalf("tautan for owner = %q, want %q", got, link)
+	}
+	resp = ta.do(http.MethodGet, "/api/admin/tautan-janjitemu/"+id, konselor, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["tautan_pertemuan"]); got != link {
+		t.Fatalf("tautan for konselor = %q, want %q", got, link)
+	}
+
+	// Bukan peserta: masyarakat lain, konselor lain, dan admin.
+	expectStatus(t, ta.do(http.MethodGet, "/api/masyarakat/tautan-janjitemu/"+id, warga2, noBody()), http.StatusNotFound)
+	expectError(t, ta.do(http.MethodGet, "/api/admin/tautan-janjitemu/"+id, konselor2, noBody()), http.StatusForbidden, "FORBIDDEN")
+	expectError(t, ta.do(http.MethodGet, "/api/admin/tautan-janjitemu/"+id, admin, noBody()), http.StatusForbidden, "FORBIDDEN")
+
+	resp = ta.do(http.MethodGet, "/api/admin/detail-janjitemu/"+id, admin, noBody())
+	expectStatus(t, resp, http.StatusOK)
+	if _, ok := resp.data()["tautan_pertemuan"]; ok {
+		t.Fatalf("detail leaks the meeting link: %v", resp.data())
+	}
+
+	// Pengingat dikirim ke pemilik janji temu dan menyertakan tautannya.
+	channel := &pengingat.FakeChannel{}
+	scheduler := pengingat.New(ta.repos, time.Minute, channel)
+	tick(t, scheduler, at(t, "2030-02-01T08:00"))
+	sent := channel.Messages()
+	if len(sent) == 0 || !strings.Contains(sent[len(sent)-1].Pesan.Isi, link) {
+		t.Fatalf("sent = %+v", sent)
+	}
+
+	resp = ta.do(http.MethodPut, "/api/admin/cancel-janjitemu/"+id, admin, formBody(map[string]string{
+		"alasan_ditolak": "Konselor berhalangan",
+	}, nil))
+	expectStatus(t, resp, http.StatusOK)
+	expectError(t, ta.do(http.MethodGet, "/api/masyarakat/tautan-janjitemu/"+id, warga, noBody()), http.StatusConflict, "CONFLICT")
+}
+
+func TestModeKonsultasiTeleponDanProviderGagal(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	admin := ta.login(adminEmail)
+	konselorID := ta.userID(konselorEmail)
+
+	telepon := createJanjiTemuMode(t, ta, warga, "2030-02-01T09:00:00", "2030-02-01T10:00:00", "telepon")
+	resp := ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+telepon, admin, jsonBody(map[string]string{
+		"konselor_id": konselorID,
+		"ruangan":     "Ruang 1",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["ruangan"]); got != "" {
+		t.Fatalf("phone consultation kept ruangan %q", got)
+	}
+	if calls := ta.meeting.Calls(); len(calls) != 0 {
+		t.Fatalf("provider called for phone consultation: %v", calls)
+	}
+	expectError(t, ta.do(http.MethodGet, "/api/masyarakat/tautan-janjitemu/"+telepon, warga, noBody()), http.StatusConflict, "CONFLICT")
+
+	// Admin bisa mengubah mode saat menyetujui; jika provider gagal, janji
+	// temu tetap menunggu persetujuan.
+	video := createJanjiTemuMode(t, ta, warga, "2030-02-02T09:00:00", "2030-02-02T10:00:00", "tatap_muka")
+	ta.meeting.Err = errors.New("provider down")
+	approve := jsonBody(map[string]string{
+		"konselor_id":     konselorID,
+		"mode_konsultasi": "video",
+	})
+	expectError(t, ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+video, admin, approve), http.StatusInternalServerError, "MEETING_LINK_FAILED")
+	if got := janjiTemuStatus(t, ta, warga, video); got != "Belum disetujui" {
+		t.Fatalf("status after failed approve = %q", got)
+	}
+
+	ta.meeting.Err = nil
+	resp = ta.do(http.MethodPut, "/api/admin/approve-janjitemu/"+video, admin, jsonBody(map[string]string{
+		"konselor_id":     konselorID,
+		"mode_konsultasi": "video",
+	}))
+	expectStatus(t, resp, http.StatusOK)
+	if got := str(resp.data()["mode_konsultasi"]); got != "video" {
+		t.Fatalf("mode after approve = %q", got)
+	}
+	expectStatus(t, ta.do(http.MethodGet, "/api/masyarakat/tautan-janjitemu/"+video, warga, noBody()), http.StatusOK)
+}
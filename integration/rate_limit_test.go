@@ -0,0 +1,22 @@
+package integration
+
+import (
+	"backend-pedika-fiber/config"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimitKeysOnForwardedClientIP(t *testing.T) {
+	ta := newTestAppWith(t, func(cfg *config.Config) {
+		cfg.RateLimit.Public = config.Limit{Max: 2, Window: time.Minute}
+	})
+
+	// Kedua client datang lewat load balancer yang sama, tetapi masing-masing
+	// punya bucket sendiri.
+	for i := 0; i < 2; i++ {
+		expectStatus(t, ta.do(http.MethodGet, "/api/publik-content", "", noBody().fromIP("203.0.113.10")), http.StatusOK)
+	}
+	expectError(t, ta.do(http.MethodGet, "/api/publik-content", "", noBody().fromIP("203.0.113.10")), http.StatusTooManyRequests, "RATE_LIMITED")
+	expectStatus(t, ta.do(http.MethodGet, "/api/publik-content", "", noBody().fromIP("203.0.113.20")), http.StatusOK)
+}
@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"backend-pedika-fiber/repository/repotest"
+	"backend-pedika-fiber/seed"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSeedRejectsUnknownRole(t *testing.T) {
+	db := repotest.Open(t)
+
+	err := seed.Apply(db, seed.Data{Users: []seed.UserSeed{{
+		FullName:    "Salah Ketik",
+		Username:    "salahketik",
+		Email:       "salah@test.local",
+		PhoneNumber: "081300000001",
+		Role:        "konselr",
+	}}}, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "unknown role") {
+		t.Fatalf("expected unknown role error, got %v", err)
+	}
+}
+
+func TestSeedStaffPasswordsOnlyInDevelopment(t *testing.T) {
+	db := repotest.Open(t)
+
+	if err := seed.RunCommand(db, []string{"demo"}, io.Discard, false); err == nil {
+		t.Fatal("demo seed with staff passwords must be refused outside development")
+	}
+	if err := seed.RunCommand(db, []string{"default", "demo"}, io.Discard, true); err != nil {
+		t.Fatalf("demo seed in development: %v", err)
+	}
+
+	// Akun masyarakat dengan password tertulis tetap boleh di mana saja.
+	file := filepath.Join(t.TempDir(), "warga.json")
+	raw := `{"users": [{"full_name": "Warga", "username": "warga", "email": "warga@test.local", "phone_number": "081300000002", "password": "rahasia123"}]}`
+	if err := os.WriteFile(file, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := seed.RunCommand(db, []string{file}, io.Discard, false); err != nil {
+		t.Fatalf("masyarakat seed outside development: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestJanjiTemuEndMustBeAfterStart(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, formBody(map[string]string{
+		"waktu_dimulai":        "2030-02-01T10:00:00",
+		"waktu_selesai":        "2030-02-01T09:00:00",
+		"keperluan_konsultasi": "Konsultasi pendampingan",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{"waktu_selesai": "after"})
+
+	resp = ta.do(http.MethodPost, "/api/masyarakat/create-janjitemu", warga, formBody(map[string]string{
+		"waktu_dimulai":        "2020-02-01T09:00:00",
+		"waktu_selesai":        "2020-02-01T10:00:00",
+		"keperluan_konsultasi": "Konsultasi pendampingan",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{"waktu_dimulai": "future"})
+
+	id := createJanjiTemu(t, ta, warga)
+	resp = ta.do(http.MethodPut, "/api/masyarakat/edit-janjitemu/"+id, warga, jsonBody(map[string]string{
+		"waktu_dimulai": "2030-02-02T10:00:00Z",
+		"waktu_selesai": "2030-02-02T10:00:00Z",
+	}))
+	expectFieldErrors(t, resp, map[string]string{"waktu_selesai": "after"})
+}
+
+func TestKorbanDanPelakuFieldRules(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+	admin := ta.login(adminEmail)
+	noRegistrasi := createLaporan(t, ta, warga)
+
+	// Semua kesalahan dilaporkan sekaligus, bukan hanya yang pertama.
+	resp := ta.do(http.MethodPost, "/api/masyarakat/create-korban-kekerasan", warga, formBody(map[string]string{
+		"no_registrasi":     noRegistrasi,
+		"nik_korban":        "12345",
+		"usia_korban":       "tujuh belas",
+		"jenis_kelamin":     "L",
+		"no_telepon":        "12345",
+		"status_perkawinan": "Lajang",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{
+		"nama_korban":       "required",
+		"nik_korban":        "nik",
+		"usia_korban":       "int",
+		"jenis_kelamin":     "oneof",
+		"no_telepon":        "phone",
+		"status_perkawinan": "oneof",
+	})
+
+	// Sebelumnya usia yang tidak valid diabaikan begitu saja.
+	resp = ta.do(http.MethodPost, "/api/admin/create-pelaku-kekerasan", admin, formBody(map[string]string{
+		"no_registrasi": noRegistrasi,
+		"nama_pelaku":   "Pelaku",
+		"usia_pelaku":   "-3",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{"usia_pelaku": "int"})
+
+	resp = ta.do(http.MethodPost, "/api/admin/create-pelaku-kekerasan", admin, formBody(map[string]string{
+		"no_registrasi": "000-TIDAK-ADA",
+		"nama_pelaku":   "Pelaku",
+	}, nil))
+	expectError(t, resp, http.StatusNotFound, "NOT_FOUND")
+
+	resp = ta.do(http.MethodPost, "/api/admin/create-pelaku-kekerasan", admin, formBody(map[string]string{
+		"no_registrasi": noRegistrasi,
+		"nik_pelaku":    "+628123456789012",
+		"no_telepon":    "+6281234567890",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{"nik_pelaku": "nik"})
+
+	resp = ta.do(http.MethodPost, "/api/admin/create-pelaku-kekerasan", admin, formBody(map[string]string{
+		"no_registrasi": noRegistrasi,
+		"no_telepon":    "+6281234567890",
+	}, nil))
+	expectStatus(t, resp, http.StatusCreated)
+}
+
+func TestLaporanFieldRules(t *testing.T) {
+	ta := newTestApp(t)
+	warga := ta.login(masyarakatEmail)
+
+	resp := ta.do(http.MethodPost, "/api/masyarakat/buat-laporan", warga, formBody(map[string]string{
+		"kategori_kekerasan_id": "satu",
+		"tanggal_kejadian":      "2999-01-01T10:00:00",
+		"alamat_tkp":            "Balige",
+	}, nil))
+	expectFieldErrors(t, resp, map[string]string{
+		"kategori_kekerasan_id": "digits",
+		"tanggal_kejadian":      "past",
+		"kategori_lokasi_kasus": "required",
+		"kronologis_kasus":      "required",
+	})
+}
+
+func TestAccountFieldRules(t *testing.T) {
+	ta := newTestApp(t)
+
+	resp := ta.do(http.MethodPost, "/api/user/register", "", jsonBody(map[string]string{
+		"full_name":    "Warga Baru",
+		"email":        "bukan-email",
+		"phone_number": "021-555",
+		"password":     "pendek",
+	}))
+	expectFieldErrors(t, resp, map[string]string{
+		"email":        "email",
+		"phone_number": "phone",
+		"password":     "min",
+	})
+
+	resp = ta.do(http.MethodPost, "/api/user/reset-password", "", jsonBody(map[string]string{
+		"token":            "abc",
+		"new_password":     "rahasia123",
+		"confirm_password": "rahasia124",
+	}))
+	expectFieldErrors(t, resp, map[string]string{"confirm_password": "eqfield"})
+
+	resp = ta.do(http.MethodPost, "/api/user/verify-otp", "", jsonBody(map[string]string{
+		"channel": "whatsapp",
+		"target":  "warga@test.local",
+		"code":    "12ab",
+	}))
+	expectFieldErrors(t, resp, map[string]string{"channel": "oneof", "code": "len"})
+}
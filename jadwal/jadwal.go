@@ -0,0 +1,127 @@
+// Package jadwal menghitung slot janji temu yang masih kosong dari jadwal
+// mingguan konselor, pengecualian jadwal, dan janji temu yang sudah dipesan.
+//
+// Semua waktu diperlakukan sebagai waktu lokal tanpa zona, sama seperti
+// WaktuDimulai janji temu yang dikirim client.
+package jadwal
+
+import (
+	"backend-pedika-fiber/models"
+	"sort"
+	"time"
+)
+
+const jamLayout = "15:04"
+
+// Slot adalah satu rentang waktu konselor yang bisa dipesan.
+type Slot struct {
+	KonselorID   uint      `json:"konselor_id"`
+	NamaKonselor string    `json:"nama_konselor"`
+	WaktuDimulai time.Time `json:"waktu_dimulai"`
+	WaktuSelesai time.Time `json:"waktu_selesai"`
+}
+
+// SlotKosong mengembalikan slot dari jadwal yang dimulai dalam rentang
+// [dari, sampai), tidak tertutup pengecualian, dan tidak bertabrakan dengan
+// janji temu aktif milik konselor yang sama. Hasilnya diurutkan berdasarkan
+// waktu mulai lalu konselor.
+func SlotKosong(jadwal []models.JadwalKonselor, pengecualian []models.PengecualianJadwal, terpakai []models.JanjiTemu, dari, sampai time.Time) []Slot {
+	var slots []Slot
+	for hari := tanggal(dari); hari.Before(sampai); hari = hari.AddDate(0, 0, 1) {
+		for _, j := range jadwal {
+			if time.Weekday(j.Hari) != hari.Weekday() || j.DurasiMenit <= 0 {
+				continue
+			}
+			mulai, errMulai := jam(hari, j.JamMulai)
+			selesai, errSelesai := jam(hari, j.JamSelesai)
+			if errMulai != nil || errSelesai != nil {
+				continue
+			}
+			durasi := time.Duration(j.DurasiMenit) * time.Minute
+			for s := mulai; !s.Add(durasi).After(selesai); s = s.Add(durasi) {
+				slot := Slot{
+					KonselorID:   j.KonselorID,
+					NamaKonselor: j.Konselor.FullName,
+					WaktuDimulai: s,
+					WaktuSelesai: s.Add(durasi),
+				}
+				if s.Before(dari) || !s.Before(sampai) {
+					continue
+				}
+				if ditutup(slot, pengecualian) || dipesan(slot, terpakai) {
+					continue
+				}
+				slots = append(slots, slot)
+			}
+		}
+	}
+	sort.SliceStable(slots, func(a, b int) bool {
+		if !slots[a].WaktuDimulai.Equal(slots[b].WaktuDimulai) {
+			return slots[a].WaktuDimulai.Before(slots[b].WaktuDimulai)
+		}
+		return slots[a].KonselorID < slots[b].KonselorID
+	})
+	return slots
+}
+
+// Cari mengembalikan slot milik konselor yang dimulai tepat pada mulai.
+func Cari(slots []Slot, konselorID uint, mulai time.Time) (Slot, bool) {
+	for _, slot := range slots {
+		if slot.KonselorID == konselorID && slot.WaktuDimulai.Equal(mulai) {
+			return slot, true
+		}
+	}
+	return Slot{}, false
+}
+
+// Bertabrakan bernilai true jika dua rentang waktu saling beririsan. Rentang
+// yang hanya bersentuhan di ujungnya tidak dianggap bertabrakan.
+func Bertabrakan(mulaiA, selesaiA, mulaiB, selesaiB time.Time) bool {
+	return mulaiA.Before(selesaiB) && mulaiB.Before(selesaiA)
+}
+
+func ditutup(slot Slot, pengecualian []models.PengecualianJadwal) bool {
+	hari := tanggal(slot.WaktuDimulai)
+	for _, p := range pengecualian {
+		if p.KonselorID != slot.KonselorID || !tanggal(p.Tanggal).Equal(hari) {
+			continue
+		}
+		if p.JamMulai == "" || p.JamSelesai == "" {
+			return true
+		}
+		mulai, errMulai := jam(hari, p.JamMulai)
+		selesai, errSelesai := jam(hari, p.JamSelesai)
+		if errMulai != nil || errSelesai != nil {
+			// Pengecualian yang tidak terbaca lebih aman dianggap seharian.
+			return true
+		}
+		if Bertabrakan(slot.WaktuDimulai, slot.WaktuSelesai, mulai, selesai) {
+			return true
+		}
+	}
+	return false
+}
+
+func dipesan(slot Slot, terpakai []models.JanjiTemu) bool {
+	for _, j := range terpakai {
+		if j.KonselorID == nil || *j.KonselorID != slot.KonselorID || !j.Aktif() {
+			continue
+		}
+		if Bertabrakan(slot.WaktuDimulai, slot.WaktuSelesai, j.WaktuDimulai, j.WaktuSelesai) {
+			return true
+		}
+	}
+	return false
+}
+
+func tanggal(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func jam(hari time.Time, hhmm string) (time.Time, error) {
+	t, err := time.Parse(jamLayout, hhmm)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return hari.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), nil
+}
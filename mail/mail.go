@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"backend-pedika-fiber/config"
+	"fmt"
+	"log"
+	"net/smtp"
+	"sync"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender adalah abstraksi pengiriman email supaya handler tidak bergantung
+// langsung pada SMTP dan bisa diganti FakeSender saat testing.
+type Sender interface {
+	Send(msg Message) error
+}
+
+// NewSender memilih implementasi Sender sesuai MAIL_DRIVER. Config sudah
+// memastikan driver "log" hanya dipakai di development.
+func NewSender(cfg config.MailConfig) Sender {
+	if cfg.Driver == "smtp" {
+		return SMTPSender{cfg: cfg}
+	}
+	return LogSender{}
+}
+
+type SMTPSender struct {
+	cfg config.MailConfig
+}
+
+func (s SMTPSender) Send(msg Message) error {
+	from := s.cfg.Sender
+	body := []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", from, msg.To, msg.Subject, msg.Body))
+	auth := smtp.PlainAuth("", from, s.cfg.Password, s.cfg.SMTPHost)
+	return smtp.SendMail(s.cfg.SMTPHost+":"+s.cfg.SMTPPort, auth, from, []string{msg.To}, body)
+}
+
+// LogSender hanya mencatat penerima dan subjek email, tanpa isi, karena isi
+// email bisa berupa link reset password atau kode OTP. Hanya boleh dipakai di
+// development.
+type LogSender struct{}
+
+func (LogSender) Send(msg Message) error {
+	log.Printf("Email to %s: %s (body not logged)\n", msg.To, msg.Subject)
+	return nil
+}
+
+// FakeSender menyimpan email di memori, dipakai untuk development dan test.
+type FakeSender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (f *FakeSender) Send(msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, msg)
+	return nil
+}
+
+func (f *FakeSender) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.messages...)
+}
@@ -1,20 +1,86 @@
 package main
 
 import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/config"
 	"backend-pedika-fiber/database"
+	"backend-pedika-fiber/handlers"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/mail"
 	"backend-pedika-fiber/migration"
+	"backend-pedika-fiber/pengingat"
+	"backend-pedika-fiber/pertemuan"
+	"backend-pedika-fiber/ratelimit"
+	"backend-pedika-fiber/repository"
 	"backend-pedika-fiber/routes"
-
-	"github.com/gofiber/fiber/v2"
+	"backend-pedika-fiber/seed"
+	"backend-pedika-fiber/sms"
+	"context"
+	"log"
+	"os"
 )
 
 func main() {
-	app := fiber.New()
-	database.GetDBInstance()
-	migration.RunMigration()
-	routes.SetAuthRoutes(app)
-	routes.SetAdminRoutes(app)
-	routes.SetMasyarakatRoutes(app)
-	routes.RoutesWithOutLogin(app)
-	app.Listen(":8080")
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// go run . migrate up|down [steps]|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migration.RunCommand(db, os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// go run . seed [default|demo|file.json ...]
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := migration.Up(db); err != nil {
+			log.Fatal(err)
+		}
+		if err := seed.RunCommand(db, os.Args[2:], os.Stdout, cfg.IsDevelopment()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Server tidak dijalankan dengan skema yang setengah jadi.
+	if err := migration.Up(db); err != nil {
+		log.Fatal(err)
+	}
+
+	uploader, err := helper.NewCloudinaryUploader(cfg.Storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+	deps := handlers.Deps{
+		JWT:       auth.NewJWT(cfg.JWT),
+		Mail:      mail.NewSender(cfg.Mail),
+		SMS:       sms.NewSender(cfg.SMS),
+		Uploader:  uploader,
+		Pertemuan: pertemuan.StaticProvider{Template: cfg.Pertemuan.Template},
+	}
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == "mysql" {
+		store = ratelimit.NewGormStore(db)
+	}
+
+	repos := repository.NewGormRepositories(db)
+	if cfg.Pengingat.Enabled {
+		channels, err := pengingat.NewChannels(cfg.Pengingat.Channels, deps.Mail, deps.SMS)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go pengingat.New(repos, cfg.Pengingat.Interval, channels...).Run(context.Background())
+	}
+
+	app := routes.NewApp(cfg)
+	routes.Setup(app, cfg, repos, deps, store)
+	log.Fatal(app.Listen(":" + cfg.Port))
 }
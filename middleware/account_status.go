@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Auth berisi middleware yang perlu membaca data user. Dibuat sekali lewat
+// NewAuth dengan repository user dan pengaturan JWT yang dipakai aplikasi.
+type Auth struct {
+	users repository.UserRepository
+	jwt   auth.JWT
+}
+
+func NewAuth(users repository.UserRepository, jwt auth.JWT) *Auth {
+	return &Auth{users: users, jwt: jwt}
+}
+
+// loadActiveUser mengambil user pemilik token dari database supaya akun yang
+// sudah dihapus atau disuspend langsung ditolak, tanpa menunggu token expired.
+// Preferensi bahasa user langsung dipakai untuk sisa request, termasuk pesan
+// akun dinonaktifkan.
+func (a *Auth) loadActiveUser(c *fiber.Ctx, claims jwt.MapClaims) (models.User, error) {
+	var user models.User
+	// Token dengan claim purpose (misalnya challenge 2FA) bukan token login.
+	if _, ok := claims["purpose"]; ok {
+		return user, helper.Unauthorized("auth.invalid_token")
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return user, helper.Unauthorized("auth.invalid_token")
+	}
+	user, err := a.users.FindByID(uint(userID))
+	if err != nil {
+		return user, helper.Unauthorized("auth.account_not_found")
+	}
+	i18n.SetLocale(c, user.Locale)
+	if user.IsSuspended {
+		return user, helper.NewError(fiber.StatusForbidden, helper.CodeAccountSuspended, "auth.account_suspended")
+	}
+	return user, nil
+}
@@ -1,58 +1,48 @@
 package middleware
 
 import (
+	"backend-pedika-fiber/auth"
 	"backend-pedika-fiber/helper"
-	"os"
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gofiber/fiber/v2"
 )
 
-func AdminMiddleware(c *fiber.Ctx) error {
+func (a *Auth) AdminMiddleware(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
-		response := helper.ResponseWithOutData{
-			Code:    fiber.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized: Missing token",
-		}
-		return c.Status(fiber.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("auth.missing_token")
 	}
 	splitToken := strings.Split(authHeader, "Bearer ")
 	if len(splitToken) != 2 {
-		response := helper.ResponseWithOutData{
-			Code:    fiber.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized: Invalid token format",
-		}
-		return c.Status(fiber.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("auth.invalid_token_format")
 	}
 
 	tokenString := splitToken[1]
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+		return a.jwt.Secret(), nil
 	})
 
 	if err != nil || !token.Valid {
-		response := helper.ResponseWithOutData{
-			Code:    fiber.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized: Invalid token",
-		}
-		return c.Status(fiber.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("auth.invalid_token")
 	}
 
 	claims, _ := token.Claims.(jwt.MapClaims)
-	role := claims["role"].(string)
-	if role != "admin" {
-		response := helper.ResponseWithOutData{
-			Code:    fiber.StatusForbidden,
-			Status:  "error",
-			Message: "Forbidden: Access Not Allowed",
-		}
-		return c.Status(fiber.StatusForbidden).JSON(response)
+	user, err := a.loadActiveUser(c, claims)
+	if err != nil {
+		return err
 	}
+
+	role := user.Role
+	if !auth.IsStaffRole(role) {
+		return helper.Forbidden("auth.staff_only")
+	}
+	if a.jwt.RequiresTwoFactor(role) && !user.TwoFactorEnabled && !strings.HasPrefix(c.Path(), "/api/admin/2fa") {
+		return helper.NewError(fiber.StatusForbidden, helper.CodeTwoFactorRequired, "auth.two_factor_setup_required")
+	}
+	c.Locals("role", role)
+	c.Locals("user", user)
 	return c.Next()
 }
@@ -2,54 +2,39 @@ package middleware
 
 import (
 	"backend-pedika-fiber/helper"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gofiber/fiber/v2"
-	"os"
 	"strings"
-	"github.com/dgrijalva/jwt-go"
 )
 
-func MasyarakatMiddleware(c *fiber.Ctx) error {
+func (a *Auth) MasyarakatMiddleware(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
-		response := helper.ResponseWithOutData{
-			Code:    fiber.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized: Missing token",
-		}
-		return c.Status(fiber.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("auth.missing_token")
 	}
 
 	splitToken := strings.Split(authHeader, "Bearer ")
 	if len(splitToken) != 2 {
-		response := helper.ResponseWithOutData{
-			Code:    fiber.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized: Invalid token format",
-		}
-		return c.Status(fiber.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("auth.invalid_token_format")
 	}
 	tokenString := splitToken[1]
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+		return a.jwt.Secret(), nil
 	})
 	if err != nil || !token.Valid {
-		response := helper.ResponseWithOutData{
-			Code:    fiber.StatusUnauthorized,
-			Status:  "error",
-			Message: "Unauthorized: Invalid token",
-		}
-		return c.Status(fiber.StatusUnauthorized).JSON(response)
+		return helper.Unauthorized("auth.invalid_token")
 	}
 	claims, _ := token.Claims.(jwt.MapClaims)
-	role := claims["role"].(string)
+	user, err := a.loadActiveUser(c, claims)
+	if err != nil {
+		return err
+	}
+
+	role := user.Role
 	if role != "masyarakat" {
-		response := helper.ResponseWithOutData{
-			Code:    fiber.StatusForbidden,
-			Status:  "error",
-			Message: "Forbidden: Only masyarakat can access this endpoint",
-		}
-		return c.Status(fiber.StatusForbidden).JSON(response)
+		return helper.Forbidden("auth.masyarakat_only")
 	}
+	c.Locals("user", user)
 	return c.Next()
 }
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/helper"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission harus dipasang setelah AdminMiddleware, karena role
+// diambil dari c.Locals("role") yang diisi oleh AdminMiddleware.
+func RequirePermission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("role").(string)
+		if !auth.HasPermission(role, permission) {
+			return helper.Forbidden("auth.permission_denied")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/ratelimit"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimiter membuat middleware pembatas request yang menghitung di store
+// yang sama. Dibuat sekali lewat NewRateLimiter saat aplikasi start.
+type RateLimiter struct {
+	store ratelimit.Store
+}
+
+func NewRateLimiter(store ratelimit.Store) *RateLimiter {
+	return &RateLimiter{store: store}
+}
+
+// RateLimit membatasi jumlah request per IP atau per user sesuai rule.
+// Untuk rule KeyByUser, middleware ini harus dipasang setelah middleware auth.
+func (l *RateLimiter) RateLimit(rule ratelimit.Rule) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if rule.OnlyWrites {
+			switch c.Method() {
+			case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+				return c.Next()
+			}
+		}
+
+		key := rule.Name + ":ip:" + c.IP()
+		if rule.KeyBy == ratelimit.KeyByUser {
+			if user, ok := c.Locals("user").(models.User); ok {
+				key = fmt.Sprintf("%s:user:%d", rule.Name, user.ID)
+			}
+		}
+
+		hits, resetAt, err := l.store.Increment(key, rule.Window)
+		if err != nil {
+			// Gagal menghitung tidak boleh membuat seluruh API ikut mati.
+			log.Println("Error incrementing rate limit counter:", err)
+			return c.Next()
+		}
+
+		remaining := rule.Max - hits
+		if remaining < 0 {
+			remaining = 0
+		}
+		retryAfter := int(math.Ceil(time.Until(resetAt).Seconds()))
+		c.Set("X-RateLimit-Limit", strconv.Itoa(rule.Max))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Set("X-RateLimit-Reset", strconv.Itoa(retryAfter))
+
+		if hits > rule.Max {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return helper.TooManyRequests("auth.rate_limited").WithArgs(retryAfter)
+		}
+		return c.Next()
+	}
+}
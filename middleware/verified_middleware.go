@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"backend-pedika-fiber/helper"
+	"backend-pedika-fiber/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireVerifiedAccount dipasang setelah MasyarakatMiddleware untuk endpoint
+// yang hanya boleh dipakai setelah email dan nomor telepon diverifikasi OTP.
+func RequireVerifiedAccount(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(models.User)
+	if !ok || !user.IsVerified() {
+		return helper.NewError(fiber.StatusForbidden, helper.CodeAccountUnverified, "auth.unverified")
+	}
+	return c.Next()
+}
@@ -0,0 +1,293 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Skema awal sama dengan hasil AutoMigrate sebelum migration berversi dipakai,
+// sehingga database lama cukup mencatat migration ini tanpa perubahan tabel.
+// Struct di bawah adalah salinan model pada saat itu dan tidak boleh diubah;
+// perubahan skema berikutnya masuk ke migration baru.
+func init() {
+	register(Migration{
+		ID: "0001_initial_schema",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(initialSchema()...); err != nil {
+				return err
+			}
+			// Role dulu berupa ENUM MySQL; 0002 mengubahnya menjadi varchar.
+			// SQLite tidak mengenal ENUM sehingga tetap varchar(20).
+			if tx.Dialector.Name() != "mysql" {
+				return nil
+			}
+			return tx.Exec("ALTER TABLE users MODIFY role ENUM('masyarakat','admin','super_admin','konselor','content_editor','supervisor') DEFAULT 'masyarakat'").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			tables := initialSchema()
+			for i := len(tables) - 1; i >= 0; i-- {
+				if err := tx.Migrator().DropTable(tables[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// Urutan mengikuti foreign key: tabel induk dibuat lebih dulu dan dihapus
+// paling akhir.
+func initialSchema() []interface{} {
+	return []interface{}{
+		&user0001{},
+		&passwordReset0001{},
+		&otpCode0001{},
+		&recoveryCode0001{},
+		&loginAttempt0001{},
+		&rateLimitCounter0001{},
+		&violenceCategory0001{},
+		&emergencyContact0001{},
+		&content0001{},
+		&laporan0001{},
+		&korban0001{},
+		&pelaku0001{},
+		&trackingLaporan0001{},
+		&event0001{},
+		&janjiTemu0001{},
+	}
+}
+
+type user0001 struct {
+	ID                uint `gorm:"primaryKey"`
+	FullName          string
+	Username          string `gorm:"size:255;unique;not null"`
+	Role              string `gorm:"size:20;default:'masyarakat'"`
+	PhotoProfile      string `gorm:"default:null"`
+	PhoneNumber       string `gorm:"unique;not null"`
+	Email             string `gorm:"size:255;unique;not null"`
+	NIK               uint
+	TempatLahir       string    `gorm:"default:null"`
+	TanggalLahir      time.Time `gorm:"default:null"`
+	JenisKelamin      string    `gorm:"default:null"`
+	Alamat            string
+	Password          string
+	EmailVerifiedAt   *time.Time
+	PhoneVerifiedAt   *time.Time
+	TwoFactorEnabled  bool `gorm:"default:false"`
+	TwoFactorSecret   string
+	TwoFactorLastStep int64 `gorm:"default:0"`
+	FailedLoginCount  int   `gorm:"default:0"`
+	LastFailedLoginAt *time.Time
+	LockedUntil       *time.Time
+	IsSuspended       bool `gorm:"default:false"`
+	SuspendedAt       *time.Time
+	AlasanSuspend     string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (user0001) TableName() string { return "users" }
+
+type passwordReset0001 struct {
+	ID        uint      `gorm:"primaryKey"`
+	Email     string    `gorm:"size:255;index;not null"`
+	TokenHash string    `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (passwordReset0001) TableName() string { return "password_resets" }
+
+type otpCode0001 struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"index;not null"`
+	Channel    string    `gorm:"size:10;not null"`
+	Target     string    `gorm:"size:255;not null"`
+	CodeHash   string    `gorm:"not null"`
+	Attempts   int       `gorm:"default:0"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	ConsumedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (otpCode0001) TableName() string { return "otp_codes" }
+
+type recoveryCode0001 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index;not null"`
+	CodeHash  string `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (recoveryCode0001) TableName() string { return "recovery_codes" }
+
+type loginAttempt0001 struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     *uint  `gorm:"index"`
+	Identifier string `gorm:"size:255"`
+	IPAddress  string `gorm:"size:45;index:idx_login_attempts_ip_created"`
+	UserAgent  string `gorm:"size:255"`
+	Success    bool
+	Reason     string    `gorm:"size:50"`
+	CreatedAt  time.Time `gorm:"index:idx_login_attempts_ip_created"`
+}
+
+func (loginAttempt0001) TableName() string { return "login_attempts" }
+
+type rateLimitCounter0001 struct {
+	CounterKey string    `gorm:"primaryKey;size:191"`
+	Hits       int       `gorm:"not null;default:0"`
+	ExpiresAt  time.Time `gorm:"index;not null"`
+}
+
+func (rateLimitCounter0001) TableName() string { return "rate_limit_counters" }
+
+type violenceCategory0001 struct {
+	ID           int64 `gorm:"primaryKey"`
+	CategoryName string
+	Image        string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (violenceCategory0001) TableName() string { return "violence_categories" }
+
+type emergencyContact0001 struct {
+	ID        int64  `gorm:"primaryKey"`
+	Phone     string `gorm:"unique;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (emergencyContact0001) TableName() string { return "emergency_contacts" }
+
+type content0001 struct {
+	ID                 uint `gorm:"primaryKey"`
+	Judul              string
+	IsiContent         string
+	ImageContent       string
+	ViolenceCategory   violenceCategory0001 `gorm:"foreignKey:ViolenceCategoryID"`
+	ViolenceCategoryID uint
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (content0001) TableName() string { return "contents" }
+
+type laporan0001 struct {
+	NoRegistrasi        string   `gorm:"primaryKey"`
+	User                user0001 `gorm:"foreignKey:UserID"`
+	UserID              uint
+	ViolenceCategory    violenceCategory0001 `gorm:"foreignKey:KategoriKekerasanID"`
+	KategoriKekerasanID uint
+	TanggalPelaporan    time.Time
+	TanggalKejadian     time.Time
+	KategoriLokasiKasus string
+	AlamatTKP           string
+	AlamatDetailTKP     string
+	KronologisKasus     string
+	Status              string
+	AlasanDibatalkan    string
+	WaktuDilihat        *time.Time
+	UserIDMelihat       *uint
+	WaktuDiproses       *time.Time
+	WaktuDibatalkan     *time.Time
+	Dokumentasi         datatypes.JSONMap `gorm:"type:json"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (laporan0001) TableName() string { return "laporans" }
+
+type korban0001 struct {
+	ID                   uint `gorm:"primaryKey"`
+	NoRegistrasi         string
+	NIKKorban            string
+	Nama                 string
+	Usia                 int
+	AlamatKorban         string
+	AlamatDetail         string
+	JenisKelamin         string
+	Agama                string
+	NoTelepon            string
+	Pendidikan           string
+	Pekerjaan            string
+	StatusPerkawinan     string
+	Kebangsaan           string
+	HubunganDenganKorban string
+	KeteranganLainnya    string
+	DokumentasiPelaku    string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+func (korban0001) TableName() string { return "korbans" }
+
+type pelaku0001 struct {
+	ID                   uint `gorm:"primaryKey"`
+	NoRegistrasi         string
+	NIKPelaku            string
+	Nama                 string
+	Usia                 int
+	AlamatPelaku         string
+	AlamatDetail         string
+	JenisKelamin         string
+	Agama                string
+	NoTelepon            string
+	Pendidikan           string
+	Pekerjaan            string
+	StatusPerkawinan     string
+	Kebangsaan           string
+	HubunganDenganKorban string
+	KeteranganLainnya    string
+	DokumentasiPelaku    string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+func (pelaku0001) TableName() string { return "pelakus" }
+
+type trackingLaporan0001 struct {
+	ID           uint   `gorm:"primaryKey"`
+	NoRegistrasi string `gorm:"not null"`
+	Keterangan   string
+	Document     datatypes.JSONMap `gorm:"type:json"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (trackingLaporan0001) TableName() string { return "tracking_laporans" }
+
+type event0001 struct {
+	ID                 uint `gorm:"primaryKey"`
+	NamaEvent          string
+	DeskripsiEvent     string
+	ThumbnailEvent     string
+	TanggalPelaksanaan time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (event0001) TableName() string { return "events" }
+
+type janjiTemu0001 struct {
+	ID                  uint     `gorm:"primaryKey"`
+	User                user0001 `gorm:"foreignKey:UserID"`
+	UserID              uint
+	WaktuDimulai        time.Time
+	WaktuSelesai        time.Time
+	KeperluanKonsultasi string
+	Status              string
+	UserTolakSetujui    user0001 `gorm:"foreignKey:UserIDTolakSetujui"`
+	UserIDTolakSetujui  *uint
+	AlasanDitolak       string `gorm:"column:alasan_ditolak"`
+	AlasanDibatalkan    string `gorm:"column:alasan_dibatalkan"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (janjiTemu0001) TableName() string { return "janji_temus" }
@@ -0,0 +1,27 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// Kolom role sebelumnya berupa ENUM MySQL yang tidak dikenal SQLite. Nilai
+// role sudah divalidasi di aplikasi, jadi cukup disimpan sebagai varchar.
+// Di SQLite 0001 sudah membuat varchar, sehingga migration ini hanya
+// mengubah database MySQL.
+func init() {
+	register(Migration{
+		ID: "0002_portable_user_role",
+		Up: func(tx *gorm.DB) error {
+			if tx.Dialector.Name() != "mysql" {
+				return nil
+			}
+			return tx.Exec("ALTER TABLE users MODIFY role VARCHAR(20) DEFAULT 'masyarakat'").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Dialector.Name() != "mysql" {
+				return nil
+			}
+			return tx.Exec("ALTER TABLE users MODIFY role ENUM('masyarakat','admin','super_admin','konselor','content_editor','supervisor') DEFAULT 'masyarakat'").Error
+		},
+	})
+}
@@ -0,0 +1,24 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// Preferensi bahasa pesan API. Kosong berarti mengikuti Accept-Language.
+func init() {
+	register(Migration{
+		ID: "0003_user_locale",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&userLocale0003{}, "Locale")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&userLocale0003{}, "Locale")
+		},
+	})
+}
+
+type userLocale0003 struct {
+	Locale string `gorm:"size:5;default:null"`
+}
+
+func (userLocale0003) TableName() string { return "users" }
@@ -0,0 +1,70 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Jadwal praktik konselor dan booking slot janji temu.
+func init() {
+	register(Migration{
+		ID: "0004_jadwal_konselor",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&janjiTemuKonselor0004{}, "KonselorID"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&jadwalKonselor0004{}, &pengecualianJadwal0004{}, &slotJanjiTemu0004{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&slotJanjiTemu0004{}, &pengecualianJadwal0004{}, &jadwalKonselor0004{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&janjiTemuKonselor0004{}, "KonselorID")
+		},
+	})
+}
+
+type janjiTemuKonselor0004 struct {
+	KonselorID *uint
+}
+
+func (janjiTemuKonselor0004) TableName() string { return "janji_temus" }
+
+type jadwalKonselor0004 struct {
+	ID          uint     `gorm:"primaryKey"`
+	KonselorID  uint     `gorm:"index"`
+	Konselor    user0001 `gorm:"foreignKey:KonselorID"`
+	Hari        int
+	JamMulai    string `gorm:"size:5"`
+	JamSelesai  string `gorm:"size:5"`
+	DurasiMenit int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (jadwalKonselor0004) TableName() string { return "jadwal_konselors" }
+
+type pengecualianJadwal0004 struct {
+	ID         uint      `gorm:"primaryKey"`
+	KonselorID uint      `gorm:"index"`
+	Konselor   user0001  `gorm:"foreignKey:KonselorID"`
+	Tanggal    time.Time `gorm:"index"`
+	JamMulai   string    `gorm:"size:5"`
+	JamSelesai string    `gorm:"size:5"`
+	Keterangan string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (pengecualianJadwal0004) TableName() string { return "pengecualian_jadwals" }
+
+type slotJanjiTemu0004 struct {
+	ID           uint      `gorm:"primaryKey"`
+	KonselorID   uint      `gorm:"uniqueIndex:idx_slot_konselor_waktu"`
+	WaktuDimulai time.Time `gorm:"uniqueIndex:idx_slot_konselor_waktu"`
+	JanjiTemuID  uint      `gorm:"uniqueIndex"`
+	CreatedAt    time.Time
+}
+
+func (slotJanjiTemu0004) TableName() string { return "slot_janji_temus" }
@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// Ruangan konsultasi dipakai untuk mendeteksi janji temu yang bentrok di
+// ruangan yang sama.
+func init() {
+	register(Migration{
+		ID: "0005_janji_temu_ruangan",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&janjiTemuRuangan0005{}, "Ruangan")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&janjiTemuRuangan0005{}, "Ruangan")
+		},
+	})
+}
+
+type janjiTemuRuangan0005 struct {
+	Ruangan string `gorm:"size:100"`
+}
+
+func (janjiTemuRuangan0005) TableName() string { return "janji_temus" }
@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Riwayat usulan jadwal ulang janji temu.
+func init() {
+	register(Migration{
+		ID: "0006_usulan_jadwal",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&usulanJadwal0006{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&usulanJadwal0006{})
+		},
+	})
+}
+
+type usulanJadwal0006 struct {
+	ID               uint          `gorm:"primaryKey"`
+	JanjiTemuID      uint          `gorm:"index"`
+	JanjiTemu        janjiTemu0001 `gorm:"foreignKey:JanjiTemuID"`
+	DiusulkanOlehID  uint
+	Pihak            string `gorm:"size:20"`
+	WaktuDimulai     time.Time
+	WaktuSelesai     time.Time
+	Alasan           string `gorm:"size:500"`
+	Status           string `gorm:"size:20"`
+	StatusSebelumnya string `gorm:"size:30"`
+	DitanggapiOlehID *uint
+	DitanggapiPada   *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (usulanJadwal0006) TableName() string { return "usulan_jadwals" }
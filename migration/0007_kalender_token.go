@@ -0,0 +1,30 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Token feed kalender iCalendar per user.
+func init() {
+	register(Migration{
+		ID: "0007_kalender_token",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&kalenderToken0007{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&kalenderToken0007{})
+		},
+	})
+}
+
+type kalenderToken0007 struct {
+	ID        uint     `gorm:"primaryKey"`
+	UserID    uint     `gorm:"uniqueIndex;not null"`
+	User      user0001 `gorm:"foreignKey:UserID"`
+	TokenHash string   `gorm:"size:64;uniqueIndex;not null"`
+	CreatedAt time.Time
+}
+
+func (kalenderToken0007) TableName() string { return "kalender_tokens" }
@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Antrean pengingat janji temu dan event.
+func init() {
+	register(Migration{
+		ID: "0008_pengingat",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&pengingat0008{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&pengingat0008{})
+		},
+	})
+}
+
+type pengingat0008 struct {
+	ID            uint   `gorm:"primaryKey"`
+	Kunci         string `gorm:"size:150;uniqueIndex;not null"`
+	Jenis         string `gorm:"size:30"`
+	UserID        uint   `gorm:"index"`
+	JanjiTemuID   *uint
+	EventID       *uint
+	WaktuAcuan    time.Time
+	KirimPada     time.Time `gorm:"index"`
+	Status        string    `gorm:"size:20;index"`
+	Percobaan     int
+	GalatTerakhir string `gorm:"size:500"`
+	TerkirimPada  *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (pengingat0008) TableName() string { return "pengingats" }
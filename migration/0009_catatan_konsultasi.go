@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Catatan dan hasil konsultasi setelah janji temu selesai.
+func init() {
+	register(Migration{
+		ID: "0009_catatan_konsultasi",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&catatanKonsultasi0009{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&catatanKonsultasi0009{})
+		},
+	})
+}
+
+type catatanKonsultasi0009 struct {
+	ID                uint `gorm:"primaryKey"`
+	JanjiTemuID       uint `gorm:"uniqueIndex"`
+	KonselorID        uint
+	Konselor          user0001 `gorm:"foreignKey:KonselorID"`
+	Kehadiran         string   `gorm:"size:20"`
+	KategoriHasil     string   `gorm:"size:30"`
+	Catatan           string   `gorm:"type:text"`
+	PerluTindakLanjut bool
+	TindakLanjut      string  `gorm:"type:text"`
+	NoRegistrasi      *string `gorm:"size:191;index"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (catatanKonsultasi0009) TableName() string { return "catatan_konsultasis" }
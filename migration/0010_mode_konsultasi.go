@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// Mode konsultasi (tatap muka, telepon, video) dan tautan rapat untuk
+// konsultasi video. Janji temu lama dianggap tatap muka.
+func init() {
+	register(Migration{
+		ID: "0010_mode_konsultasi",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range []string{"ModeKonsultasi", "TautanPertemuan"} {
+				if err := tx.Migrator().AddColumn(&janjiTemuMode0010{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, field := range []string{"TautanPertemuan", "ModeKonsultasi"} {
+				if err := tx.Migrator().DropColumn(&janjiTemuMode0010{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+type janjiTemuMode0010 struct {
+	ModeKonsultasi  string `gorm:"size:20;not null;default:tatap_muka"`
+	TautanPertemuan string `gorm:"size:500"`
+}
+
+func (janjiTemuMode0010) TableName() string { return "janji_temus" }
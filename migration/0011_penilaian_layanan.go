@@ -0,0 +1,34 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Penilaian kepuasan masyarakat atas janji temu dan laporan yang selesai.
+func init() {
+	register(Migration{
+		ID: "0011_penilaian_layanan",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&penilaianLayanan0011{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&penilaianLayanan0011{})
+		},
+	})
+}
+
+type penilaianLayanan0011 struct {
+	ID                  uint    `gorm:"primaryKey"`
+	UserID              uint    `gorm:"index"`
+	JanjiTemuID         *uint   `gorm:"uniqueIndex"`
+	NoRegistrasi        *string `gorm:"size:191;uniqueIndex"`
+	KonselorID          *uint   `gorm:"index"`
+	KategoriKekerasanID *uint   `gorm:"index"`
+	Rating              int
+	Komentar            string    `gorm:"type:text"`
+	CreatedAt           time.Time `gorm:"index"`
+}
+
+func (penilaianLayanan0011) TableName() string { return "penilaian_layanans" }
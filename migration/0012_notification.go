@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Kotak masuk notifikasi in-app untuk perubahan status laporan dan janji
+// temu.
+func init() {
+	register(Migration{
+		ID: "0012_notification",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&notification0012{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&notification0012{})
+		},
+	})
+}
+
+type notification0012 struct {
+	ID           uint    `gorm:"primaryKey"`
+	UserID       uint    `gorm:"index"`
+	Type         string  `gorm:"size:50"`
+	Title        string  `gorm:"size:200"`
+	Body         string  `gorm:"type:text"`
+	NoRegistrasi *string `gorm:"size:191"`
+	JanjiTemuID  *uint
+	ReadAt       *time.Time
+	CreatedAt    time.Time
+}
+
+func (notification0012) TableName() string { return "notifications" }
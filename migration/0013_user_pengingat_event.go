@@ -0,0 +1,24 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// Pengingat event hanya dikirim ke masyarakat yang memilih menerimanya.
+func init() {
+	register(Migration{
+		ID: "0013_user_pengingat_event",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&userPengingatEvent0013{}, "PengingatEvent")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&userPengingatEvent0013{}, "PengingatEvent")
+		},
+	})
+}
+
+type userPengingatEvent0013 struct {
+	PengingatEvent bool `gorm:"default:false"`
+}
+
+func (userPengingatEvent0013) TableName() string { return "users" }
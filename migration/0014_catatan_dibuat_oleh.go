@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// Petugas yang menyelesaikan janji temu dicatat terpisah dari konselor yang
+// menanganinya. Catatan lama menyimpan petugas tersebut di konselor_id, jadi
+// nilainya dipindahkan lalu konselor_id diisi dari janji temunya.
+func init() {
+	register(Migration{
+		ID: "0014_catatan_dibuat_oleh",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&catatanDibuatOleh0014{}, "DibuatOlehID"); err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE catatan_konsultasis SET dibuat_oleh_id = konselor_id").Error; err != nil {
+				return err
+			}
+			return tx.Exec(`UPDATE catatan_konsultasis SET konselor_id = (
+				SELECT janji_temus.konselor_id FROM janji_temus WHERE janji_temus.id = catatan_konsultasis.janji_temu_id
+			) WHERE EXISTS (
+				SELECT 1 FROM janji_temus WHERE janji_temus.id = catatan_konsultasis.janji_temu_id AND janji_temus.konselor_id IS NOT NULL
+			)`).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&catatanDibuatOleh0014{}, "DibuatOlehID")
+		},
+	})
+}
+
+type catatanDibuatOleh0014 struct {
+	DibuatOlehID uint
+}
+
+func (catatanDibuatOleh0014) TableName() string { return "catatan_konsultasis" }
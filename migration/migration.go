@@ -1,25 +1,139 @@
 package migration
 
 import (
-	"backend-pedika-fiber/database"
-	"backend-pedika-fiber/models"
+	"fmt"
 	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
 )
 
-func RunMigration() {
-	err := database.DB.AutoMigrate(
-		&models.User{},
-		&models.ViolenceCategory{},
-		&models.EmergencyContact{},
-		&models.Content{},
-		&models.Laporan{},
-		&models.Korban{},
-		&models.Pelaku{},
-		&models.TrackingLaporan{},
-		&models.Event{},
-		&models.JanjiTemu{})
+// Migration adalah satu perubahan skema yang berversi. ID harus unik dan
+// urutannya menentukan urutan eksekusi, jadi gunakan format "0001_nama".
+// Down wajib membatalkan persis apa yang dilakukan Up.
+//
+// Migration yang sudah dirilis tidak boleh berubah. Karena itu migration tidak
+// memakai struct di package models, yang ikut berubah bersama aplikasi,
+// melainkan salinan struct privat (dinamai dengan nomor migration-nya) atau
+// SQL. Setiap perubahan skema, termasuk kolom baru di models, ditulis sebagai
+// migration baru yang menjalankan DDL-nya sendiri.
+type Migration struct {
+	ID   string
+	Up   func(tx *gorm.DB) error
+	Down func(tx *gorm.DB) error
+}
+
+// SchemaMigration mencatat migration yang sudah dijalankan.
+type SchemaMigration struct {
+	ID        string `gorm:"primaryKey;size:191"`
+	AppliedAt time.Time
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Status struct {
+	ID        string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+var registry []Migration
+
+// register dipanggil dari init() di file migration masing-masing.
+func register(m Migration) {
+	registry = append(registry, m)
+}
+
+func migrations() []Migration {
+	sorted := append([]Migration(nil), registry...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+func applied(db *gorm.DB) (map[string]SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("migration: failed to prepare schema_migrations: %w", err)
+	}
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("migration: failed to read schema_migrations: %w", err)
+	}
+	result := make(map[string]SchemaMigration, len(rows))
+	for _, row := range rows {
+		result[row.ID] = row
+	}
+	return result, nil
+}
+
+// Up menjalankan semua migration yang belum pernah dijalankan, berurutan.
+// Proses berhenti di migration pertama yang gagal dan error dikembalikan.
+func Up(db *gorm.DB) error {
+	done, err := applied(db)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations() {
+		if _, ok := done[m.ID]; ok {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{ID: m.ID, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration: %s failed: %w", m.ID, err)
+		}
+		log.Printf("Applied migration %s\n", m.ID)
+	}
+	return nil
+}
+
+// Down membatalkan sejumlah steps migration terakhir yang sudah dijalankan.
+func Down(db *gorm.DB, steps int) error {
+	done, err := applied(db)
 	if err != nil {
-		log.Println(err)
+		return err
 	}
+	all := migrations()
+	for i := len(all) - 1; i >= 0 && steps > 0; i-- {
+		m := all[i]
+		if _, ok := done[m.ID]; !ok {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{ID: m.ID}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration: rollback %s failed: %w", m.ID, err)
+		}
+		log.Printf("Rolled back migration %s\n", m.ID)
+		steps--
+	}
+	return nil
+}
 
+func GetStatus(db *gorm.DB) ([]Status, error) {
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+	var result []Status
+	for _, m := range migrations() {
+		status := Status{ID: m.ID}
+		if row, ok := done[m.ID]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		result = append(result, status)
+	}
+	return result, nil
 }
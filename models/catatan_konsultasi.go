@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Kehadiran masyarakat pada janji temu.
+const (
+	KehadiranHadir      = "hadir"
+	KehadiranTidakHadir = "tidak_hadir"
+)
+
+// Kategori hasil konsultasi yang dihadiri.
+const (
+	HasilTertangani           = "tertangani"
+	HasilPendampinganLanjutan = "pendampingan_lanjutan"
+	HasilRujukan              = "rujukan"
+	HasilPelaporanKasus       = "pelaporan_kasus"
+	HasilLainnya              = "lainnya"
+)
+
+// CatatanKonsultasi adalah catatan konselor setelah janji temu selesai.
+// Catatannya rahasia: hanya bisa dibaca staff dengan izin catatan konsultasi
+// dan tidak pernah ikut dalam respons janji temu.
+type CatatanKonsultasi struct {
+	ID          uint `json:"id" gorm:"primaryKey"`
+	JanjiTemuID uint `json:"janji_temu_id" gorm:"uniqueIndex"`
+	KonselorID  uint `json:"konselor_id"`
+	Konselor    User `json:"-" gorm:"foreignKey:KonselorID"`
+	// DibuatOlehID adalah petugas yang menyelesaikan janji temu, bisa admin
+	// atau supervisor selain konselor yang menangani.
+	DibuatOlehID      uint   `json:"dibuat_oleh_id"`
+	Kehadiran         string `json:"kehadiran" gorm:"size:20"`
+	KategoriHasil     string `json:"kategori_hasil" gorm:"size:30"`
+	Catatan           string `json:"catatan" gorm:"type:text"`
+	PerluTindakLanjut bool   `json:"perlu_tindak_lanjut"`
+	TindakLanjut      string `json:"tindak_lanjut" gorm:"type:text"`
+	// NoRegistrasi menautkan konsultasi ke laporan kasus yang terkait.
+	NoRegistrasi *string   `json:"no_registrasi" gorm:"size:191;index"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
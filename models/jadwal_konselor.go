@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// JadwalKonselor adalah jam praktik mingguan seorang konselor. Hari mengikuti
+// time.Weekday (0 = Minggu) dan jam ditulis HH:mm dalam waktu lokal yang sama
+// dengan WaktuDimulai janji temu. Rentang jam dibagi menjadi slot sepanjang
+// DurasiMenit.
+type JadwalKonselor struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	KonselorID  uint      `json:"konselor_id" gorm:"index"`
+	Konselor    User      `json:"-" gorm:"foreignKey:KonselorID"`
+	Hari        int       `json:"hari"`
+	JamMulai    string    `json:"jam_mulai" gorm:"size:5"`
+	JamSelesai  string    `json:"jam_selesai" gorm:"size:5"`
+	DurasiMenit int       `json:"durasi_menit"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PengecualianJadwal menutup jadwal konselor pada tanggal tertentu, misalnya
+// libur nasional atau cuti. Jika JamMulai dan JamSelesai kosong, seluruh hari
+// tersebut ditutup.
+type PengecualianJadwal struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	KonselorID uint      `json:"konselor_id" gorm:"index"`
+	Konselor   User      `json:"-" gorm:"foreignKey:KonselorID"`
+	Tanggal    time.Time `json:"tanggal" gorm:"index"`
+	JamMulai   string    `json:"jam_mulai" gorm:"size:5"`
+	JamSelesai string    `json:"jam_selesai" gorm:"size:5"`
+	Keterangan string    `json:"keterangan"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SlotJanjiTemu mencatat slot konselor yang sedang dipesan. Booking yang
+// beririsan dicegah oleh row lock konselor di repository; unique index pada
+// konselor dan waktu mulai menjadi pengaman tambahan untuk slot yang sama.
+// Barisnya dihapus saat janji temu dibatalkan atau ditolak supaya slot bisa
+// dipesan lagi.
+type SlotJanjiTemu struct {
+	ID           uint      `gorm:"primaryKey"`
+	KonselorID   uint      `gorm:"uniqueIndex:idx_slot_konselor_waktu"`
+	WaktuDimulai time.Time `gorm:"uniqueIndex:idx_slot_konselor_waktu"`
+	JanjiTemuID  uint      `gorm:"uniqueIndex"`
+	CreatedAt    time.Time
+}
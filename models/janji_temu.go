@@ -2,10 +2,50 @@ package models
 
 import "time"
 
+// Status janji temu.
+const (
+	JanjiTemuBelumDisetujui = "Belum disetujui"
+	JanjiTemuDisetujui      = "Disetujui"
+	JanjiTemuDitolak        = "Ditolak"
+	JanjiTemuDibatalkan     = "Dibatalkan"
+	// JanjiTemuJadwalUlang berlaku selama ada usulan jadwal ulang yang belum
+	// ditanggapi.
+	JanjiTemuJadwalUlang = "Menunggu jadwal ulang"
+	// JanjiTemuSelesai dan JanjiTemuTidakHadir dicatat konselor setelah
+	// waktu janji temu lewat, bersama CatatanKonsultasi.
+	JanjiTemuSelesai    = "Selesai"
+	JanjiTemuTidakHadir = "Tidak hadir"
+)
+
+// Mode konsultasi janji temu.
+const (
+	ModeTatapMuka = "tatap_muka"
+	ModeTelepon   = "telepon"
+	ModeVideo     = "video"
+)
+
+// transisiJanjiTemu adalah perubahan status yang diizinkan. Ditolak,
+// Dibatalkan, Selesai, dan Tidak hadir adalah status akhir; janji temu yang sudah disetujui masih bisa
+// ditolak petugas, misalnya jika konselor berhalangan.
+var transisiJanjiTemu = map[string][]string{
+	JanjiTemuBelumDisetujui: {JanjiTemuDisetujui, JanjiTemuDitolak, JanjiTemuDibatalkan, JanjiTemuJadwalUlang},
+	JanjiTemuDisetujui:      {JanjiTemuDitolak, JanjiTemuDibatalkan, JanjiTemuJadwalUlang, JanjiTemuSelesai, JanjiTemuTidakHadir},
+	JanjiTemuJadwalUlang:    {JanjiTemuBelumDisetujui, JanjiTemuDisetujui, JanjiTemuDitolak, JanjiTemuDibatalkan},
+}
+
 type JanjiTemu struct {
-	ID                  uint      `json:"id" gorm:"primaryKey"`
-	User                User      `json:"user" gorm:"foreignKey:UserID"`
-	UserID              uint      `json:"user_id"`
+	ID     uint `json:"id" gorm:"primaryKey"`
+	User   User `json:"user" gorm:"foreignKey:UserID"`
+	UserID uint `json:"user_id"`
+	// KonselorID terisi jika janji temu dipesan dari slot jadwal konselor atau
+	// saat disetujui, yaitu konselor yang menangani konsultasi.
+	KonselorID     *uint  `json:"konselor_id"`
+	Konselor       *User  `json:"konselor,omitempty" gorm:"foreignKey:KonselorID"`
+	Ruangan        string `json:"ruangan" gorm:"size:100"`
+	ModeKonsultasi string `json:"mode_konsultasi" gorm:"size:20;not null;default:tatap_muka"`
+	// TautanPertemuan dibuat saat janji temu video disetujui dan hanya
+	// diberikan kepada peserta, sehingga tidak ikut di JSON biasa.
+	TautanPertemuan     string    `json:"-" gorm:"size:500"`
 	WaktuDimulai        time.Time `json:"waktu_dimulai"`
 	WaktuSelesai        time.Time `json:"waktu_selesai"`
 	KeperluanKonsultasi string    `json:"keperluan_konsultasi"`
@@ -17,3 +57,19 @@ type JanjiTemu struct {
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
 }
+
+// Aktif bernilai true selama janji temu masih memakai waktu konselor, yaitu
+// belum dibatalkan atau ditolak.
+func (j JanjiTemu) Aktif() bool {
+	return j.Status != JanjiTemuDitolak && j.Status != JanjiTemuDibatalkan
+}
+
+// BisaMenjadi melaporkan apakah status janji temu boleh berubah ke status.
+func (j JanjiTemu) BisaMenjadi(status string) bool {
+	for _, next := range transisiJanjiTemu[j.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// KalenderToken adalah token rahasia di URL feed kalender user. Aplikasi
+// kalender tidak bisa mengirim header Authorization, jadi token inilah yang
+// mengautentikasi feed. Hanya hash-nya yang disimpan.
+type KalenderToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"uniqueIndex;not null"`
+	User      User   `gorm:"foreignKey:UserID"`
+	TokenHash string `gorm:"size:64;uniqueIndex;not null"`
+	CreatedAt time.Time
+}
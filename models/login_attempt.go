@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type LoginAttempt struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     *uint     `gorm:"index" json:"user_id"`
+	Identifier string    `gorm:"size:255" json:"identifier"`
+	IPAddress  string    `gorm:"size:45;index:idx_login_attempts_ip_created" json:"ip_address"`
+	UserAgent  string    `gorm:"size:255" json:"user_agent"`
+	Success    bool      `json:"success"`
+	Reason     string    `gorm:"size:50" json:"reason"`
+	CreatedAt  time.Time `gorm:"index:idx_login_attempts_ip_created" json:"created_at"`
+}
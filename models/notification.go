@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Jenis notifikasi in-app.
+const (
+	NotificationLaporanDilihat      = "laporan_dilihat"
+	NotificationLaporanDiproses     = "laporan_diproses"
+	NotificationLaporanDibatalkan   = "laporan_dibatalkan"
+	NotificationTrackingLaporan     = "tracking_laporan"
+	NotificationJanjiTemuDisetujui  = "janji_temu_disetujui"
+	NotificationJanjiTemuDitolak    = "janji_temu_ditolak"
+	NotificationJanjiTemuDibatalkan = "janji_temu_dibatalkan"
+)
+
+// Notification adalah pesan di kotak masuk user. Title dan Body sudah
+// diterjemahkan ke bahasa penerima saat notifikasi dibuat, sama seperti
+// pengingat. NoRegistrasi atau JanjiTemuID menunjuk data yang berubah
+// supaya frontend bisa membuka halamannya.
+type Notification struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"index"`
+	Type         string     `json:"type" gorm:"size:50"`
+	Title        string     `json:"title" gorm:"size:200"`
+	Body         string     `json:"body" gorm:"type:text"`
+	NoRegistrasi *string    `json:"no_registrasi" gorm:"size:191"`
+	JanjiTemuID  *uint      `json:"janji_temu_id"`
+	ReadAt       *time.Time `json:"read_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
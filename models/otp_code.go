@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	OTPChannelEmail = "email"
+	OTPChannelPhone = "phone"
+)
+
+type OTPCode struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	Channel    string     `gorm:"size:10;not null" json:"channel"`
+	Target     string     `gorm:"size:255;not null" json:"target"`
+	CodeHash   string     `gorm:"not null" json:"-"`
+	Attempts   int        `gorm:"default:0" json:"attempts"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -6,8 +6,9 @@ import (
 
 type PasswordReset struct {
 	ID        uint      `gorm:"primaryKey"`
-	Email     string    `gorm:"uniqueIndex;not null"`
-	Token     string    `gorm:"uniqueIndex;not null"`
+	Email     string    `gorm:"size:255;index;not null"`
+	TokenHash string    `gorm:"size:64;uniqueIndex;not null"`
 	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
 	CreatedAt time.Time
 }
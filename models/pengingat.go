@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Jenis pengingat.
+const (
+	PengingatJanjiTemu24Jam = "janji_temu_24_jam"
+	PengingatJanjiTemu1Jam  = "janji_temu_1_jam"
+	PengingatEventH1        = "event_h_1"
+)
+
+// Status pengingat.
+const (
+	PengingatMenunggu = "Menunggu"
+	PengingatTerkirim = "Terkirim"
+	// PengingatDilewati dipakai jika janji temu atau event sudah berubah,
+	// dibatalkan, atau sudah dimulai saat pengingat akan dikirim.
+	PengingatDilewati = "Dilewati"
+	PengingatGagal    = "Gagal"
+)
+
+// Pengingat adalah satu pengingat untuk satu penerima yang disimpan di
+// database, sehingga tidak hilang saat aplikasi restart.
+type Pengingat struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+	// Kunci mencegah pengingat yang sama dibuat dua kali. Isinya memuat
+	// WaktuAcuan, jadi janji temu yang dijadwalkan ulang mendapat pengingat
+	// baru dan pengingat lamanya dilewati.
+	Kunci       string    `json:"-" gorm:"size:150;uniqueIndex;not null"`
+	Jenis       string    `json:"jenis" gorm:"size:30"`
+	UserID      uint      `json:"user_id" gorm:"index"`
+	JanjiTemuID *uint     `json:"janji_temu_id"`
+	EventID     *uint     `json:"event_id"`
+	WaktuAcuan  time.Time `json:"waktu_acuan"`
+	KirimPada   time.Time `json:"kirim_pada" gorm:"index"`
+	Status      string    `json:"status" gorm:"size:20;index"`
+	Percobaan   int       `json:"percobaan"`
+	// GalatTerakhir berisi error terakhir dari channel yang gagal.
+	GalatTerakhir string     `json:"galat_terakhir" gorm:"size:500"`
+	TerkirimPada  *time.Time `json:"terkirim_pada"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// PenilaianLayanan adalah penilaian kepuasan masyarakat atas satu janji temu
+// yang selesai atau satu laporan yang selesai ditangani. Tepat satu dari
+// JanjiTemuID dan NoRegistrasi terisi, dan masing-masing hanya bisa dinilai
+// sekali.
+type PenilaianLayanan struct {
+	ID           uint    `json:"id" gorm:"primaryKey"`
+	UserID       uint    `json:"user_id" gorm:"index"`
+	JanjiTemuID  *uint   `json:"janji_temu_id" gorm:"uniqueIndex"`
+	NoRegistrasi *string `json:"no_registrasi" gorm:"size:191;uniqueIndex"`
+	// KonselorID dan KategoriKekerasanID disalin saat penilaian dibuat supaya
+	// statistik tetap sama walaupun janji temu atau laporan berubah.
+	KonselorID          *uint     `json:"konselor_id" gorm:"index"`
+	KategoriKekerasanID *uint     `json:"kategori_kekerasan_id" gorm:"index"`
+	Rating              int       `json:"rating"`
+	Komentar            string    `json:"komentar" gorm:"type:text"`
+	CreatedAt           time.Time `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+type RateLimitCounter struct {
+	CounterKey string    `gorm:"primaryKey;size:191"`
+	Hits       int       `gorm:"not null;default:0"`
+	ExpiresAt  time.Time `gorm:"index;not null"`
+}
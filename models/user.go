@@ -6,22 +6,49 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
+const (
+	RoleMasyarakat    = "masyarakat"
+	RoleAdmin         = "admin"
+	RoleSuperAdmin    = "super_admin"
+	RoleKonselor      = "konselor"
+	RoleContentEditor = "content_editor"
+	RoleSupervisor    = "supervisor"
+)
+
 type User struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	FullName     string    `json:"full_name"`
-	Username     string    `json:"username" gorm:"size:255;unique;not null"`
-	Role         string    `json:"role" gorm:"type:enum('masyarakat','admin');default:'masyarakat'"`
-	PhotoProfile string    `json:"photo_profile" gorm:"default:null"`
-	PhoneNumber  string    `json:"phone_number" gorm:"unique;not null"`
-	Email        string    `json:"email" gorm:"size:255;unique;not null"`
-	NIK          uint      `json:"nik"`
-	TempatLahir  string    `json:"tempat_lahir" gorm:"default:null"`
-	TanggalLahir time.Time `json:"tanggal_lahir" gorm:"default:null"`
-	JenisKelamin string    `json:"jenis_kelamin" gorm:"default:null"`
-	Alamat       string    `json:"alamat"`
-	Password     string    `json:"password"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	FullName          string     `json:"full_name"`
+	Username          string     `json:"username" gorm:"size:255;unique;not null"`
+	Role              string     `json:"role" gorm:"size:20;default:'masyarakat'"`
+	PhotoProfile      string     `json:"photo_profile" gorm:"default:null"`
+	PhoneNumber       string     `json:"phone_number" gorm:"unique;not null"`
+	Email             string     `json:"email" gorm:"size:255;unique;not null"`
+	NIK               uint       `json:"nik"`
+	TempatLahir       string     `json:"tempat_lahir" gorm:"default:null"`
+	TanggalLahir      time.Time  `json:"tanggal_lahir" gorm:"default:null"`
+	JenisKelamin      string     `json:"jenis_kelamin" gorm:"default:null"`
+	Alamat            string     `json:"alamat"`
+	Password          string     `json:"password"`
+	EmailVerifiedAt   *time.Time `json:"email_verified_at"`
+	PhoneVerifiedAt   *time.Time `json:"phone_verified_at"`
+	TwoFactorEnabled  bool       `json:"two_factor_enabled" gorm:"default:false"`
+	TwoFactorSecret   string     `json:"-"`
+	TwoFactorLastStep int64      `json:"-" gorm:"default:0"`
+	FailedLoginCount  int        `json:"failed_login_count" gorm:"default:0"`
+	LastFailedLoginAt *time.Time `json:"last_failed_login_at"`
+	LockedUntil       *time.Time `json:"locked_until"`
+	IsSuspended       bool       `json:"is_suspended" gorm:"default:false"`
+	SuspendedAt       *time.Time `json:"suspended_at"`
+	AlasanSuspend     string     `json:"alasan_suspend"`
+	Locale            string     `json:"locale" gorm:"size:5;default:null"`
+	PengingatEvent    bool       `json:"pengingat_event" gorm:"default:false"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// IsVerified bernilai true jika email dan nomor telepon sudah diverifikasi OTP.
+func (u User) IsVerified() bool {
+	return u.EmailVerifiedAt != nil && u.PhoneVerifiedAt != nil
 }
 
 type LoginCredentials struct {
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Pihak yang mengajukan usulan jadwal ulang.
+const (
+	PihakMasyarakat = "masyarakat"
+	PihakPetugas    = "petugas"
+)
+
+// Status usulan jadwal ulang. Usulan yang dibalas dengan usulan tandingan
+// berstatus UsulanDibalas.
+const (
+	UsulanMenunggu = "Menunggu"
+	UsulanDiterima = "Diterima"
+	UsulanDitolak  = "Ditolak"
+	UsulanDibalas  = "Dibalas"
+)
+
+// UsulanJadwal adalah satu usulan waktu baru untuk janji temu. Semua usulan
+// disimpan sebagai riwayat; paling banyak satu yang berstatus Menunggu.
+type UsulanJadwal struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	JanjiTemuID     uint      `json:"janji_temu_id" gorm:"index"`
+	JanjiTemu       JanjiTemu `json:"-" gorm:"foreignKey:JanjiTemuID"`
+	DiusulkanOlehID uint      `json:"diusulkan_oleh_id"`
+	Pihak           string    `json:"pihak" gorm:"size:20"`
+	WaktuDimulai    time.Time `json:"waktu_dimulai"`
+	WaktuSelesai    time.Time `json:"waktu_selesai"`
+	Alasan          string    `json:"alasan" gorm:"size:500"`
+	Status          string    `json:"status" gorm:"size:20"`
+	// StatusSebelumnya adalah status janji temu sebelum penjadwalan ulang
+	// dimulai, dipakai kembali setelah usulan diterima atau ditolak.
+	StatusSebelumnya string     `json:"status_sebelumnya" gorm:"size:30"`
+	DitanggapiOlehID *uint      `json:"ditanggapi_oleh_id"`
+	DitanggapiPada   *time.Time `json:"ditanggapi_pada"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// PihakLawan mengembalikan pihak yang berhak menanggapi usulan.
+func (u UsulanJadwal) PihakLawan() string {
+	if u.Pihak == PihakMasyarakat {
+		return PihakPetugas
+	}
+	return PihakMasyarakat
+}
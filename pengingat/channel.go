@@ -0,0 +1,105 @@
+package pengingat
+
+import (
+	"backend-pedika-fiber/mail"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/sms"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Pesan adalah isi pengingat yang sudah diterjemahkan ke bahasa penerima.
+type Pesan struct {
+	Judul string
+	Isi   string
+}
+
+// Channel mengirim pengingat ke satu user. Channel yang tidak bisa
+// menjangkau user (misalnya user tanpa nomor telepon) mengembalikan
+// ErrTidakTerjangkau, bukan error pengiriman.
+type Channel interface {
+	Name() string
+	Send(user models.User, pesan Pesan) error
+}
+
+var ErrTidakTerjangkau = errors.New("pengingat: user tidak bisa dijangkau lewat channel ini")
+
+// EmailChannel mengirim pengingat lewat mail.Sender.
+type EmailChannel struct {
+	Sender mail.Sender
+}
+
+func (EmailChannel) Name() string { return "email" }
+
+func (ch EmailChannel) Send(user models.User, pesan Pesan) error {
+	if user.Email == "" {
+		return ErrTidakTerjangkau
+	}
+	return ch.Sender.Send(mail.Message{To: user.Email, Subject: pesan.Judul, Body: pesan.Isi})
+}
+
+// SMSChannel mengirim pengingat lewat sms.Sender.
+type SMSChannel struct {
+	Sender sms.Sender
+}
+
+func (SMSChannel) Name() string { return "sms" }
+
+func (ch SMSChannel) Send(user models.User, pesan Pesan) error {
+	if user.PhoneNumber == "" {
+		return ErrTidakTerjangkau
+	}
+	return ch.Sender.Send(sms.Message{To: user.PhoneNumber, Body: pesan.Isi})
+}
+
+// NewChannels membuat channel dari daftar nama di konfigurasi, misalnya
+// []string{"email", "sms"}, dengan sender yang juga dipakai handler.
+func NewChannels(names []string, mailSender mail.Sender, smsSender sms.Sender) ([]Channel, error) {
+	var channels []Channel
+	for _, name := range names {
+		switch strings.TrimSpace(name) {
+		case "email":
+			channels = append(channels, EmailChannel{Sender: mailSender})
+		case "sms":
+			channels = append(channels, SMSChannel{Sender: smsSender})
+		default:
+			return nil, fmt.Errorf("pengingat: unknown channel %q", name)
+		}
+	}
+	return channels, nil
+}
+
+// FakeChannel menyimpan pengingat di memori untuk test. Jika Err diisi,
+// setiap pengiriman gagal dengan error tersebut.
+type FakeChannel struct {
+	Err error
+
+	mu       sync.Mutex
+	terkirim []Terkirim
+}
+
+// Terkirim adalah satu pengingat yang diterima FakeChannel.
+type Terkirim struct {
+	UserID uint
+	Pesan  Pesan
+}
+
+func (f *FakeChannel) Name() string { return "fake" }
+
+func (f *FakeChannel) Send(user models.User, pesan Pesan) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.terkirim = append(f.terkirim, Terkirim{UserID: user.ID, Pesan: pesan})
+	return nil
+}
+
+func (f *FakeChannel) Messages() []Terkirim {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Terkirim(nil), f.terkirim...)
+}
@@ -0,0 +1,269 @@
+// Package pengingat menjadwalkan dan mengirim pengingat janji temu dan event.
+//
+// Setiap putaran Scheduler memasukkan pengingat yang akan jatuh tempo ke
+// tabel pengingat, lalu mengirim yang sudah jatuh tempo lewat Channel.
+// Karena antrean disimpan di database, pengingat tidak hilang saat aplikasi
+// restart, dan Claim mencegah dua replica mengirim pengingat yang sama.
+package pengingat
+
+import (
+	"backend-pedika-fiber/i18n"
+	"backend-pedika-fiber/models"
+	"backend-pedika-fiber/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// horizon adalah seberapa jauh ke depan pengingat dimasukkan ke antrean.
+	horizon = time.Hour
+	// batasTerlambat: pengingat yang baru dibuat setelah lewat waktunya lebih
+	// dari ini tidak dikirim, misalnya pengingat H-24 untuk janji temu yang
+	// baru disetujui 3 jam sebelum dimulai.
+	batasTerlambat = 30 * time.Minute
+	// lease adalah lama sebuah pengingat dipegang satu proses saat dikirim.
+	lease         = 5 * time.Minute
+	maksPercobaan = 5
+	batchSize     = 100
+	waktuLayout   = "02/01/2006 15:04"
+)
+
+// aturan menentukan kapan pengingat dikirim relatif terhadap WaktuAcuan.
+var aturan = map[string]time.Duration{
+	models.PengingatJanjiTemu24Jam: 24 * time.Hour,
+	models.PengingatJanjiTemu1Jam:  time.Hour,
+	models.PengingatEventH1:        24 * time.Hour,
+}
+
+type Scheduler struct {
+	repos    *repository.Repositories
+	channels []Channel
+	interval time.Duration
+}
+
+func New(repos *repository.Repositories, interval time.Duration, channels ...Channel) *Scheduler {
+	return &Scheduler{repos: repos, channels: channels, interval: interval}
+}
+
+// Run menjalankan Tick setiap interval sampai ctx selesai.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Tick(time.Now().UTC()); err != nil {
+			log.Printf("pengingat: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick menjadwalkan pengingat baru lalu mengirim yang sudah jatuh tempo pada
+// waktu now.
+func (s *Scheduler) Tick(now time.Time) error {
+	if err := s.jadwalkan(now); err != nil {
+		return fmt.Errorf("jadwalkan: %w", err)
+	}
+	return s.kirim(now)
+}
+
+func (s *Scheduler) jadwalkan(now time.Time) error {
+	var antrean []models.Pengingat
+	tambah := func(jenis string, userID uint, janjiTemuID, eventID *uint, acuan time.Time) {
+		kirimPada := acuan.Add(-aturan[jenis])
+		if !dalamHorizon(kirimPada, now) {
+			return
+		}
+		sumber := fmt.Sprintf("janji_temu:%d", deref(janjiTemuID))
+		if eventID != nil {
+			sumber = fmt.Sprintf("event:%d", *eventID)
+		}
+		antrean = append(antrean, models.Pengingat{
+			Kunci:       fmt.Sprintf("%s:%s:%d:user:%d", sumber, jenis, acuan.Unix(), userID),
+			Jenis:       jenis,
+			UserID:      userID,
+			JanjiTemuID: janjiTemuID,
+			EventID:     eventID,
+			WaktuAcuan:  acuan,
+			KirimPada:   kirimPada,
+			Status:      models.PengingatMenunggu,
+		})
+	}
+
+	// Rentang dibuat sedikit lebih lebar dari aturan terpanjang; tambah
+	// yang menyaring pengingat di luar horizon.
+	dari, sampai := now, now.Add(24*time.Hour+horizon)
+	janjiTemus, err := s.repos.JanjiTemu.ListDisetujui(dari, sampai)
+	if err != nil {
+		return err
+	}
+	for i := range janjiTemus {
+		j := janjiTemus[i]
+		tambah(models.PengingatJanjiTemu24Jam, j.UserID, &j.ID, nil, j.WaktuDimulai)
+		tambah(models.PengingatJanjiTemu1Jam, j.UserID, &j.ID, nil, j.WaktuDimulai)
+	}
+
+	events, err := s.repos.Events.ListBetween(dari, sampai)
+	if err != nil {
+		return err
+	}
+	// Penerima event dimuat hanya jika ada event yang pengingatnya masuk
+	// horizon, bukan setiap putaran selama event masih dalam 25 jam.
+	var jatuhTempo []models.Event
+	for _, e := range events {
+		if dalamHorizon(e.TanggalPelaksanaan.Add(-aturan[models.PengingatEventH1]), now) {
+			jatuhTempo = append(jatuhTempo, e)
+		}
+	}
+	if len(jatuhTempo) > 0 {
+		penerima, err := s.repos.Pengingat.ListPenerimaEvent()
+		if err != nil {
+			return err
+		}
+		for i := range jatuhTempo {
+			e := jatuhTempo[i]
+			for _, user := range penerima {
+				tambah(models.PengingatEventH1, user.ID, nil, &e.ID, e.TanggalPelaksanaan)
+			}
+		}
+	}
+	return s.repos.Pengingat.Enqueue(antrean)
+}
+
+// dalamHorizon bernilai true jika pengingat dengan waktu kirim tersebut perlu
+// dimasukkan ke antrean pada putaran now.
+func dalamHorizon(kirimPada, now time.Time) bool {
+	return !kirimPada.After(now.Add(horizon)) && !kirimPada.Before(now.Add(-batasTerlambat))
+}
+
+func (s *Scheduler) kirim(now time.Time) error {
+	due, err := s.repos.Pengingat.Due(now, batchSize)
+	if err != nil {
+		return err
+	}
+	for i := range due {
+		p := &due[i]
+		ok, err := s.repos.Pengingat.Claim(p, now.Add(lease))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := s.kirimSatu(p, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// kirimSatu mengirim satu pengingat ke semua channel yang sesuai jenisnya.
+// Pengingat dianggap terkirim jika minimal satu channel berhasil.
+func (s *Scheduler) kirimSatu(p *models.Pengingat, now time.Time) error {
+	user, pesan, masihBerlaku := s.susunPesan(p, now)
+	if !masihBerlaku {
+		p.Status = models.PengingatDilewati
+		return s.repos.Pengingat.Save(p)
+	}
+
+	var galat []string
+	terkirim := false
+	for _, channel := range s.channels {
+		if p.EventID != nil && channel.Name() == "sms" {
+			// Pengingat event bersifat pengumuman; SMS berbayar dan dipakai
+			// hanya untuk pengingat janji temu milik user sendiri.
+			continue
+		}
+		err := channel.Send(user, pesan)
+		switch {
+		case err == nil:
+			terkirim = true
+		case errors.Is(err, ErrTidakTerjangkau):
+		default:
+			galat = append(galat, channel.Name()+": "+err.Error())
+		}
+	}
+
+	p.Percobaan++
+	switch {
+	case terkirim:
+		p.Status = models.PengingatTerkirim
+		p.TerkirimPada = &now
+	case len(galat) == 0:
+		// Tidak ada channel yang bisa menjangkau user.
+		p.Status = models.PengingatDilewati
+	case p.Percobaan >= maksPercobaan:
+		p.Status = models.PengingatGagal
+	default:
+		p.KirimPada = now.Add(time.Duration(p.Percobaan) * s.interval)
+	}
+	p.GalatTerakhir = truncate(strings.Join(galat, "; "), 500)
+	return s.repos.Pengingat.Save(p)
+}
+
+// susunPesan memuat penerima dan sumber pengingat. masihBerlaku false jika
+// janji temu atau event sudah berubah, dibatalkan, atau sudah dimulai.
+func (s *Scheduler) susunPesan(p *models.Pengingat, now time.Time) (models.User, Pesan, bool) {
+	if !now.Before(p.WaktuAcuan) {
+		return models.User{}, Pesan{}, false
+	}
+	user, err := s.repos.Users.FindByID(p.UserID)
+	if err != nil || user.IsSuspended {
+		return user, Pesan{}, false
+	}
+
+	if p.JanjiTemuID != nil {
+		j, err := s.repos.JanjiTemu.FindByID(*p.JanjiTemuID)
+		if err != nil || j.Status != models.JanjiTemuDisetujui || !j.WaktuDimulai.Equal(p.WaktuAcuan) {
+			return user, Pesan{}, false
+		}
+		return user, Pesan{
+			Judul: i18n.Translate(user.Locale, "pengingat.janji_temu_subject"),
+			Isi:   i18n.Translate(user.Locale, "pengingat.janji_temu_body", j.WaktuDimulai.Format(waktuLayout), lokasi(user.Locale, j)),
+		}, true
+	}
+
+	e, err := s.repos.Events.FindByID(deref(p.EventID))
+	if err != nil || !e.TanggalPelaksanaan.Equal(p.WaktuAcuan) {
+		return user, Pesan{}, false
+	}
+	return user, Pesan{
+		Judul: i18n.Translate(user.Locale, "pengingat.event_subject", e.NamaEvent),
+		Isi:   i18n.Translate(user.Locale, "pengingat.event_body", e.NamaEvent, e.TanggalPelaksanaan.Format(waktuLayout)),
+	}, true
+}
+
+// lokasi untuk konsultasi video adalah tautan pertemuan; pengingat janji temu
+// hanya dikirim ke pemilik janji temu sehingga tautannya aman disertakan.
+func lokasi(locale string, j models.JanjiTemu) string {
+	switch {
+	case j.ModeKonsultasi == models.ModeVideo && j.TautanPertemuan != "":
+		return j.TautanPertemuan
+	case j.ModeKonsultasi == models.ModeTelepon:
+		return i18n.Translate(locale, "pengingat.lokasi_telepon")
+	case j.Ruangan == "":
+		return i18n.Translate(locale, "pengingat.lokasi_menyusul")
+	}
+	return j.Ruangan
+}
+
+func deref(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
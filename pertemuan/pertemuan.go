@@ -0,0 +1,67 @@
+// Package pertemuan membuat tautan rapat video untuk janji temu yang
+// dilakukan secara daring.
+package pertemuan
+
+import (
+	"backend-pedika-fiber/models"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultTemplate dipakai jika PERTEMUAN_TEMPLATE_TAUTAN tidak di-set.
+const DefaultTemplate = "https://meet.jit.si/pedika-{kode}"
+
+// Provider membuat tautan rapat untuk satu janji temu. StaticProvider
+// dipakai di production, dan FakeProvider dipakai saat testing.
+type Provider interface {
+	BuatTautan(janjiTemu models.JanjiTemu) (string, error)
+}
+
+// StaticProvider mengisi template ruang rapat statis seperti Jitsi. {kode}
+// diganti kode acak supaya nama ruangan tidak bisa ditebak orang di luar
+// peserta, dan {id} diganti ID janji temu.
+type StaticProvider struct {
+	Template string
+}
+
+func (p StaticProvider) BuatTautan(janjiTemu models.JanjiTemu) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("pertemuan: failed to generate room code: %w", err)
+	}
+	return strings.NewReplacer(
+		"{kode}", hex.EncodeToString(buf),
+		"{id}", strconv.FormatUint(uint64(janjiTemu.ID), 10),
+	).Replace(p.Template), nil
+}
+
+// FakeProvider mengembalikan tautan yang bisa ditebak dan mencatat janji temu
+// yang dibuatkan tautan, untuk test. Jika Err di-set, semua pembuatan tautan
+// gagal dengan error tersebut.
+type FakeProvider struct {
+	Err error
+
+	mu    sync.Mutex
+	calls []uint
+}
+
+func (f *FakeProvider) BuatTautan(janjiTemu models.JanjiTemu) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return "", f.Err
+	}
+	f.calls = append(f.calls, janjiTemu.ID)
+	return fmt.Sprintf("https://meet.test/janji-temu-%d", janjiTemu.ID), nil
+}
+
+// Calls mengembalikan ID janji temu yang sudah dibuatkan tautan.
+func (f *FakeProvider) Calls() []uint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]uint(nil), f.calls...)
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"backend-pedika-fiber/models"
+	"log"
+	"math/rand"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormStore menyimpan counter di tabel rate_limit_counters sehingga batasan
+// berlaku bersama untuk semua replica yang memakai database yang sama.
+type GormStore struct {
+	DB *gorm.DB
+}
+
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{DB: db}
+}
+
+func (s *GormStore) Increment(key string, window time.Duration) (int, time.Time, error) {
+	now := time.Now()
+	key, resetAt := windowKey(key, window, now)
+
+	counter := models.RateLimitCounter{CounterKey: key, Hits: 1, ExpiresAt: resetAt}
+	err := s.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "counter_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"hits": gorm.Expr("hits + 1")}),
+	}).Create(&counter).Error
+	if err != nil {
+		return 0, resetAt, err
+	}
+	if err := s.DB.Where("counter_key = ?", key).First(&counter).Error; err != nil {
+		return 0, resetAt, err
+	}
+
+	// Bersihkan counter lama sesekali, tidak perlu di setiap request.
+	if rand.Intn(100) == 0 {
+		if err := s.DB.Where("expires_at < ?", now).Delete(&models.RateLimitCounter{}).Error; err != nil {
+			log.Println("Error cleaning rate limit counters:", err)
+		}
+	}
+	return counter.Hits, resetAt, nil
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore cukup untuk satu instance aplikasi. Untuk beberapa replica
+// gunakan GormStore supaya counter dibagi lewat database.
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]memoryCounter
+	lastGC   time.Time
+}
+
+type memoryCounter struct {
+	hits    int
+	resetAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: make(map[string]memoryCounter)}
+}
+
+func (s *MemoryStore) Increment(key string, window time.Duration) (int, time.Time, error) {
+	now := time.Now()
+	key, resetAt := windowKey(key, window, now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastGC) > time.Minute {
+		for k, counter := range s.counters {
+			if !counter.resetAt.After(now) {
+				delete(s.counters, k)
+			}
+		}
+		s.lastGC = now
+	}
+
+	counter := s.counters[key]
+	counter.hits++
+	counter.resetAt = resetAt
+	s.counters[key] = counter
+	return counter.hits, resetAt, nil
+}
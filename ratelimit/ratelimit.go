@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"backend-pedika-fiber/config"
+	"fmt"
+	"time"
+)
+
+// Store menyimpan counter fixed-window. Increment harus atomic supaya
+// batasannya akurat walaupun banyak request datang bersamaan.
+type Store interface {
+	Increment(key string, window time.Duration) (hits int, resetAt time.Time, err error)
+}
+
+type KeyBy int
+
+const (
+	// KeyByIP dipakai untuk route publik yang belum login. IP-nya adalah IP
+	// client dari c.IP(), yang di balik load balancer dibaca dari PROXY_HEADER
+	// hanya jika request datang dari TRUSTED_PROXIES (lihat routes.NewApp).
+	KeyByIP KeyBy = iota
+	// KeyByUser dipakai untuk route yang sudah melewati middleware auth,
+	// dengan fallback ke IP jika user tidak ditemukan.
+	KeyByUser
+)
+
+type Rule struct {
+	Name   string
+	Max    int
+	Window time.Duration
+	KeyBy  KeyBy
+	// OnlyWrites membuat request GET/HEAD/OPTIONS tidak dihitung.
+	OnlyWrites bool
+}
+
+// NewRule membuat Rule dari batas yang sudah divalidasi oleh package config.
+func NewRule(name string, limit config.Limit, keyBy KeyBy) Rule {
+	return Rule{Name: name, Max: limit.Max, Window: limit.Window, KeyBy: keyBy}
+}
+
+func windowKey(key string, window time.Duration, now time.Time) (string, time.Time) {
+	start := now.Truncate(window)
+	return fmt.Sprintf("%s:%d", key, start.Unix()), start.Add(window)
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OTPRepository interface {
+	// Latest mengembalikan kode OTP terakhir yang dibuat untuk channel ini,
+	// dipakai untuk menghitung cooldown kirim ulang.
+	Latest(userID uint, channel string) (models.OTPCode, error)
+	// FindActive mengembalikan kode terbaru yang belum dipakai untuk target ini.
+	FindActive(userID uint, channel, target string) (models.OTPCode, error)
+	Create(otp *models.OTPCode) error
+	// ClaimAttempt mencatat satu percobaan verifikasi sebelum kode diperiksa,
+	// dalam satu UPDATE bersyarat: hanya berhasil jika percobaan masih di
+	// bawah maxAttempts, sehingga request paralel tidak bisa melewati batas.
+	ClaimAttempt(id uint, maxAttempts int) (bool, error)
+	// Consume menandai kode terpakai dan menandai channel user terverifikasi
+	// dalam satu transaksi.
+	Consume(otp models.OTPCode, now time.Time) error
+}
+
+type TwoFactorRepository interface {
+	SaveSecret(userID uint, secret string) error
+	// Enable mengaktifkan 2FA dan mengganti semua recovery code dengan
+	// codeHashes dalam satu transaksi.
+	Enable(userID uint, step int64, codeHashes []string) error
+	Disable(userID uint) error
+	ReplaceRecoveryCodes(userID uint, codeHashes []string) error
+	// AdvanceStep menyimpan step TOTP terakhir. Mengembalikan false jika step
+	// tersebut sudah pernah dipakai, sehingga kode yang sama tidak bisa di-replay.
+	AdvanceStep(userID uint, step int64) (bool, error)
+	UnusedRecoveryCodes(userID uint) ([]models.RecoveryCode, error)
+	// UseRecoveryCode mengembalikan false jika code sudah dipakai request lain.
+	UseRecoveryCode(id uint, now time.Time) (bool, error)
+}
+
+type PasswordResetRepository interface {
+	// Replace menghapus token lama yang belum dipakai lalu menyimpan token baru.
+	Replace(reset *models.PasswordReset) error
+	FindValid(tokenHash string, now time.Time) (models.PasswordReset, error)
+	// Consume menandai token terpakai sekaligus mengganti password user.
+	// Mengembalikan ErrNotFound jika token sudah dipakai.
+	Consume(reset models.PasswordReset, hashedPassword string, now time.Time) error
+}
+
+type gormOTPRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOTPRepository(db *gorm.DB) OTPRepository {
+	return &gormOTPRepository{db: db}
+}
+
+func (r *gormOTPRepository) Latest(userID uint, channel string) (models.OTPCode, error) {
+	var otp models.OTPCode
+	err := r.db.Where("user_id = ? AND channel = ?", userID, channel).Order("created_at desc").First(&otp).Error
+	return otp, translate(err)
+}
+
+func (r *gormOTPRepository) FindActive(userID uint, channel, target string) (models.OTPCode, error) {
+	var otp models.OTPCode
+	err := r.db.Where("user_id = ? AND channel = ? AND target = ? AND consumed_at IS NULL", userID, channel, target).
+		Order("created_at desc").
+		First(&otp).Error
+	return otp, translate(err)
+}
+
+func (r *gormOTPRepository) Create(otp *models.OTPCode) error {
+	return r.db.Create(otp).Error
+}
+
+func (r *gormOTPRepository) ClaimAttempt(id uint, maxAttempts int) (bool, error) {
+	result := r.db.Model(&models.OTPCode{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *gormOTPRepository) Consume(otp models.OTPCode, now time.Time) error {
+	verifiedColumn := "email_verified_at"
+	if otp.Channel == models.OTPChannelPhone {
+		verifiedColumn = "phone_verified_at"
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.OTPCode{}).Where("id = ?", otp.ID).Update("consumed_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", otp.UserID).Update(verifiedColumn, now).Error
+	})
+}
+
+type gormTwoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &gormTwoFactorRepository{db: db}
+}
+
+func (r *gormTwoFactorRepository) SaveSecret(userID uint, secret string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"two_factor_secret":    secret,
+		"two_factor_last_step": 0,
+	}).Error
+}
+
+func (r *gormTwoFactorRepository) Enable(userID uint, step int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_enabled":   true,
+			"two_factor_last_step": step,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func (r *gormTwoFactorRepository) Disable(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_enabled":   false,
+			"two_factor_secret":    "",
+			"two_factor_last_step": 0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+func (r *gormTwoFactorRepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if err := tx.Create(&models.RecoveryCode{UserID: userID, CodeHash: hash, CreatedAt: time.Now()}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *gormTwoFactorRepository) AdvanceStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND two_factor_last_step < ?", userID, step).
+		Update("two_factor_last_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gormTwoFactorRepository) UnusedRecoveryCodes(userID uint) ([]models.RecoveryCode, error) {
+	var codes []models.RecoveryCode
+	err := r.db.Where("user_id = ? AND used_at IS NULL", userID).Find(&codes).Error
+	return codes, err
+}
+
+func (r *gormTwoFactorRepository) UseRecoveryCode(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+type gormPasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &gormPasswordResetRepository{db: db}
+}
+
+func (r *gormPasswordResetRepository) Replace(reset *models.PasswordReset) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("email = ? AND used_at IS NULL", reset.Email).Delete(&models.PasswordReset{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(reset).Error
+	})
+}
+
+func (r *gormPasswordResetRepository) FindValid(tokenHash string, now time.Time) (models.PasswordReset, error) {
+	var reset models.PasswordReset
+	err := r.db.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).First(&reset).Error
+	return reset, translate(err)
+}
+
+func (r *gormPasswordResetRepository) Consume(reset models.PasswordReset, hashedPassword string, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Kondisi used_at IS NULL memastikan token hanya bisa dipakai sekali
+		// walaupun ada dua request reset yang berjalan bersamaan.
+		result := tx.Model(&models.PasswordReset{}).
+			Where("id = ? AND used_at IS NULL", reset.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Model(&models.User{}).Where("email = ?", reset.Email).Updates(map[string]interface{}{
+			"password":   hashedPassword,
+			"updated_at": now,
+		}).Error
+	})
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+
+	"gorm.io/gorm"
+)
+
+type ContentRepository interface {
+	List() ([]models.Content, error)
+	// FindByID mengembalikan content beserta kategori kekerasannya.
+	FindByID(id uint) (models.Content, error)
+	Create(content *models.Content) error
+	Save(content *models.Content) error
+	Delete(id uint) error
+}
+
+type ViolenceCategoryRepository interface {
+	List() ([]models.ViolenceCategory, error)
+	FindByID(id uint) (models.ViolenceCategory, error)
+	Create(category *models.ViolenceCategory) error
+	Save(category *models.ViolenceCategory) error
+	Delete(id uint) error
+}
+
+type EmergencyContactRepository interface {
+	// First mengembalikan satu-satunya kontak darurat yang dipakai aplikasi.
+	First() (models.EmergencyContact, error)
+	FindByID(id uint) (models.EmergencyContact, error)
+	Save(contact *models.EmergencyContact) error
+}
+
+type gormContentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormContentRepository(db *gorm.DB) ContentRepository {
+	return &gormContentRepository{db: db}
+}
+
+func (r *gormContentRepository) List() ([]models.Content, error) {
+	var contents []models.Content
+	err := r.db.Preload("ViolenceCategory").Find(&contents).Error
+	return contents, err
+}
+
+func (r *gormContentRepository) FindByID(id uint) (models.Content, error) {
+	var content models.Content
+	err := r.db.Preload("ViolenceCategory").First(&content, id).Error
+	return content, translate(err)
+}
+
+func (r *gormContentRepository) Create(content *models.Content) error {
+	return r.db.Create(content).Error
+}
+
+func (r *gormContentRepository) Save(content *models.Content) error {
+	return r.db.Save(content).Error
+}
+
+func (r *gormContentRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Content{}, id).Error
+}
+
+type gormViolenceCategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewGormViolenceCategoryRepository(db *gorm.DB) ViolenceCategoryRepository {
+	return &gormViolenceCategoryRepository{db: db}
+}
+
+func (r *gormViolenceCategoryRepository) List() ([]models.ViolenceCategory, error) {
+	var categories []models.ViolenceCategory
+	err := r.db.Find(&categories).Error
+	return categories, err
+}
+
+func (r *gormViolenceCategoryRepository) FindByID(id uint) (models.ViolenceCategory, error) {
+	var category models.ViolenceCategory
+	err := r.db.First(&category, id).Error
+	return category, translate(err)
+}
+
+func (r *gormViolenceCategoryRepository) Create(category *models.ViolenceCategory) error {
+	return r.db.Create(category).Error
+}
+
+func (r *gormViolenceCategoryRepository) Save(category *models.ViolenceCategory) error {
+	return r.db.Save(category).Error
+}
+
+func (r *gormViolenceCategoryRepository) Delete(id uint) error {
+	return r.db.Where("id = ?", id).Delete(&models.ViolenceCategory{}).Error
+}
+
+type gormEmergencyContactRepository struct {
+	db *gorm.DB
+}
+
+func NewGormEmergencyContactRepository(db *gorm.DB) EmergencyContactRepository {
+	return &gormEmergencyContactRepository{db: db}
+}
+
+func (r *gormEmergencyContactRepository) First() (models.EmergencyContact, error) {
+	var contact models.EmergencyContact
+	err := r.db.First(&contact).Error
+	return contact, translate(err)
+}
+
+func (r *gormEmergencyContactRepository) FindByID(id uint) (models.EmergencyContact, error) {
+	var contact models.EmergencyContact
+	err := r.db.First(&contact, id).Error
+	return contact, translate(err)
+}
+
+func (r *gormEmergencyContactRepository) Save(contact *models.EmergencyContact) error {
+	return r.db.Save(contact).Error
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EventRepository interface {
+	List() ([]models.Event, error)
+	// ListBetween mengembalikan event yang dilaksanakan dalam rentang
+	// [dari, sampai).
+	ListBetween(dari, sampai time.Time) ([]models.Event, error)
+	FindByID(id uint) (models.Event, error)
+	Create(event *models.Event) error
+	Save(event *models.Event) error
+	Delete(id uint) error
+}
+
+type gormEventRepository struct {
+	db *gorm.DB
+}
+
+func NewGormEventRepository(db *gorm.DB) EventRepository {
+	return &gormEventRepository{db: db}
+}
+
+func (r *gormEventRepository) List() ([]models.Event, error) {
+	var events []models.Event
+	err := r.db.Find(&events).Error
+	return events, err
+}
+
+func (r *gormEventRepository) ListBetween(dari, sampai time.Time) ([]models.Event, error) {
+	var events []models.Event
+	err := r.db.Where("tanggal_pelaksanaan >= ? AND tanggal_pelaksanaan < ?", dari, sampai).
+		Order("tanggal_pelaksanaan").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *gormEventRepository) FindByID(id uint) (models.Event, error) {
+	var event models.Event
+	err := r.db.First(&event, id).Error
+	return event, translate(err)
+}
+
+func (r *gormEventRepository) Create(event *models.Event) error {
+	return r.db.Create(event).Error
+}
+
+func (r *gormEventRepository) Save(event *models.Event) error {
+	return r.db.Save(event).Error
+}
+
+func (r *gormEventRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Event{}, id).Error
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type JadwalKonselorRepository interface {
+	// ListJadwal mengembalikan jadwal mingguan seorang konselor, atau semua
+	// konselor jika konselorID 0. User konselor ikut dimuat.
+	ListJadwal(konselorID uint) ([]models.JadwalKonselor, error)
+	FindJadwal(id uint) (models.JadwalKonselor, error)
+	CreateJadwal(jadwal *models.JadwalKonselor) error
+	DeleteJadwal(jadwal *models.JadwalKonselor) error
+
+	// ListPengecualian mengembalikan pengecualian dengan tanggal dalam rentang
+	// [dari, sampai); sampai yang kosong berarti tanpa batas akhir dan
+	// konselorID 0 berarti semua konselor.
+	ListPengecualian(konselorID uint, dari, sampai time.Time) ([]models.PengecualianJadwal, error)
+	FindPengecualian(id uint) (models.PengecualianJadwal, error)
+	CreatePengecualian(pengecualian *models.PengecualianJadwal) error
+	DeletePengecualian(pengecualian *models.PengecualianJadwal) error
+}
+
+type gormJadwalKonselorRepository struct {
+	db *gorm.DB
+}
+
+func NewGormJadwalKonselorRepository(db *gorm.DB) JadwalKonselorRepository {
+	return &gormJadwalKonselorRepository{db: db}
+}
+
+func (r *gormJadwalKonselorRepository) ListJadwal(konselorID uint) ([]models.JadwalKonselor, error) {
+	var jadwal []models.JadwalKonselor
+	query := r.db.Preload("Konselor", publikUser).Order("hari, jam_mulai")
+	if konselorID != 0 {
+		query = query.Where("konselor_id = ?", konselorID)
+	}
+	err := query.Find(&jadwal).Error
+	return jadwal, err
+}
+
+func (r *gormJadwalKonselorRepository) FindJadwal(id uint) (models.JadwalKonselor, error) {
+	var jadwal models.JadwalKonselor
+	err := r.db.First(&jadwal, id).Error
+	return jadwal, translate(err)
+}
+
+func (r *gormJadwalKonselorRepository) CreateJadwal(jadwal *models.JadwalKonselor) error {
+	return r.db.Create(jadwal).Error
+}
+
+func (r *gormJadwalKonselorRepository) DeleteJadwal(jadwal *models.JadwalKonselor) error {
+	return r.db.Delete(jadwal).Error
+}
+
+func (r *gormJadwalKonselorRepository) ListPengecualian(konselorID uint, dari, sampai time.Time) ([]models.PengecualianJadwal, error) {
+	var pengecualian []models.PengecualianJadwal
+	query := r.db.Where("tanggal >= ?", dari).Order("tanggal, jam_mulai")
+	if !sampai.IsZero() {
+		query = query.Where("tanggal < ?", sampai)
+	}
+	if konselorID != 0 {
+		query = query.Where("konselor_id = ?", konselorID)
+	}
+	err := query.Find(&pengecualian).Error
+	return pengecualian, err
+}
+
+func (r *gormJadwalKonselorRepository) FindPengecualian(id uint) (models.PengecualianJadwal, error) {
+	var pengecualian models.PengecualianJadwal
+	err := r.db.First(&pengecualian, id).Error
+	return pengecualian, translate(err)
+}
+
+func (r *gormJadwalKonselorRepository) CreatePengecualian(pengecualian *models.PengecualianJadwal) error {
+	return r.db.Create(pengecualian).Error
+}
+
+func (r *gormJadwalKonselorRepository) DeletePengecualian(pengecualian *models.PengecualianJadwal) error {
+	return r.db.Delete(pengecualian).Error
+}
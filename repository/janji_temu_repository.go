@@ -0,0 +1,343 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSlotTaken dikembalikan Book jika slot konselor sudah dipesan orang lain.
+var ErrSlotTaken = errors.New("slot janji temu sudah dipesan")
+
+type JanjiTemuRepository interface {
+	FindByID(id uint) (models.JanjiTemu, error)
+	// FindDetail ikut memuat user pembuat dan user yang menyetujui/menolak.
+	FindDetail(id uint) (models.JanjiTemu, error)
+	List() ([]models.JanjiTemu, error)
+	ListByUser(userID uint) ([]models.JanjiTemu, error)
+	// ListAktifByKonselor mengembalikan janji temu yang belum dibatalkan atau
+	// ditolak dan beririsan dengan rentang [dari, sampai); konselorID 0 berarti
+	// semua konselor.
+	ListAktifByKonselor(konselorID uint, dari, sampai time.Time) ([]models.JanjiTemu, error)
+	// ListKalender mengembalikan janji temu user, sebagai pembuat maupun
+	// konselor, yang perlu ada di feed kalendernya: yang disetujui atau sudah
+	// selesai, yang sudah disetujui lalu sedang menunggu jadwal ulang (tetap
+	// pada waktunya sekarang sampai usulannya diterima), serta yang ditolak
+	// atau dibatalkan sejak batalSejak supaya aplikasi kalender ikut
+	// menghapusnya.
+	ListKalender(userID uint, batalSejak time.Time) ([]models.JanjiTemu, error)
+	// ListDisetujui mengembalikan janji temu yang disetujui dan dimulai dalam
+	// rentang [dari, sampai).
+	ListDisetujui(dari, sampai time.Time) ([]models.JanjiTemu, error)
+	Create(janjiTemu *models.JanjiTemu) error
+	// Book menyimpan janji temu sekaligus mengunci slot konselornya dalam satu
+	// transaksi. ErrSlotTaken dikembalikan jika slot sudah dipesan atau
+	// waktunya beririsan dengan janji temu aktif konselor tersebut.
+	Book(janjiTemu *models.JanjiTemu) error
+	// HasSlot melaporkan apakah janji temu dipesan dari slot jadwal konselor.
+	HasSlot(janjiTemuID uint) (bool, error)
+	// FindClashes mengembalikan janji temu lain yang sudah disetujui (termasuk
+	// yang sedang menunggu jadwal ulang) dan waktunya beririsan dengan
+	// janjiTemu, baik karena konselornya sama maupun ruangannya sama.
+	FindClashes(janjiTemu models.JanjiTemu) ([]models.JanjiTemu, error)
+	// Approve memeriksa bentrokan lalu menyimpan janji temu yang disetujui
+	// dalam satu transaksi. Jika ada bentrokan, janji temu tidak disimpan dan
+	// daftar bentrokannya dikembalikan.
+	Approve(janjiTemu *models.JanjiTemu) ([]models.JanjiTemu, error)
+	// Save menyimpan perubahan janji temu. Jika statusnya menjadi dibatalkan
+	// atau ditolak, slot konselor yang dikunci ikut dilepas dan usulan jadwal
+	// ulang yang masih menunggu ikut ditolak.
+	Save(janjiTemu *models.JanjiTemu) error
+	// Complete menyimpan catatan konsultasi sekaligus status akhir janji temu.
+	Complete(janjiTemu *models.JanjiTemu, catatan *models.CatatanKonsultasi) error
+	FindCatatan(janjiTemuID uint) (models.CatatanKonsultasi, error)
+	// ListUsulanJadwal mengembalikan riwayat usulan jadwal ulang, yang lama
+	// lebih dulu.
+	ListUsulanJadwal(janjiTemuID uint) ([]models.UsulanJadwal, error)
+	// FindUsulanMenunggu mengembalikan usulan jadwal ulang yang belum
+	// ditanggapi, atau ErrNotFound.
+	FindUsulanMenunggu(janjiTemuID uint) (models.UsulanJadwal, error)
+	// ProposeJadwal menyimpan usulan baru beserta status janji temu. Usulan
+	// yang masih menunggu dianggap sudah dibalas oleh usulan baru.
+	ProposeJadwal(janjiTemu *models.JanjiTemu, usulan *models.UsulanJadwal) error
+	// RespondJadwal menyimpan tanggapan atas usulan beserta janji temunya.
+	// Jika janji temu kembali disetujui, bentrokan diperiksa seperti Approve.
+	RespondJadwal(janjiTemu *models.JanjiTemu, usulan *models.UsulanJadwal) ([]models.JanjiTemu, error)
+}
+
+type gormJanjiTemuRepository struct {
+	db *gorm.DB
+}
+
+func NewGormJanjiTemuRepository(db *gorm.DB) JanjiTemuRepository {
+	return &gormJanjiTemuRepository{db: db}
+}
+
+func (r *gormJanjiTemuRepository) FindByID(id uint) (models.JanjiTemu, error) {
+	var janjiTemu models.JanjiTemu
+	err := r.db.First(&janjiTemu, id).Error
+	return janjiTemu, translate(err)
+}
+
+func (r *gormJanjiTemuRepository) FindDetail(id uint) (models.JanjiTemu, error) {
+	var janjiTemu models.JanjiTemu
+	err := r.db.Preload("User").Preload("UserTolakSetujui", publikUser).Preload("Konselor", publikUser).First(&janjiTemu, id).Error
+	return janjiTemu, translate(err)
+}
+
+func (r *gormJanjiTemuRepository) List() ([]models.JanjiTemu, error) {
+	var janjiTemus []models.JanjiTemu
+	err := r.db.Preload("User").Preload("UserTolakSetujui", publikUser).Find(&janjiTemus).Error
+	return janjiTemus, err
+}
+
+func (r *gormJanjiTemuRepository) ListByUser(userID uint) ([]models.JanjiTemu, error) {
+	var janjiTemus []models.JanjiTemu
+	err := r.db.Preload("User").Preload("UserTolakSetujui", publikUser).Where("user_id = ?", userID).Find(&janjiTemus).Error
+	return janjiTemus, err
+}
+
+func (r *gormJanjiTemuRepository) ListAktifByKonselor(konselorID uint, dari, sampai time.Time) ([]models.JanjiTemu, error) {
+	var janjiTemus []models.JanjiTemu
+	query := r.db.Where("konselor_id IS NOT NULL").
+		Where("status NOT IN ?", []string{models.JanjiTemuDitolak, models.JanjiTemuDibatalkan}).
+		Where("waktu_dimulai < ? AND waktu_selesai > ?", sampai, dari)
+	if konselorID != 0 {
+		query = query.Where("konselor_id = ?", konselorID)
+	}
+	err := query.Find(&janjiTemus).Error
+	return janjiTemus, err
+}
+
+func (r *gormJanjiTemuRepository) ListKalender(userID uint, batalSejak time.Time) ([]models.JanjiTemu, error) {
+	var janjiTemus []models.JanjiTemu
+	dijadwalUlang := r.db.Model(&models.UsulanJadwal{}).
+		Select("janji_temu_id").
+		Where("status = ? AND status_sebelumnya = ?", models.UsulanMenunggu, models.JanjiTemuDisetujui)
+	err := r.db.Where("user_id = ? OR konselor_id = ?", userID, userID).
+		Where(r.db.Where("status IN ?", []string{models.JanjiTemuDisetujui, models.JanjiTemuSelesai, models.JanjiTemuTidakHadir}).
+			Or("status = ? AND id IN (?)", models.JanjiTemuJadwalUlang, dijadwalUlang).
+			Or("status IN ? AND updated_at >= ?", []string{models.JanjiTemuDitolak, models.JanjiTemuDibatalkan}, batalSejak)).
+		Order("waktu_dimulai").
+		Find(&janjiTemus).Error
+	return janjiTemus, err
+}
+
+func (r *gormJanjiTemuRepository) ListDisetujui(dari, sampai time.Time) ([]models.JanjiTemu, error) {
+	var janjiTemus []models.JanjiTemu
+	err := r.db.Where("status = ? AND waktu_dimulai >= ? AND waktu_dimulai < ?", models.JanjiTemuDisetujui, dari, sampai).
+		Order("waktu_dimulai").
+		Find(&janjiTemus).Error
+	return janjiTemus, err
+}
+
+func (r *gormJanjiTemuRepository) Create(janjiTemu *models.JanjiTemu) error {
+	return r.db.Create(janjiTemu).Error
+}
+
+func (r *gormJanjiTemuRepository) Book(janjiTemu *models.JanjiTemu) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockKonselor(tx, janjiTemu.KonselorID); err != nil {
+			return err
+		}
+		bentrok, err := exists(tx.Model(&models.JanjiTemu{}).
+			Where("konselor_id = ?", janjiTemu.KonselorID).
+			Where("status NOT IN ?", []string{models.JanjiTemuDitolak, models.JanjiTemuDibatalkan}).
+			Where("waktu_dimulai < ? AND waktu_selesai > ?", janjiTemu.WaktuSelesai, janjiTemu.WaktuDimulai))
+		if err != nil {
+			return err
+		}
+		if bentrok {
+			return ErrSlotTaken
+		}
+		if err := tx.Create(janjiTemu).Error; err != nil {
+			return err
+		}
+		// Unique index slot menjadi pengaman terakhir jika database tidak
+		// mendukung row lock.
+		slot := models.SlotJanjiTemu{
+			KonselorID:   *janjiTemu.KonselorID,
+			WaktuDimulai: janjiTemu.WaktuDimulai,
+			JanjiTemuID:  janjiTemu.ID,
+		}
+		if err := tx.Create(&slot).Error; err != nil {
+			if isDuplicateKey(tx, err) {
+				return ErrSlotTaken
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *gormJanjiTemuRepository) HasSlot(janjiTemuID uint) (bool, error) {
+	return exists(r.db.Model(&models.SlotJanjiTemu{}).Where("janji_temu_id = ?", janjiTemuID))
+}
+
+func (r *gormJanjiTemuRepository) FindClashes(janjiTemu models.JanjiTemu) ([]models.JanjiTemu, error) {
+	return findClashes(r.db, janjiTemu)
+}
+
+func (r *gormJanjiTemuRepository) Approve(janjiTemu *models.JanjiTemu) ([]models.JanjiTemu, error) {
+	var clashes []models.JanjiTemu
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockKonselor(tx, janjiTemu.KonselorID); err != nil {
+			return err
+		}
+		var err error
+		if clashes, err = findClashes(tx, *janjiTemu); err != nil || len(clashes) > 0 {
+			return err
+		}
+		return save(tx, janjiTemu)
+	})
+	return clashes, err
+}
+
+func (r *gormJanjiTemuRepository) Save(janjiTemu *models.JanjiTemu) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return save(tx, janjiTemu)
+	})
+}
+
+func (r *gormJanjiTemuRepository) Complete(janjiTemu *models.JanjiTemu, catatan *models.CatatanKonsultasi) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(catatan).Error; err != nil {
+			return err
+		}
+		return save(tx, janjiTemu)
+	})
+}
+
+func (r *gormJanjiTemuRepository) FindCatatan(janjiTemuID uint) (models.CatatanKonsultasi, error) {
+	var catatan models.CatatanKonsultasi
+	err := r.db.Where("janji_temu_id = ?", janjiTemuID).First(&catatan).Error
+	return catatan, translate(err)
+}
+
+func (r *gormJanjiTemuRepository) ListUsulanJadwal(janjiTemuID uint) ([]models.UsulanJadwal, error) {
+	var usulan []models.UsulanJadwal
+	err := r.db.Where("janji_temu_id = ?", janjiTemuID).Order("created_at, id").Find(&usulan).Error
+	return usulan, err
+}
+
+func (r *gormJanjiTemuRepository) FindUsulanMenunggu(janjiTemuID uint) (models.UsulanJadwal, error) {
+	var usulan models.UsulanJadwal
+	err := r.db.Where("janji_temu_id = ? AND status = ?", janjiTemuID, models.UsulanMenunggu).
+		Order("id DESC").First(&usulan).Error
+	return usulan, translate(err)
+}
+
+func (r *gormJanjiTemuRepository) ProposeJadwal(janjiTemu *models.JanjiTemu, usulan *models.UsulanJadwal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.UsulanJadwal{}).
+			Where("janji_temu_id = ? AND status = ?", janjiTemu.ID, models.UsulanMenunggu).
+			Updates(map[string]interface{}{
+				"status":             models.UsulanDibalas,
+				"ditanggapi_oleh_id": usulan.DiusulkanOlehID,
+				"ditanggapi_pada":    time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(usulan).Error; err != nil {
+			return err
+		}
+		return save(tx, janjiTemu)
+	})
+}
+
+func (r *gormJanjiTemuRepository) RespondJadwal(janjiTemu *models.JanjiTemu, usulan *models.UsulanJadwal) ([]models.JanjiTemu, error) {
+	var clashes []models.JanjiTemu
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockKonselor(tx, janjiTemu.KonselorID); err != nil {
+			return err
+		}
+		if janjiTemu.Status == models.JanjiTemuDisetujui {
+			var err error
+			if clashes, err = findClashes(tx, *janjiTemu); err != nil || len(clashes) > 0 {
+				return err
+			}
+		}
+		if err := tx.Save(usulan).Error; err != nil {
+			return err
+		}
+		return save(tx, janjiTemu)
+	})
+	return clashes, err
+}
+
+func findClashes(db *gorm.DB, janjiTemu models.JanjiTemu) ([]models.JanjiTemu, error) {
+	var clashes []models.JanjiTemu
+	if janjiTemu.KonselorID == nil && janjiTemu.Ruangan == "" {
+		return clashes, nil
+	}
+	sameResource := db.Where("1 = 0")
+	if janjiTemu.KonselorID != nil {
+		sameResource = sameResource.Or("konselor_id = ?", *janjiTemu.KonselorID)
+	}
+	if janjiTemu.Ruangan != "" {
+		sameResource = sameResource.Or("ruangan = ?", janjiTemu.Ruangan)
+	}
+	// Janji temu yang menunggu jadwal ulang masih memakai waktunya sekarang
+	// sampai usulannya diterima, sehingga tetap dihitung.
+	err := db.Where("id <> ? AND status IN ?", janjiTemu.ID, []string{models.JanjiTemuDisetujui, models.JanjiTemuJadwalUlang}).
+		Where("waktu_dimulai < ? AND waktu_selesai > ?", janjiTemu.WaktuSelesai, janjiTemu.WaktuDimulai).
+		Where(sameResource).
+		Order("waktu_dimulai").
+		Find(&clashes).Error
+	return clashes, err
+}
+
+// lockKonselor mengunci baris user konselor dengan SELECT ... FOR UPDATE
+// sampai transaksi selesai. Semua transaksi yang memeriksa lalu mengubah
+// jadwal seorang konselor (Book, Approve, RespondJadwal, save) berjalan
+// bergantian, sehingga dua janji temu yang beririsan dengan waktu mulai
+// berbeda tidak bisa sama-sama lolos pengecekan. SQLite mengabaikan klausa
+// ini, tetapi di sana transaksi tulis memang sudah berjalan satu per satu.
+func lockKonselor(tx *gorm.DB, konselorID *uint) error {
+	if konselorID == nil {
+		return nil
+	}
+	var konselor []models.User
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", *konselorID).
+		Find(&konselor).Error
+}
+
+// save juga menyesuaikan kunci slot: dilepas jika janji temu tidak aktif lagi,
+// atau dipindah jika konselor atau waktunya berubah.
+func save(tx *gorm.DB, janjiTemu *models.JanjiTemu) error {
+	if err := lockKonselor(tx, janjiTemu.KonselorID); err != nil {
+		return err
+	}
+	if err := tx.Save(janjiTemu).Error; err != nil {
+		return err
+	}
+	if !janjiTemu.Aktif() {
+		err := tx.Model(&models.UsulanJadwal{}).
+			Where("janji_temu_id = ? AND status = ?", janjiTemu.ID, models.UsulanMenunggu).
+			Update("status", models.UsulanDitolak).Error
+		if err != nil {
+			return err
+		}
+	}
+	slots := tx.Model(&models.SlotJanjiTemu{}).Where("janji_temu_id = ?", janjiTemu.ID)
+	if !janjiTemu.Aktif() || janjiTemu.KonselorID == nil {
+		return slots.Delete(&models.SlotJanjiTemu{}).Error
+	}
+	err := slots.Updates(map[string]interface{}{
+		"konselor_id":   *janjiTemu.KonselorID,
+		"waktu_dimulai": janjiTemu.WaktuDimulai,
+	}).Error
+	if err != nil {
+		if isDuplicateKey(tx, err) {
+			return ErrSlotTaken
+		}
+		return err
+	}
+	return nil
+}
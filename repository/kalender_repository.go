@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+
+	"gorm.io/gorm"
+)
+
+type KalenderTokenRepository interface {
+	// Replace menghapus token feed lama milik user lalu menyimpan token baru,
+	// sehingga URL feed lama langsung tidak berlaku.
+	Replace(token *models.KalenderToken) error
+	// FindByHash ikut memuat user pemilik token.
+	FindByHash(tokenHash string) (models.KalenderToken, error)
+	DeleteByUser(userID uint) error
+}
+
+type gormKalenderTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewGormKalenderTokenRepository(db *gorm.DB) KalenderTokenRepository {
+	return &gormKalenderTokenRepository{db: db}
+}
+
+func (r *gormKalenderTokenRepository) Replace(token *models.KalenderToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", token.UserID).Delete(&models.KalenderToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (r *gormKalenderTokenRepository) FindByHash(tokenHash string) (models.KalenderToken, error) {
+	var token models.KalenderToken
+	err := r.db.Preload("User").Where("token_hash = ?", tokenHash).First(&token).Error
+	return token, translate(err)
+}
+
+func (r *gormKalenderTokenRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.KalenderToken{}).Error
+}
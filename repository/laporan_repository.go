@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+
+	"gorm.io/gorm"
+)
+
+type LaporanRepository interface {
+	FindByNoRegistrasi(noRegistrasi string) (models.Laporan, error)
+	// FindDetail sama dengan FindByNoRegistrasi tetapi ikut memuat pelapor
+	// dan kategori kekerasan.
+	FindDetail(noRegistrasi string) (models.Laporan, error)
+	Latest(limit int) ([]models.Laporan, error)
+	ListByUser(userID uint) ([]models.Laporan, error)
+	NoRegistrasiExists(noRegistrasi string) (bool, error)
+	Create(laporan *models.Laporan) error
+	Save(laporan *models.Laporan) error
+}
+
+type TrackingLaporanRepository interface {
+	FindByID(id uint) (models.TrackingLaporan, error)
+	ListByNoRegistrasi(noRegistrasi string) ([]models.TrackingLaporan, error)
+	Create(tracking *models.TrackingLaporan) error
+	Save(tracking *models.TrackingLaporan) error
+	Delete(tracking *models.TrackingLaporan) error
+}
+
+type KorbanRepository interface {
+	FindByID(id uint) (models.Korban, error)
+	ListByNoRegistrasi(noRegistrasi string) ([]models.Korban, error)
+	Create(korban *models.Korban) error
+	Save(korban *models.Korban) error
+	Delete(korban *models.Korban) error
+}
+
+type PelakuRepository interface {
+	FindByID(id uint) (models.Pelaku, error)
+	ListByNoRegistrasi(noRegistrasi string) ([]models.Pelaku, error)
+	Create(pelaku *models.Pelaku) error
+	Save(pelaku *models.Pelaku) error
+	Delete(pelaku *models.Pelaku) error
+}
+
+type gormLaporanRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLaporanRepository(db *gorm.DB) LaporanRepository {
+	return &gormLaporanRepository{db: db}
+}
+
+func (r *gormLaporanRepository) FindByNoRegistrasi(noRegistrasi string) (models.Laporan, error) {
+	var laporan models.Laporan
+	err := r.db.Where("no_registrasi = ?", noRegistrasi).First(&laporan).Error
+	return laporan, translate(err)
+}
+
+func (r *gormLaporanRepository) FindDetail(noRegistrasi string) (models.Laporan, error) {
+	var laporan models.Laporan
+	err := r.db.
+		Preload("User").
+		Preload("ViolenceCategory").
+		Where("no_registrasi = ?", noRegistrasi).
+		First(&laporan).Error
+	return laporan, translate(err)
+}
+
+func (r *gormLaporanRepository) Latest(limit int) ([]models.Laporan, error) {
+	var reports []models.Laporan
+	err := r.db.
+		Preload("ViolenceCategory").
+		Order("created_at desc").
+		Limit(limit).
+		Find(&reports).Error
+	return reports, err
+}
+
+func (r *gormLaporanRepository) ListByUser(userID uint) ([]models.Laporan, error) {
+	var reports []models.Laporan
+	err := r.db.Preload("ViolenceCategory").Where("user_id = ?", userID).Find(&reports).Error
+	return reports, err
+}
+
+func (r *gormLaporanRepository) NoRegistrasiExists(noRegistrasi string) (bool, error) {
+	return exists(r.db.Model(&models.Laporan{}).Where("no_registrasi = ?", noRegistrasi))
+}
+
+func (r *gormLaporanRepository) Create(laporan *models.Laporan) error {
+	return r.db.Create(laporan).Error
+}
+
+func (r *gormLaporanRepository) Save(laporan *models.Laporan) error {
+	return r.db.Save(laporan).Error
+}
+
+type gormTrackingLaporanRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTrackingLaporanRepository(db *gorm.DB) TrackingLaporanRepository {
+	return &gormTrackingLaporanRepository{db: db}
+}
+
+func (r *gormTrackingLaporanRepository) FindByID(id uint) (models.TrackingLaporan, error) {
+	var tracking models.TrackingLaporan
+	err := r.db.First(&tracking, id).Error
+	return tracking, translate(err)
+}
+
+func (r *gormTrackingLaporanRepository) ListByNoRegistrasi(noRegistrasi string) ([]models.TrackingLaporan, error) {
+	var trackings []models.TrackingLaporan
+	err := r.db.Where("no_registrasi = ?", noRegistrasi).Find(&trackings).Error
+	return trackings, err
+}
+
+func (r *gormTrackingLaporanRepository) Create(tracking *models.TrackingLaporan) error {
+	return r.db.Create(tracking).Error
+}
+
+func (r *gormTrackingLaporanRepository) Save(tracking *models.TrackingLaporan) error {
+	return r.db.Save(tracking).Error
+}
+
+func (r *gormTrackingLaporanRepository) Delete(tracking *models.TrackingLaporan) error {
+	return r.db.Delete(tracking).Error
+}
+
+type gormKorbanRepository struct {
+	db *gorm.DB
+}
+
+func NewGormKorbanRepository(db *gorm.DB) KorbanRepository {
+	return &gormKorbanRepository{db: db}
+}
+
+func (r *gormKorbanRepository) FindByID(id uint) (models.Korban, error) {
+	var korban models.Korban
+	err := r.db.First(&korban, id).Error
+	return korban, translate(err)
+}
+
+func (r *gormKorbanRepository) ListByNoRegistrasi(noRegistrasi string) ([]models.Korban, error) {
+	var korban []models.Korban
+	err := r.db.Where("no_registrasi = ?", noRegistrasi).Find(&korban).Error
+	return korban, err
+}
+
+func (r *gormKorbanRepository) Create(korban *models.Korban) error {
+	return r.db.Create(korban).Error
+}
+
+func (r *gormKorbanRepository) Save(korban *models.Korban) error {
+	return r.db.Save(korban).Error
+}
+
+func (r *gormKorbanRepository) Delete(korban *models.Korban) error {
+	return r.db.Delete(korban).Error
+}
+
+type gormPelakuRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPelakuRepository(db *gorm.DB) PelakuRepository {
+	return &gormPelakuRepository{db: db}
+}
+
+func (r *gormPelakuRepository) FindByID(id uint) (models.Pelaku, error) {
+	var pelaku models.Pelaku
+	err := r.db.First(&pelaku, id).Error
+	return pelaku, translate(err)
+}
+
+func (r *gormPelakuRepository) ListByNoRegistrasi(noRegistrasi string) ([]models.Pelaku, error) {
+	var pelaku []models.Pelaku
+	err := r.db.Where("no_registrasi = ?", noRegistrasi).Find(&pelaku).Error
+	return pelaku, err
+}
+
+func (r *gormPelakuRepository) Create(pelaku *models.Pelaku) error {
+	return r.db.Create(pelaku).Error
+}
+
+func (r *gormPelakuRepository) Save(pelaku *models.Pelaku) error {
+	return r.db.Save(pelaku).Error
+}
+
+func (r *gormPelakuRepository) Delete(pelaku *models.Pelaku) error {
+	return r.db.Delete(pelaku).Error
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginAttemptFilter struct {
+	UserID    uint
+	IPAddress string
+	Success   *bool
+}
+
+type LoginAttemptRepository interface {
+	Create(attempt *models.LoginAttempt) error
+	CountFailuresByIP(ip string, since time.Time) (int64, error)
+	LastFailureByIP(ip string) (models.LoginAttempt, error)
+	List(filter LoginAttemptFilter, page Page) ([]models.LoginAttempt, int64, error)
+}
+
+type gormLoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &gormLoginAttemptRepository{db: db}
+}
+
+func (r *gormLoginAttemptRepository) Create(attempt *models.LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+func (r *gormLoginAttemptRepository) CountFailuresByIP(ip string, since time.Time) (int64, error) {
+	var failures int64
+	err := r.db.Model(&models.LoginAttempt{}).
+		Where("ip_address = ? AND success = ? AND created_at > ?", ip, false, since).
+		Count(&failures).Error
+	return failures, err
+}
+
+func (r *gormLoginAttemptRepository) LastFailureByIP(ip string) (models.LoginAttempt, error) {
+	var last models.LoginAttempt
+	err := r.db.Where("ip_address = ? AND success = ?", ip, false).Order("created_at desc").First(&last).Error
+	return last, translate(err)
+}
+
+func (r *gormLoginAttemptRepository) List(filter LoginAttemptFilter, page Page) ([]models.LoginAttempt, int64, error) {
+	query := r.db.Model(&models.LoginAttempt{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var attempts []models.LoginAttempt
+	err := query.Order("created_at desc").Offset(page.offset()).Limit(page.Limit).Find(&attempts).Error
+	return attempts, total, err
+}
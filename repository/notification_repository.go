@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	// ListByUser mengembalikan notifikasi terbaru lebih dulu beserta jumlah
+	// totalnya.
+	ListByUser(userID uint, unreadOnly bool, page Page) ([]models.Notification, int64, error)
+	CountUnread(userID uint) (int64, error)
+	// MarkRead mengembalikan ErrNotFound jika notifikasi bukan milik user.
+	// Notifikasi yang sudah dibaca tidak diubah waktu bacanya.
+	MarkRead(userID, id uint, now time.Time) (models.Notification, error)
+	MarkAllRead(userID uint, now time.Time) (int64, error)
+}
+
+type gormNotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &gormNotificationRepository{db: db}
+}
+
+func (r *gormNotificationRepository) Create(notification *models.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r *gormNotificationRepository) ListByUser(userID uint, unreadOnly bool, page Page) ([]models.Notification, int64, error) {
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var notifications []models.Notification
+	err := query.Order("created_at DESC, id DESC").
+		Offset(page.offset()).Limit(page.Limit).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *gormNotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *gormNotificationRepository) MarkRead(userID, id uint, now time.Time) (models.Notification, error) {
+	var notification models.Notification
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return notification, translate(err)
+	}
+	if notification.ReadAt != nil {
+		return notification, nil
+	}
+	notification.ReadAt = &now
+	err := r.db.Model(&notification).Update("read_at", now).Error
+	return notification, err
+}
+
+func (r *gormNotificationRepository) MarkAllRead(userID uint, now time.Time) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", now)
+	return result.RowsAffected, result.Error
+}
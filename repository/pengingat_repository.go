@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PengingatRepository interface {
+	// Enqueue menyimpan pengingat yang belum ada. Pengingat dengan Kunci yang
+	// sudah tersimpan diabaikan, sehingga aman dipanggil setiap putaran.
+	Enqueue(pengingat []models.Pengingat) error
+	// Due mengembalikan pengingat yang masih menunggu dan waktunya sudah tiba,
+	// yang paling lama lebih dulu.
+	Due(now time.Time, limit int) ([]models.Pengingat, error)
+	// Claim menunda KirimPada pengingat sampai until, hanya jika belum diambil
+	// proses lain. Jika proses mati di tengah pengiriman, pengingat akan
+	// dicoba lagi setelah until.
+	Claim(pengingat *models.Pengingat, until time.Time) (bool, error)
+	Save(pengingat *models.Pengingat) error
+	List(userID uint) ([]models.Pengingat, error)
+	// ListPenerimaEvent mengembalikan akun masyarakat aktif yang memilih
+	// menerima pengingat event.
+	ListPenerimaEvent() ([]models.User, error)
+}
+
+type gormPengingatRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPengingatRepository(db *gorm.DB) PengingatRepository {
+	return &gormPengingatRepository{db: db}
+}
+
+func (r *gormPengingatRepository) Enqueue(pengingat []models.Pengingat) error {
+	if len(pengingat) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&pengingat, 100).Error
+}
+
+func (r *gormPengingatRepository) Due(now time.Time, limit int) ([]models.Pengingat, error) {
+	var pengingat []models.Pengingat
+	err := r.db.Where("status = ? AND kirim_pada <= ?", models.PengingatMenunggu, now).
+		Order("kirim_pada, id").
+		Limit(limit).
+		Find(&pengingat).Error
+	return pengingat, err
+}
+
+func (r *gormPengingatRepository) Claim(pengingat *models.Pengingat, until time.Time) (bool, error) {
+	result := r.db.Model(&models.Pengingat{}).
+		Where("id = ? AND status = ? AND kirim_pada = ?", pengingat.ID, models.PengingatMenunggu, pengingat.KirimPada).
+		Update("kirim_pada", until)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	pengingat.KirimPada = until
+	return true, nil
+}
+
+func (r *gormPengingatRepository) Save(pengingat *models.Pengingat) error {
+	return r.db.Save(pengingat).Error
+}
+
+func (r *gormPengingatRepository) List(userID uint) ([]models.Pengingat, error) {
+	var pengingat []models.Pengingat
+	err := r.db.Where("user_id = ?", userID).Order("kirim_pada, id").Find(&pengingat).Error
+	return pengingat, err
+}
+
+func (r *gormPengingatRepository) ListPenerimaEvent() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("role = ? AND is_suspended = ? AND pengingat_event = ?", models.RoleMasyarakat, false, true).Find(&users).Error
+	return users, err
+}
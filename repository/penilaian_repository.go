@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrSudahDinilai dikembalikan Create jika janji temu atau laporan yang sama
+// sudah pernah dinilai.
+var ErrSudahDinilai = errors.New("layanan sudah dinilai")
+
+type PenilaianLayananRepository interface {
+	Create(penilaian *models.PenilaianLayanan) error
+	FindByJanjiTemu(janjiTemuID uint) (models.PenilaianLayanan, error)
+	FindByLaporan(noRegistrasi string) (models.PenilaianLayanan, error)
+	// List mengembalikan penilaian yang dibuat dalam rentang [dari, sampai),
+	// yang lama lebih dulu. Waktu nol berarti rentangnya tidak dibatasi.
+	List(dari, sampai time.Time) ([]models.PenilaianLayanan, error)
+}
+
+type gormPenilaianLayananRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPenilaianLayananRepository(db *gorm.DB) PenilaianLayananRepository {
+	return &gormPenilaianLayananRepository{db: db}
+}
+
+func (r *gormPenilaianLayananRepository) Create(penilaian *models.PenilaianLayanan) error {
+	err := r.db.Create(penilaian).Error
+	if err != nil && isDuplicateKey(r.db, err) {
+		return ErrSudahDinilai
+	}
+	return err
+}
+
+func (r *gormPenilaianLayananRepository) FindByJanjiTemu(janjiTemuID uint) (models.PenilaianLayanan, error) {
+	var penilaian models.PenilaianLayanan
+	err := r.db.Where("janji_temu_id = ?", janjiTemuID).First(&penilaian).Error
+	return penilaian, translate(err)
+}
+
+func (r *gormPenilaianLayananRepository) FindByLaporan(noRegistrasi string) (models.PenilaianLayanan, error) {
+	var penilaian models.PenilaianLayanan
+	err := r.db.Where("no_registrasi = ?", noRegistrasi).First(&penilaian).Error
+	return penilaian, translate(err)
+}
+
+func (r *gormPenilaianLayananRepository) List(dari, sampai time.Time) ([]models.PenilaianLayanan, error) {
+	query := r.db.Order("created_at, id")
+	if !dari.IsZero() {
+		query = query.Where("created_at >= ?", dari)
+	}
+	if !sampai.IsZero() {
+		query = query.Where("created_at < ?", sampai)
+	}
+	var penilaian []models.PenilaianLayanan
+	err := query.Find(&penilaian).Error
+	return penilaian, err
+}
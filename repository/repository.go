@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound dikembalikan semua repository jika data yang dicari tidak ada,
+// sehingga handler tidak perlu bergantung pada error milik GORM.
+var ErrNotFound = errors.New("record not found")
+
+// Repositories mengelompokkan semua repository per aggregate. Handler dan
+// middleware menerima struct ini lewat constructor, bukan memakai koneksi
+// database global.
+type Repositories struct {
+	Users             UserRepository
+	OTP               OTPRepository
+	TwoFactor         TwoFactorRepository
+	PasswordResets    PasswordResetRepository
+	LoginAttempts     LoginAttemptRepository
+	Laporan           LaporanRepository
+	Tracking          TrackingLaporanRepository
+	Korban            KorbanRepository
+	Pelaku            PelakuRepository
+	ViolenceCategory  ViolenceCategoryRepository
+	EmergencyContacts EmergencyContactRepository
+	Contents          ContentRepository
+	Events            EventRepository
+	JanjiTemu         JanjiTemuRepository
+	JadwalKonselor    JadwalKonselorRepository
+	KalenderTokens    KalenderTokenRepository
+	Pengingat         PengingatRepository
+	PenilaianLayanan  PenilaianLayananRepository
+	Notifications     NotificationRepository
+}
+
+// NewGormRepositories membuat semua repository dengan implementasi GORM di
+// atas koneksi yang sama. Bisa dipakai untuk MySQL maupun SQLite.
+func NewGormRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Users:             NewGormUserRepository(db),
+		OTP:               NewGormOTPRepository(db),
+		TwoFactor:         NewGormTwoFactorRepository(db),
+		PasswordResets:    NewGormPasswordResetRepository(db),
+		LoginAttempts:     NewGormLoginAttemptRepository(db),
+		Laporan:           NewGormLaporanRepository(db),
+		Tracking:          NewGormTrackingLaporanRepository(db),
+		Korban:            NewGormKorbanRepository(db),
+		Pelaku:            NewGormPelakuRepository(db),
+		ViolenceCategory:  NewGormViolenceCategoryRepository(db),
+		EmergencyContacts: NewGormEmergencyContactRepository(db),
+		Contents:          NewGormContentRepository(db),
+		Events:            NewGormEventRepository(db),
+		JanjiTemu:         NewGormJanjiTemuRepository(db),
+		JadwalKonselor:    NewGormJadwalKonselorRepository(db),
+		KalenderTokens:    NewGormKalenderTokenRepository(db),
+		Pengingat:         NewGormPengingatRepository(db),
+		PenilaianLayanan:  NewGormPenilaianLayananRepository(db),
+		Notifications:     NewGormNotificationRepository(db),
+	}
+}
+
+// Page dipakai untuk query yang mendukung paginasi. Page dimulai dari 1.
+type Page struct {
+	Page  int
+	Limit int
+}
+
+func (p Page) offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// publikUser membatasi preload akun petugas ke kolom yang boleh dilihat
+// masyarakat, sehingga password, kontak, dan NIK tidak ikut di respons.
+func publikUser(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "full_name", "username", "role", "photo_profile")
+}
+
+func translate(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// isDuplicateKey memeriksa pelanggaran unique index tanpa menyalakan
+// TranslateError untuk seluruh koneksi, karena sebagian handler masih membaca
+// pesan error asli dari driver.
+func isDuplicateKey(db *gorm.DB, err error) bool {
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+func exists(query *gorm.DB) (bool, error) {
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,49 @@
+// Package repotest menyediakan database SQLite in-memory yang sudah
+// dimigrasi untuk test. Setiap pemanggilan Open mendapat database terpisah
+// yang otomatis ditutup saat test selesai.
+package repotest
+
+import (
+	"backend-pedika-fiber/migration"
+	"backend-pedika-fiber/repository"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var counter int64
+
+// Open membuat database SQLite in-memory baru dan menjalankan semua migration.
+func Open(tb testing.TB) *gorm.DB {
+	tb.Helper()
+	dsn := fmt.Sprintf("file:repotest_%d?mode=memory&cache=shared&_foreign_keys=1", atomic.AddInt64(&counter, 1))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		tb.Fatalf("repotest: failed to open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatalf("repotest: failed to get sql.DB: %v", err)
+	}
+	// Sama dengan database.OpenSQLite: SQLite hanya mengizinkan satu penulis,
+	// jadi request yang berjalan bersamaan harus antre di satu koneksi.
+	sqlDB.SetMaxOpenConns(1)
+	tb.Cleanup(func() { sqlDB.Close() })
+
+	if err := migration.Up(db); err != nil {
+		tb.Fatalf("repotest: %v", err)
+	}
+	return db
+}
+
+// New mengembalikan semua repository GORM di atas database dari Open.
+func New(tb testing.TB) *repository.Repositories {
+	tb.Helper()
+	return repository.NewGormRepositories(Open(tb))
+}
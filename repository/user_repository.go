@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"backend-pedika-fiber/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserFilter struct {
+	// Search dicocokkan ke nama, username, email, dan nomor telepon.
+	Search    string
+	Role      string
+	Suspended *bool
+}
+
+type UserRepository interface {
+	FindByID(id uint) (models.User, error)
+	FindByEmail(email string) (models.User, error)
+	FindByPhoneNumber(phoneNumber string) (models.User, error)
+	// FindByLogin mencari user berdasarkan salah satu identitas login yang
+	// tidak kosong: email, username, atau nomor telepon.
+	FindByLogin(email, username, phoneNumber string) (models.User, error)
+	EmailExists(email string) (bool, error)
+	PhoneNumberExists(phoneNumber string) (bool, error)
+	UsernameExists(username string) (bool, error)
+	List(filter UserFilter, page Page) ([]models.User, int64, error)
+	ListLocked(now time.Time) ([]models.User, error)
+	// ListByIDs memuat beberapa user sekaligus. ID yang tidak ditemukan
+	// dilewati tanpa error.
+	ListByIDs(ids []uint) ([]models.User, error)
+	Create(user *models.User) error
+	Save(user *models.User) error
+	Delete(user *models.User) error
+	UpdatePassword(id uint, hashedPassword string) error
+
+	// ClaimLoginAttempt mencatat satu percobaan login sebagai gagal sebelum
+	// password diperiksa, dalam satu UPDATE bersyarat: hanya berhasil jika
+	// counter masih failedCount dan akun tidak terkunci. Dari beberapa
+	// percobaan paralel yang membaca counter yang sama hanya satu yang lolos.
+	ClaimLoginAttempt(id uint, failedCount int, now time.Time) (bool, error)
+	// ReleaseLoginAttempt membatalkan klaim ClaimLoginAttempt ketika password
+	// ternyata benar tetapi login belum selesai (misalnya menunggu kode 2FA).
+	ReleaseLoginAttempt(id uint) error
+	// LockIfExceeded mengunci akun sampai lockedUntil jika counter gagal login
+	// sudah mencapai lockThreshold.
+	LockIfExceeded(id uint, lockThreshold int, lockedUntil time.Time) error
+	ResetFailedLogins(id uint) error
+}
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+func NewGormUserRepository(db *gorm.DB) UserRepository {
+	return &gormUserRepository{db: db}
+}
+
+func (r *gormUserRepository) FindByID(id uint) (models.User, error) {
+	var user models.User
+	err := r.db.First(&user, id).Error
+	return user, translate(err)
+}
+
+func (r *gormUserRepository) FindByEmail(email string) (models.User, error) {
+	var user models.User
+	err := r.db.Where("email = ?", email).First(&user).Error
+	return user, translate(err)
+}
+
+func (r *gormUserRepository) FindByPhoneNumber(phoneNumber string) (models.User, error) {
+	var user models.User
+	err := r.db.Where("phone_number = ?", phoneNumber).First(&user).Error
+	return user, translate(err)
+}
+
+func (r *gormUserRepository) FindByLogin(email, username, phoneNumber string) (models.User, error) {
+	var user models.User
+	query := r.db.Model(&models.User{})
+	conditions := 0
+	for column, value := range map[string]string{"email": email, "username": username, "phone_number": phoneNumber} {
+		if value == "" {
+			continue
+		}
+		if conditions == 0 {
+			query = query.Where(column+" = ?", value)
+		} else {
+			query = query.Or(column+" = ?", value)
+		}
+		conditions++
+	}
+	if conditions == 0 {
+		return user, ErrNotFound
+	}
+	err := query.First(&user).Error
+	return user, translate(err)
+}
+
+func (r *gormUserRepository) EmailExists(email string) (bool, error) {
+	return exists(r.db.Model(&models.User{}).Where("email = ?", email))
+}
+
+func (r *gormUserRepository) PhoneNumberExists(phoneNumber string) (bool, error) {
+	return exists(r.db.Model(&models.User{}).Where("phone_number = ?", phoneNumber))
+}
+
+func (r *gormUserRepository) UsernameExists(username string) (bool, error) {
+	return exists(r.db.Model(&models.User{}).Where("username = ?", username))
+}
+
+func (r *gormUserRepository) List(filter UserFilter, page Page) ([]models.User, int64, error) {
+	query := r.db.Model(&models.User{})
+	if filter.Search != "" {
+		like := "%" + filter.Search + "%"
+		query = query.Where("full_name LIKE ? OR username LIKE ? OR email LIKE ? OR phone_number LIKE ?", like, like, like, like)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Suspended != nil {
+		query = query.Where("is_suspended = ?", *filter.Suspended)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []models.User
+	err := query.Order("created_at desc").Offset(page.offset()).Limit(page.Limit).Find(&users).Error
+	return users, total, err
+}
+
+func (r *gormUserRepository) ListLocked(now time.Time) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("locked_until > ?", now).Order("locked_until desc").Find(&users).Error
+	return users, err
+}
+
+func (r *gormUserRepository) ListByIDs(ids []uint) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+func (r *gormUserRepository) Create(user *models.User) error {
+	// Tanggal lahir kosong tidak bisa disimpan sebagai zero date di MySQL strict mode.
+	query := r.db
+	if user.TanggalLahir.IsZero() {
+		query = query.Omit("TanggalLahir")
+	}
+	return query.Create(user).Error
+}
+
+func (r *gormUserRepository) Save(user *models.User) error {
+	return r.db.Save(user).Error
+}
+
+func (r *gormUserRepository) Delete(user *models.User) error {
+	return r.db.Delete(user).Error
+}
+
+func (r *gormUserRepository) UpdatePassword(id uint, hashedPassword string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":   hashedPassword,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+func (r *gormUserRepository) ClaimLoginAttempt(id uint, failedCount int, now time.Time) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND failed_login_count = ?", id, failedCount).
+		Where("locked_until IS NULL OR locked_until <= ?", now).
+		Updates(map[string]interface{}{
+			"failed_login_count":   gorm.Expr("failed_login_count + 1"),
+			"last_failed_login_at": now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *gormUserRepository) ReleaseLoginAttempt(id uint) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ? AND failed_login_count > 0", id).
+		Update("failed_login_count", gorm.Expr("failed_login_count - 1")).Error
+}
+
+func (r *gormUserRepository) LockIfExceeded(id uint, lockThreshold int, lockedUntil time.Time) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ? AND failed_login_count >= ?", id, lockThreshold).
+		Updates(map[string]interface{}{
+			"locked_until":       lockedUntil,
+			"failed_login_count": 0,
+		}).Error
+}
+
+func (r *gormUserRepository) ResetFailedLogins(id uint) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_login_count":   0,
+		"last_failed_login_at": nil,
+		"locked_until":         nil,
+	}).Error
+}
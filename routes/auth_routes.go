@@ -1,15 +1,22 @@
 package routes
 
 import (
+	"backend-pedika-fiber/config"
 	"backend-pedika-fiber/handlers"
+	"backend-pedika-fiber/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetAuthRoutes(app *fiber.App) {
-	userGroup := app.Group("/api/user")
+func SetAuthRoutes(app *fiber.App, cfg *config.Config, h *handlers.Handler, rl *middleware.RateLimiter) {
+	userGroup := app.Group("/api/user", rl.RateLimit(authRateLimit(cfg)))
 	{
-		userGroup.Post("/register", handlers.RegisterUser)
-		userGroup.Post("/login", handlers.LoginUser)
+		userGroup.Post("/register", h.RegisterUser)
+		userGroup.Post("/login", h.LoginUser)
+		userGroup.Post("/login/2fa", h.LoginTwoFactor)
+		userGroup.Post("/forgot-password", h.ForgotPassword)
+		userGroup.Post("/reset-password", h.ResetPassword)
+		userGroup.Post("/send-otp", h.SendOTP)
+		userGroup.Post("/verify-otp", h.VerifyOTP)
 	}
 }
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"backend-pedika-fiber/config"
+	"backend-pedika-fiber/ratelimit"
+)
+
+// Batas diatur lewat env RATE_LIMIT_* dengan format "<jumlah>/<durasi>".
+func authRateLimit(cfg *config.Config) ratelimit.Rule {
+	return ratelimit.NewRule("auth", cfg.RateLimit.Auth, ratelimit.KeyByIP)
+}
+
+func publicRateLimit(cfg *config.Config) ratelimit.Rule {
+	return ratelimit.NewRule("public", cfg.RateLimit.Public, ratelimit.KeyByIP)
+}
+
+func writeRateLimit(cfg *config.Config) ratelimit.Rule {
+	rule := ratelimit.NewRule("write", cfg.RateLimit.Write, ratelimit.KeyByUser)
+	rule.OnlyWrites = true
+	return rule
+}
+
+func uploadRateLimit(cfg *config.Config) ratelimit.Rule {
+	return ratelimit.NewRule("upload", cfg.RateLimit.Upload, ratelimit.KeyByUser)
+}
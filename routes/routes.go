@@ -1,118 +1,240 @@
 package routes
 
 import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/config"
 	"backend-pedika-fiber/handlers"
+	"backend-pedika-fiber/helper"
 	"backend-pedika-fiber/middleware"
+	"backend-pedika-fiber/ratelimit"
+	"backend-pedika-fiber/repository"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// NewApp membuat aplikasi Fiber dengan error handler dan pengaturan proxy
+// dari config. Dengan pengaturan ini c.IP() berisi IP client asli di balik
+// load balancer, bukan IP load balancer, selama request datang dari proxy
+// yang dipercaya.
+func NewApp(cfg *config.Config) *fiber.App {
+	return fiber.New(fiber.Config{
+		ErrorHandler:            helper.ErrorHandler,
+		ProxyHeader:             cfg.Proxy.Header,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.Proxy.TrustedProxies,
+		EnableIPValidation:      true,
+	})
+}
+
+// Setup membuat handler dan middleware dari repository, layanan luar dan
+// store rate limit yang diberikan lalu mendaftarkan semua endpoint. Dipakai
+// oleh main dan oleh test integrasi.
+func Setup(app *fiber.App, cfg *config.Config, repos *repository.Repositories, deps handlers.Deps, store ratelimit.Store) {
+	h := handlers.New(cfg, repos, deps)
+	mw := middleware.NewAuth(repos.Users, deps.JWT)
+	rl := middleware.NewRateLimiter(store)
+
+	SetAuthRoutes(app, cfg, h, rl)
+	SetAdminRoutes(app, cfg, h, mw, rl)
+	SetMasyarakatRoutes(app, cfg, h, mw, rl)
+	RoutesWithOutLogin(app, cfg, h, rl)
+}
+
 /*========= || Endpoint yang hanya bisa diakses oleh admin || ====================*/
-func SetAdminRoutes(app *fiber.App) {
+func SetAdminRoutes(app *fiber.App, cfg *config.Config, h *handlers.Handler, mw *middleware.Auth, rl *middleware.RateLimiter) {
 	adminGroup := app.Group("/api/admin")
-	adminGroup.Use(middleware.AdminMiddleware)
-
-	adminGroup.Get("/profile", handlers.GetUserProfile)
-	adminGroup.Put("/edit-profile", handlers.UpdateUserProfile)
-	adminGroup.Put("/change-password", handlers.ChangePassword)
-
-	adminGroup.Get("/emergency-contact", handlers.GetEmergencyContact)
-	adminGroup.Put("/emergency-contact-edit", handlers.UpdateEmergencyContact)
-
-	adminGroup.Get("/laporans", handlers.GetLatestReports)
-	adminGroup.Get("/detail-laporan/:no_registrasi", handlers.GetLaporanByNoRegistrasi)
-	adminGroup.Put("/lihat-laporan/:no_registrasi", handlers.AdminLihatLaporan)
-	adminGroup.Put("/proses-laporan/:no_registrasi", handlers.AdminProsesLaporan)
-	adminGroup.Put("laporan-selesai/:no_registrasi", handlers.SelesaikanLaporan)
-
-	adminGroup.Post("/create-tracking-laporan", handlers.CreateTrackingLaporan)
-	adminGroup.Delete("/delete-tracking-laporan/:id", handlers.DeleteTrackingLaporan)
-	adminGroup.Put("/edit-tracking-laporan/:id", handlers.UpdateTrackingLaporan)
-
-	adminGroup.Post("/create-pelaku-kekerasan", handlers.CreatePelaku)
-	adminGroup.Put("/edit-pelaku-kekerasan/:id", handlers.UpdatePelaku)
-	adminGroup.Delete("/delete-pelaku-kekerasan/:id", handlers.DeletePelaku)
-
-	adminGroup.Post("/create-korban-kekerasan", handlers.CreateKorban)
-	adminGroup.Put("/edit-korban-kekerasan/:id", handlers.UpdateKorban)
-
-	adminGroup.Get("/violence-categories", handlers.GetAllViolenceCategories)
-	adminGroup.Get("/detail-violence-category/:id", handlers.GetViolenceCategoryByID)
-	adminGroup.Post("/create-violence-category", handlers.CreateViolenceCategory)
-	adminGroup.Put("/edit-violence-category/:id", handlers.UpdateViolenceCategory)
-	adminGroup.Delete("/delete-violence-category/:id", handlers.DeleteViolenceCategory)
-
-	adminGroup.Get("/contents", handlers.GetAllContents)
-	adminGroup.Get("/detail-content/:id", handlers.GetContentByID)
-	adminGroup.Post("/create-content", handlers.CreateContent)
-	adminGroup.Put("/edit-content/:id", handlers.UpdateContent)
-	adminGroup.Delete("/delete-content/:id", handlers.DeleteContent)
-
-	adminGroup.Get("/event", handlers.GetAllEvent)
-	adminGroup.Get("/detail-event/:id", handlers.GetEventByID)
-	adminGroup.Post("/create-event", handlers.CreateEvent)
-	adminGroup.Put("/edit-event/:id", handlers.UpdateEvent)
-	adminGroup.Delete("/delete-event/:id", handlers.DeleteEvent)
-
-	adminGroup.Get("/janjitemus", handlers.AdminGetAllJanjiTemu)
-	adminGroup.Get("/detail-janjitemu/:id", handlers.AdminJanjiTemuByID)
-	adminGroup.Put("/approve-janjitemu/:id", handlers.AdminApproveJanjiTemu)
-	adminGroup.Put("/cancel-janjitemu/:id", handlers.AdminCancelJanjiTemu)
+	adminGroup.Use(mw.AdminMiddleware)
+	adminGroup.Use(rl.RateLimit(writeRateLimit(cfg)))
+	upload := rl.RateLimit(uploadRateLimit(cfg))
+
+	profile := middleware.RequirePermission(auth.PermProfile)
+	adminGroup.Get("/profile", profile, h.GetUserProfile)
+	adminGroup.Put("/edit-profile", profile, upload, h.UpdateUserProfile)
+	adminGroup.Put("/change-password", profile, h.ChangePassword)
+
+	adminGroup.Post("/2fa/setup", profile, h.TwoFactorSetup)
+	adminGroup.Post("/2fa/enable", profile, h.TwoFactorEnable)
+	adminGroup.Post("/2fa/disable", profile, h.TwoFactorDisable)
+	adminGroup.Post("/2fa/recovery-codes", profile, h.TwoFactorRegenerateRecoveryCodes)
+
+	adminGroup.Post("/kalender/token", profile, h.BuatTokenKalender)
+	adminGroup.Delete("/kalender/token", profile, h.HapusTokenKalender)
+
+	adminGroup.Get("/notifikasi", profile, h.GetNotifications)
+	adminGroup.Get("/notifikasi/belum-dibaca", profile, h.GetUnreadNotificationCount)
+	adminGroup.Put("/baca-notifikasi/:id", profile, h.ReadNotification)
+	adminGroup.Put("/baca-semua-notifikasi", profile, h.ReadAllNotifications)
+
+	adminGroup.Get("/emergency-contact", middleware.RequirePermission(auth.PermEmergencyContactRead), h.GetEmergencyContact)
+	adminGroup.Put("/emergency-contact-edit", middleware.RequirePermission(auth.PermEmergencyContactWrite), h.UpdateEmergencyContact)
+
+	laporanRead := middleware.RequirePermission(auth.PermLaporanRead)
+	laporanWrite := middleware.RequirePermission(auth.PermLaporanWrite)
+	adminGroup.Get("/laporans", laporanRead, h.GetLatestReports)
+	adminGroup.Get("/detail-laporan/:no_registrasi", laporanRead, h.GetLaporanByNoRegistrasi)
+	adminGroup.Put("/lihat-laporan/:no_registrasi", laporanWrite, h.AdminLihatLaporan)
+	adminGroup.Put("/proses-laporan/:no_registrasi", laporanWrite, h.AdminProsesLaporan)
+	adminGroup.Put("laporan-selesai/:no_registrasi", laporanWrite, h.SelesaikanLaporan)
+
+	adminGroup.Post("/create-tracking-laporan", laporanWrite, upload, h.CreateTrackingLaporan)
+	adminGroup.Delete("/delete-tracking-laporan/:id", laporanWrite, h.DeleteTrackingLaporan)
+	adminGroup.Put("/edit-tracking-laporan/:id", laporanWrite, upload, h.UpdateTrackingLaporan)
+
+	adminGroup.Post("/create-pelaku-kekerasan", laporanWrite, upload, h.CreatePelaku)
+	adminGroup.Put("/edit-pelaku-kekerasan/:id", laporanWrite, upload, h.UpdatePelaku)
+	adminGroup.Delete("/delete-pelaku-kekerasan/:id", laporanWrite, h.DeletePelaku)
+
+	adminGroup.Post("/create-korban-kekerasan", laporanWrite, upload, h.CreateKorban)
+	adminGroup.Put("/edit-korban-kekerasan/:id", laporanWrite, upload, h.UpdateKorban)
+
+	categoryRead := middleware.RequirePermission(auth.PermViolenceCategoryRead)
+	categoryWrite := middleware.RequirePermission(auth.PermViolenceCategoryWrite)
+	adminGroup.Get("/violence-categories", categoryRead, h.GetAllViolenceCategories)
+	adminGroup.Get("/detail-violence-category/:id", categoryRead, h.GetViolenceCategoryByID)
+	adminGroup.Post("/create-violence-category", categoryWrite, upload, h.CreateViolenceCategory)
+	adminGroup.Put("/edit-violence-category/:id", categoryWrite, upload, h.UpdateViolenceCategory)
+	adminGroup.Delete("/delete-violence-category/:id", categoryWrite, h.DeleteViolenceCategory)
+
+	contentRead := middleware.RequirePermission(auth.PermContentRead)
+	contentWrite := middleware.RequirePermission(auth.PermContentWrite)
+	adminGroup.Get("/contents", contentRead, h.GetAllContents)
+	adminGroup.Get("/detail-content/:id", contentRead, h.GetContentByID)
+	adminGroup.Post("/create-content", contentWrite, upload, h.CreateContent)
+	adminGroup.Put("/edit-content/:id", contentWrite, upload, h.UpdateContent)
+	adminGroup.Delete("/delete-content/:id", contentWrite, h.DeleteContent)
+
+	eventRead := middleware.RequirePermission(auth.PermEventRead)
+	eventWrite := middleware.RequirePermission(auth.PermEventWrite)
+	adminGroup.Get("/event", eventRead, h.GetAllEvent)
+	adminGroup.Get("/detail-event/:id", eventRead, h.GetEventByID)
+	adminGroup.Post("/create-event", eventWrite, upload, h.CreateEvent)
+	adminGroup.Put("/edit-event/:id", eventWrite, upload, h.UpdateEvent)
+	adminGroup.Delete("/delete-event/:id", eventWrite, h.DeleteEvent)
+
+	janjiTemuRead := middleware.RequirePermission(auth.PermJanjiTemuRead)
+	janjiTemuWrite := middleware.RequirePermission(auth.PermJanjiTemuWrite)
+	adminGroup.Get("/janjitemus", janjiTemuRead, h.AdminGetAllJanjiTemu)
+	adminGroup.Get("/detail-janjitemu/:id", janjiTemuRead, h.AdminJanjiTemuByID)
+	adminGroup.Put("/approve-janjitemu/:id", janjiTemuWrite, h.AdminApproveJanjiTemu)
+	adminGroup.Put("/cancel-janjitemu/:id", janjiTemuWrite, h.AdminCancelJanjiTemu)
+	adminGroup.Get("/ics-janjitemu/:id", janjiTemuRead, h.AdminUnduhJanjiTemuICal)
+	adminGroup.Get("/tautan-janjitemu/:id", janjiTemuRead, h.AdminTautanJanjiTemu)
+
+	catatanRead := middleware.RequirePermission(auth.PermCatatanKonsultasiRead)
+	catatanWrite := middleware.RequirePermission(auth.PermCatatanKonsultasiWrite)
+	adminGroup.Get("/catatan-janjitemu/:id", catatanRead, h.AdminGetCatatanKonsultasi)
+	adminGroup.Put("/selesai-janjitemu/:id", catatanWrite, h.AdminSelesaikanJanjiTemu)
+	adminGroup.Get("/riwayat-jadwal-ulang-janjitemu/:id", janjiTemuRead, h.AdminRiwayatJadwalUlang)
+	adminGroup.Post("/jadwal-ulang-janjitemu/:id", janjiTemuWrite, h.AdminUsulkanJadwalUlang)
+	adminGroup.Put("/terima-jadwal-ulang-janjitemu/:id", janjiTemuWrite, h.AdminTerimaJadwalUlang)
+	adminGroup.Put("/tolak-jadwal-ulang-janjitemu/:id", janjiTemuWrite, h.AdminTolakJadwalUlang)
+
+	penilaianRead := middleware.RequirePermission(auth.PermPenilaianRead)
+	adminGroup.Get("/statistik-penilaian", penilaianRead, h.AdminStatistikPenilaian)
+
+	adminGroup.Get("/jadwal-konselor/:konselor_id", janjiTemuRead, h.AdminGetJadwalKonselor)
+	adminGroup.Post("/jadwal-konselor/:konselor_id", janjiTemuWrite, h.AdminCreateJadwalKonselor)
+	adminGroup.Delete("/jadwal-konselor/:konselor_id/:id", janjiTemuWrite, h.AdminDeleteJadwalKonselor)
+	adminGroup.Post("/jadwal-konselor/:konselor_id/pengecualian", janjiTemuWrite, h.AdminCreatePengecualianJadwal)
+	adminGroup.Delete("/jadwal-konselor/:konselor_id/pengecualian/:id", janjiTemuWrite, h.AdminDeletePengecualianJadwal)
+
+	userManage := middleware.RequirePermission(auth.PermUserManage)
+	adminGroup.Get("/users", userManage, h.AdminGetUsers)
+	adminGroup.Get("/users/:id", userManage, h.AdminGetUserByID)
+	adminGroup.Post("/users", userManage, h.AdminCreateUser)
+	adminGroup.Put("/users/:id", userManage, h.AdminUpdateUser)
+	adminGroup.Delete("/users/:id", userManage, h.AdminDeleteUser)
+	adminGroup.Put("/users/:id/suspend", userManage, h.AdminSuspendUser)
+	adminGroup.Put("/users/:id/reactivate", userManage, h.AdminReactivateUser)
+	adminGroup.Put("/users/:id/reset-password", userManage, h.AdminResetUserPassword)
+	adminGroup.Put("/users/:id/unlock", userManage, h.AdminUnlockUser)
+	adminGroup.Get("/locked-accounts", userManage, h.AdminGetLockedAccounts)
+	adminGroup.Get("/login-attempts", userManage, h.AdminGetLoginAttempts)
+
+	roleManage := middleware.RequirePermission(auth.PermRoleManage)
+	adminGroup.Get("/roles", roleManage, h.GetRoles)
+	adminGroup.Put("/users/:id/role", roleManage, h.UpdateUserRole)
 
 }
 
 /*========= ||  Endpoint yang hanya bisa diakses oleh masyarakat || ====================*/
-func SetMasyarakatRoutes(app *fiber.App) {
+func SetMasyarakatRoutes(app *fiber.App, cfg *config.Config, h *handlers.Handler, mw *middleware.Auth, rl *middleware.RateLimiter) {
 	masyarakatGroup := app.Group("/api/masyarakat")
-	masyarakatGroup.Use(middleware.MasyarakatMiddleware)
-
-	masyarakatGroup.Get("/profile", handlers.GetUserProfile)
-	masyarakatGroup.Put("/edit-profile", handlers.UpdateUserProfile)
-	masyarakatGroup.Put("/change-password", handlers.ChangePassword)
-
-	masyarakatGroup.Get("/kategori-kekerasan", handlers.GetAllViolenceCategories)
-	masyarakatGroup.Get("/kategori-kekerasan/:id", handlers.GetViolenceCategoryByID)
-
-	masyarakatGroup.Get("/laporans", handlers.GetUserReports)
-	masyarakatGroup.Post("/buat-laporan", handlers.CreateLaporan)
-	masyarakatGroup.Put("/edit-laporan/:no_registrasi", handlers.EditLaporan)
-	masyarakatGroup.Get("/detail-laporan/:no_registrasi", handlers.GetReportByNoRegistrasi)
-	masyarakatGroup.Put("batalkan-laporan/:no_registrasi", handlers.BatalkanLaporan)
-	masyarakatGroup.Put("laporan-selesai/:no_registrasi", handlers.SelesaikanLaporan)
-
-	masyarakatGroup.Post("/create-korban-kekerasan", handlers.CreateKorban)
-	masyarakatGroup.Put("/edit-korban-kekerasan/:id", handlers.UpdateKorban)
-
-	masyarakatGroup.Post("/create-pelaku-kekerasan", handlers.CreateKorban)
-	masyarakatGroup.Put("/edit-pelaku-kekerasan/:id", handlers.UpdateKorban)
-
-	masyarakatGroup.Post("/create-korban-kekerasan", handlers.CreateKorban)
-	masyarakatGroup.Put("/edit-korban-kekerasan/:id", handlers.UpdateKorban)
-
-	masyarakatGroup.Get("/janjitemus", handlers.GetUserJanjiTemus)
-	masyarakatGroup.Get("/detail-janjitemu/:id", handlers.GetJanjiTemuByID)
-	masyarakatGroup.Post("/create-janjitemu", handlers.MasyarakatCreateJanjiTemu)
-	masyarakatGroup.Put("/edit-janjitemu/:id", handlers.MasyarakatEditJanjiTemu)
-	masyarakatGroup.Put("/batal-janjitemu/:id", handlers.MasyarakatCancelJanjiTemu)
-
-	masyarakatGroup.Get("/content", handlers.GetAllContents)
-	masyarakatGroup.Get("/detail-content/:id", handlers.GetContentByID)
+	masyarakatGroup.Use(mw.MasyarakatMiddleware)
+	masyarakatGroup.Use(rl.RateLimit(writeRateLimit(cfg)))
+	upload := rl.RateLimit(uploadRateLimit(cfg))
+
+	masyarakatGroup.Get("/profile", h.GetUserProfile)
+	masyarakatGroup.Put("/edit-profile", upload, h.UpdateUserProfile)
+	masyarakatGroup.Put("/change-password", h.ChangePassword)
+
+	masyarakatGroup.Post("/kalender/token", h.BuatTokenKalender)
+	masyarakatGroup.Delete("/kalender/token", h.HapusTokenKalender)
+
+	masyarakatGroup.Get("/notifikasi", h.GetNotifications)
+	masyarakatGroup.Get("/notifikasi/belum-dibaca", h.GetUnreadNotificationCount)
+	masyarakatGroup.Put("/baca-notifikasi/:id", h.ReadNotification)
+	masyarakatGroup.Put("/baca-semua-notifikasi", h.ReadAllNotifications)
+
+	masyarakatGroup.Get("/kategori-kekerasan", h.GetAllViolenceCategories)
+	masyarakatGroup.Get("/kategori-kekerasan/:id", h.GetViolenceCategoryByID)
+
+	masyarakatGroup.Get("/laporans", h.GetUserReports)
+	masyarakatGroup.Post("/buat-laporan", middleware.RequireVerifiedAccount, upload, h.CreateLaporan)
+	masyarakatGroup.Put("/edit-laporan/:no_registrasi", upload, h.EditLaporan)
+	masyarakatGroup.Get("/detail-laporan/:no_registrasi", h.GetReportByNoRegistrasi)
+	masyarakatGroup.Put("batalkan-laporan/:no_registrasi", h.BatalkanLaporan)
+	masyarakatGroup.Put("laporan-selesai/:no_registrasi", h.SelesaikanLaporan)
+
+	masyarakatGroup.Post("/create-korban-kekerasan", upload, h.CreateKorban)
+	masyarakatGroup.Put("/edit-korban-kekerasan/:id", upload, h.UpdateKorban)
+
+	masyarakatGroup.Post("/create-pelaku-kekerasan", upload, h.CreateKorban)
+	masyarakatGroup.Put("/edit-pelaku-kekerasan/:id", upload, h.UpdateKorban)
+
+	masyarakatGroup.Post("/create-korban-kekerasan", upload, h.CreateKorban)
+	masyarakatGroup.Put("/edit-korban-kekerasan/:id", upload, h.UpdateKorban)
+
+	masyarakatGroup.Get("/janjitemus", h.GetUserJanjiTemus)
+	masyarakatGroup.Get("/detail-janjitemu/:id", h.GetJanjiTemuByID)
+	masyarakatGroup.Post("/create-janjitemu", h.MasyarakatCreateJanjiTemu)
+	masyarakatGroup.Post("/booking-janjitemu", h.MasyarakatBookingJanjiTemu)
+	masyarakatGroup.Put("/edit-janjitemu/:id", h.MasyarakatEditJanjiTemu)
+	masyarakatGroup.Put("/batal-janjitemu/:id", h.MasyarakatCancelJanjiTemu)
+	masyarakatGroup.Get("/ics-janjitemu/:id", h.MasyarakatUnduhJanjiTemuICal)
+	masyarakatGroup.Get("/tautan-janjitemu/:id", h.MasyarakatTautanJanjiTemu)
+	masyarakatGroup.Get("/riwayat-jadwal-ulang-janjitemu/:id", h.MasyarakatRiwayatJadwalUlang)
+	masyarakatGroup.Post("/jadwal-ulang-janjitemu/:id", h.MasyarakatUsulkanJadwalUlang)
+	masyarakatGroup.Put("/terima-jadwal-ulang-janjitemu/:id", h.MasyarakatTerimaJadwalUlang)
+	masyarakatGroup.Put("/tolak-jadwal-ulang-janjitemu/:id", h.MasyarakatTolakJadwalUlang)
+	masyarakatGroup.Get("/penilaian-janjitemu/:id", h.MasyarakatGetPenilaianJanjiTemu)
+	masyarakatGroup.Post("/penilaian-janjitemu/:id", h.MasyarakatNilaiJanjiTemu)
+	masyarakatGroup.Get("/penilaian-laporan/:no_registrasi", h.MasyarakatGetPenilaianLaporan)
+	masyarakatGroup.Post("/penilaian-laporan/:no_registrasi", h.MasyarakatNilaiLaporan)
+
+	masyarakatGroup.Get("/content", h.GetAllContents)
+	masyarakatGroup.Get("/detail-content/:id", h.GetContentByID)
 }
 
 /*========= ||  Endpoint bisa di akses tanpa login || ====================*/
-func RoutesWithOutLogin(app *fiber.App) {
+func RoutesWithOutLogin(app *fiber.App, cfg *config.Config, h *handlers.Handler, rl *middleware.RateLimiter) {
+	public := rl.RateLimit(publicRateLimit(cfg))
+
 	app.Get("/", func(c *fiber.Ctx) error {
 		fmt.Println("succes")
 		return c.Status(200).SendString("oke manta")
 	})
 
-	app.Get("/api/emergency-contact", handlers.EmergencyContact)
-	app.Get("/api/publik-content", handlers.GetAllContents)
-	app.Get("/api/detail-content/:id", handlers.GetContentByID)
-	app.Get("api/publik-event", handlers.GetAllEvent)
-	app.Get("/api/detail-event/:id", handlers.GetEventByID)
-	app.Get("/hello", handlers.HelloMasyarakat)
-	app.Get("/api/publik/kategori-kekerasan", handlers.GetAllViolenceCategories)
-	app.Get("/api/publik/detail-kategori-kekerasan/:id", handlers.GetViolenceCategoryByID)
+	app.Get("/api/emergency-contact", public, h.EmergencyContact)
+	app.Get("/api/publik-content", public, h.GetAllContents)
+	app.Get("/api/detail-content/:id", public, h.GetContentByID)
+	app.Get("api/publik-event", public, h.GetAllEvent)
+	app.Get("/api/detail-event/:id", public, h.GetEventByID)
+	app.Get("/api/ics-event/:id", public, h.UnduhEventICal)
+	app.Get("/api/kalender/:token.ics", public, h.FeedKalender)
+	app.Get("/hello", public, handlers.HelloMasyarakat)
+	app.Get("/api/publik/kategori-kekerasan", public, h.GetAllViolenceCategories)
+	app.Get("/api/publik/detail-kategori-kekerasan/:id", public, h.GetViolenceCategoryByID)
+	app.Get("/api/publik/slot-konselor", public, h.GetSlotKonselor)
 }
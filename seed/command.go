@@ -0,0 +1,43 @@
+package seed
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// RunCommand menjalankan subcommand "seed". Argumen berupa nama set
+// (default, demo) atau path file .json; tanpa argumen set default dipakai.
+// Di luar development, data yang membuat akun staff dengan password tertulis
+// ditolak sebelum apa pun disimpan.
+//
+//	seed
+//	seed default demo
+//	seed ./data-tambahan.json
+func RunCommand(db *gorm.DB, args []string, out io.Writer, development bool) error {
+	if len(args) == 0 {
+		args = []string{"default"}
+	}
+	for _, arg := range args {
+		var data Data
+		var err error
+		if strings.HasSuffix(arg, ".json") {
+			data, err = LoadFile(arg)
+		} else {
+			data, err = LoadSet(arg)
+		}
+		if err != nil {
+			return err
+		}
+		if staff := data.StaffWithPassword(); len(staff) > 0 && !development {
+			return fmt.Errorf("seed: %s creates staff accounts with fixed passwords (%s), only allowed when APP_ENV=development", arg, strings.Join(staff, ", "))
+		}
+		if err := Apply(db, data, out); err != nil {
+			return fmt.Errorf("seed: %s failed: %w", arg, err)
+		}
+		fmt.Fprintf(out, "Seeded %s\n", arg)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package seed
+
+import (
+	"backend-pedika-fiber/auth"
+	"backend-pedika-fiber/models"
+	"bytes"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// File seed dikelompokkan per set di folder data/<set>:
+//
+//	default  data referensi yang wajib ada (admin awal, kategori, kontak darurat)
+//	demo     contoh akun, konten, dan event untuk development
+//
+// Fixture untuk test ada di integration/testdata dan tidak ikut di-embed ke
+// binary production.
+//
+//go:embed data
+var embedded embed.FS
+
+type Data struct {
+	Users              []UserSeed             `json:"users"`
+	ViolenceCategories []ViolenceCategorySeed `json:"violence_categories"`
+	EmergencyContact   *EmergencyContactSeed  `json:"emergency_contact"`
+	Contents           []ContentSeed          `json:"contents"`
+	Events             []EventSeed            `json:"events"`
+}
+
+// UserSeed tanpa password akan dibuatkan password acak yang dicetak sekali
+// ke output, supaya password admin production tidak tersimpan di repository.
+// Akun staff dengan password tertulis hanya boleh di-seed di development,
+// lihat StaffWithPassword.
+type UserSeed struct {
+	FullName    string `json:"full_name"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+	Role        string `json:"role"`
+	Password    string `json:"password"`
+}
+
+type ViolenceCategorySeed struct {
+	CategoryName string `json:"category_name"`
+	Image        string `json:"image"`
+}
+
+type EmergencyContactSeed struct {
+	Phone string `json:"phone"`
+}
+
+type ContentSeed struct {
+	Judul        string `json:"judul"`
+	IsiContent   string `json:"isi_content"`
+	ImageContent string `json:"image_content"`
+	CategoryName string `json:"category_name"`
+}
+
+type EventSeed struct {
+	NamaEvent          string    `json:"nama_event"`
+	DeskripsiEvent     string    `json:"deskripsi_event"`
+	ThumbnailEvent     string    `json:"thumbnail_event"`
+	TanggalPelaksanaan time.Time `json:"tanggal_pelaksanaan"`
+}
+
+// LoadSet membaca semua file .json di data/<set> dan menggabungkannya.
+func LoadSet(set string) (Data, error) {
+	dir := path.Join("data", set)
+	entries, err := fs.ReadDir(embedded, dir)
+	if err != nil {
+		return Data{}, fmt.Errorf("seed: unknown set %q", set)
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var data Data
+	for _, name := range names {
+		raw, err := embedded.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return Data{}, err
+		}
+		if err := data.merge(raw, name); err != nil {
+			return Data{}, err
+		}
+	}
+	return data, nil
+}
+
+// LoadFile membaca satu file seed JSON dari disk.
+func LoadFile(filename string) (Data, error) {
+	raw, err := os.ReadFile(filename)
+	if err != nil {
+		return Data{}, fmt.Errorf("seed: %w", err)
+	}
+	var data Data
+	if err := data.merge(raw, filename); err != nil {
+		return Data{}, err
+	}
+	return data, nil
+}
+
+// StaffWithPassword mengembalikan email akun staff yang password-nya tertulis
+// di file seed. Password seperti itu ada di repository dan binary, sehingga
+// akunnya tidak boleh dibuat di luar development.
+func (d Data) StaffWithPassword() []string {
+	var emails []string
+	for _, u := range d.Users {
+		if u.Password != "" && auth.IsStaffRole(u.Role) {
+			emails = append(emails, u.Email)
+		}
+	}
+	return emails
+}
+
+func (d *Data) merge(raw []byte, name string) error {
+	var part Data
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&part); err != nil {
+		return fmt.Errorf("seed: invalid %s: %w", name, err)
+	}
+	d.Users = append(d.Users, part.Users...)
+	d.ViolenceCategories = append(d.ViolenceCategories, part.ViolenceCategories...)
+	if part.EmergencyContact != nil {
+		d.EmergencyContact = part.EmergencyContact
+	}
+	d.Contents = append(d.Contents, part.Contents...)
+	d.Events = append(d.Events, part.Events...)
+	return nil
+}
+
+// Apply menyimpan data ke database. Aman dijalankan berulang kali: baris yang
+// sudah ada (berdasarkan email, nama kategori, judul, atau nama event) tidak
+// diubah, dan kontak darurat hanya dibuat jika belum ada sama sekali.
+func Apply(db *gorm.DB, data Data, out io.Writer) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, u := range data.Users {
+			if err := seedUser(tx, u, out); err != nil {
+				return err
+			}
+		}
+		for _, c := range data.ViolenceCategories {
+			if err := seedViolenceCategory(tx, c); err != nil {
+				return err
+			}
+		}
+		if data.EmergencyContact != nil {
+			if err := seedEmergencyContact(tx, *data.EmergencyContact); err != nil {
+				return err
+			}
+		}
+		for _, c := range data.Contents {
+			if err := seedContent(tx, c); err != nil {
+				return err
+			}
+		}
+		for _, e := range data.Events {
+			if err := seedEvent(tx, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func seedUser(tx *gorm.DB, u UserSeed, out io.Writer) error {
+	if u.Email == "" || u.Username == "" || u.PhoneNumber == "" {
+		return fmt.Errorf("seed: user %q requires email, username and phone_number", u.FullName)
+	}
+	if u.Role == "" {
+		u.Role = models.RoleMasyarakat
+	}
+	if !auth.IsValidRole(u.Role) {
+		return fmt.Errorf("seed: user %s has unknown role %q", u.Email, u.Role)
+	}
+
+	var existing models.User
+	err := tx.Where("email = ?", u.Email).First(&existing).Error
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	password := u.Password
+	if password == "" {
+		password, err = randomPassword()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Created %s %s with password %s (simpan sekarang, tidak akan ditampilkan lagi)\n", u.Role, u.Email, password)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user := models.User{
+		FullName:        u.FullName,
+		Username:        u.Username,
+		Email:           u.Email,
+		PhoneNumber:     u.PhoneNumber,
+		Role:            u.Role,
+		Password:        string(hashed),
+		EmailVerifiedAt: &now,
+		PhoneVerifiedAt: &now,
+	}
+	return tx.Omit("TanggalLahir").Create(&user).Error
+}
+
+func seedViolenceCategory(tx *gorm.DB, c ViolenceCategorySeed) error {
+	category := models.ViolenceCategory{CategoryName: c.CategoryName}
+	return tx.Where(models.ViolenceCategory{CategoryName: c.CategoryName}).
+		Attrs(models.ViolenceCategory{Image: c.Image}).
+		FirstOrCreate(&category).Error
+}
+
+func seedEmergencyContact(tx *gorm.DB, c EmergencyContactSeed) error {
+	var count int64
+	if err := tx.Model(&models.EmergencyContact{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return tx.Create(&models.EmergencyContact{Phone: c.Phone}).Error
+}
+
+func seedContent(tx *gorm.DB, c ContentSeed) error {
+	var category models.ViolenceCategory
+	if err := tx.Where("category_name = ?", c.CategoryName).First(&category).Error; err != nil {
+		return fmt.Errorf("seed: content %q: category %q not found", c.Judul, c.CategoryName)
+	}
+	content := models.Content{Judul: c.Judul}
+	return tx.Where(models.Content{Judul: c.Judul}).
+		Attrs(models.Content{
+			IsiContent:         c.IsiContent,
+			ImageContent:       c.ImageContent,
+			ViolenceCategoryID: uint(category.ID),
+		}).
+		FirstOrCreate(&content).Error
+}
+
+func seedEvent(tx *gorm.DB, e EventSeed) error {
+	event := models.Event{NamaEvent: e.NamaEvent}
+	return tx.Where(models.Event{NamaEvent: e.NamaEvent}).
+		Attrs(models.Event{
+			DeskripsiEvent:     e.DeskripsiEvent,
+			ThumbnailEvent:     e.ThumbnailEvent,
+			TanggalPelaksanaan: e.TanggalPelaksanaan,
+		}).
+		FirstOrCreate(&event).Error
+}
+
+func randomPassword() (string, error) {
+	buf := make([]byte, 9)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,128 @@
+package sms
+
+import (
+	"backend-pedika-fiber/config"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Message struct {
+	To   string
+	Body string
+}
+
+// Sender adalah abstraksi pengiriman SMS. Provider SMS dibuat lewat
+// NewSender saat aplikasi start, dan FakeSender dipakai saat testing.
+type Sender interface {
+	Send(msg Message) error
+}
+
+// NewSender memilih implementasi Sender sesuai SMS_DRIVER. Config sudah
+// memastikan driver "log" hanya dipakai di development.
+func NewSender(cfg config.SMSConfig) Sender {
+	if cfg.Driver == "twilio" {
+		return NewTwilioSender(cfg)
+	}
+	return LogSender{}
+}
+
+// TwilioSender mengirim SMS lewat REST API Twilio.
+type TwilioSender struct {
+	cfg     config.SMSConfig
+	baseURL string
+	client  *http.Client
+}
+
+func NewTwilioSender(cfg config.SMSConfig) TwilioSender {
+	return TwilioSender{
+		cfg:     cfg,
+		baseURL: "https://api.twilio.com",
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s TwilioSender) Send(msg Message) error {
+	form := url.Values{}
+	form.Set("To", NormalizeNumber(msg.To))
+	form.Set("From", s.cfg.From)
+	form.Set("Body", msg.Body)
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.baseURL, s.cfg.AccountSID)
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.cfg.AccountSID, s.cfg.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		// Isi respons bisa memuat ulang nomor dan pesan, jadi hanya status
+		// yang dikembalikan.
+		io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("sms: twilio responded %s", resp.Status)
+	}
+	return nil
+}
+
+// NormalizeNumber mengubah nomor lokal Indonesia (08xx atau 628xx) ke format
+// E.164 (+628xx) yang dibutuhkan provider. Nomor yang sudah diawali + tidak
+// diubah.
+func NormalizeNumber(number string) string {
+	number = strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(number))
+	switch {
+	case strings.HasPrefix(number, "+"):
+		return number
+	case strings.HasPrefix(number, "0"):
+		return "+62" + number[1:]
+	case strings.HasPrefix(number, "62"):
+		return "+" + number
+	}
+	return number
+}
+
+// LogSender hanya mencatat bahwa ada SMS terkirim, tanpa isi pesan dan
+// tanpa nomor lengkap, karena isi SMS bisa berupa kode OTP. Hanya boleh
+// dipakai di development.
+type LogSender struct{}
+
+func (LogSender) Send(msg Message) error {
+	log.Printf("SMS to %s (%d characters, body not logged)\n", maskNumber(msg.To), len(msg.Body))
+	return nil
+}
+
+func maskNumber(number string) string {
+	if len(number) <= 4 {
+		return "****"
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
+
+// FakeSender menyimpan SMS di memori untuk test.
+type FakeSender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (f *FakeSender) Send(msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, msg)
+	return nil
+}
+
+func (f *FakeSender) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.messages...)
+}
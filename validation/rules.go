@@ -0,0 +1,224 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format tanggal yang dipakai form di aplikasi mobile dan web. DateTime juga
+// menerima RFC 3339 untuk client yang mengirim JSON dengan zona waktu.
+const (
+	DateTimeLayout = "2006-01-02T15:04:05"
+	DateLayout     = "2006-01-02"
+	TimeLayout     = "15:04"
+)
+
+var (
+	digitsPattern = regexp.MustCompile(`^[0-9]+$`)
+	nikPattern    = regexp.MustCompile(`^[0-9]{16}$`)
+	// Nomor seluler Indonesia: 08xx, 628xx, atau +628xx dengan total 10-15 digit.
+	phonePattern = regexp.MustCompile(`^(\+62|62|0)8[1-9][0-9]{6,11}$`)
+)
+
+// ParseDateTime membaca nilai field ber-aturan datetime. Jika layouts kosong,
+// dipakai DateTimeLayout dan RFC 3339; jika diisi, layout dicoba berurutan
+// seperti pada aturan datetime=layout1|layout2.
+func ParseDateTime(value string, layouts ...string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = []string{DateTimeLayout, time.RFC3339}
+	}
+	value = strings.TrimSpace(value)
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// ParseDate membaca nilai field ber-aturan date.
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse(DateLayout, strings.TrimSpace(value))
+}
+
+// ParseTime membaca nilai field ber-aturan time, yaitu jam HH:mm tanpa
+// tanggal.
+func ParseTime(value string) (time.Time, error) {
+	return time.Parse(TimeLayout, strings.TrimSpace(value))
+}
+
+func parseRule(spec string) rule {
+	name, param := strings.TrimSpace(spec), ""
+	if i := strings.Index(name, "="); i >= 0 {
+		name, param = name[:i], name[i+1:]
+	}
+	r := rule{name: name, param: param}
+
+	switch name {
+	case "required":
+		r.check = func(f field, _ map[string]field) bool { return f.value != "" }
+	case "min":
+		n := mustAtoi(name, param)
+		r.check = func(f field, _ map[string]field) bool { return len([]rune(f.value)) >= n }
+	case "max":
+		n := mustAtoi(name, param)
+		r.check = func(f field, _ map[string]field) bool { return len([]rune(f.value)) <= n }
+	case "len":
+		n := mustAtoi(name, param)
+		r.check = func(f field, _ map[string]field) bool { return len([]rune(f.value)) == n }
+	case "digits":
+		r.check = matches(digitsPattern)
+	case "nik":
+		r.check = matches(nikPattern)
+	case "phone":
+		r.check = matches(phonePattern)
+	case "email":
+		r.check = func(f field, _ map[string]field) bool {
+			addr, err := mail.ParseAddress(f.value)
+			return err == nil && addr.Address == f.value
+		}
+	case "oneof":
+		options := strings.Split(param, "|")
+		r.check = func(f field, _ map[string]field) bool {
+			for _, option := range options {
+				if f.value == option {
+					return true
+				}
+			}
+			return false
+		}
+	case "int":
+		lo, hi, bounded := parseRange(param)
+		r.check = func(f field, _ map[string]field) bool {
+			n, err := strconv.Atoi(f.value)
+			if err != nil {
+				return false
+			}
+			return !bounded || (n >= lo && n <= hi)
+		}
+	case "datetime":
+		var layouts []string
+		if param != "" {
+			layouts = strings.Split(param, "|")
+		}
+		r.check = func(f field, _ map[string]field) bool {
+			_, err := ParseDateTime(f.value, layouts...)
+			return err == nil
+		}
+	case "date":
+		r.check = func(f field, _ map[string]field) bool {
+			_, err := ParseDate(f.value)
+			return err == nil
+		}
+	case "time":
+		r.check = func(f field, _ map[string]field) bool {
+			_, err := ParseTime(f.value)
+			return err == nil
+		}
+	case "past":
+		r.check = func(f field, _ map[string]field) bool {
+			t, err := parseAny(f.value)
+			return err != nil || !t.After(time.Now())
+		}
+	case "future":
+		r.check = func(f field, _ map[string]field) bool {
+			t, err := parseAny(f.value)
+			return err != nil || t.After(time.Now())
+		}
+	case "after":
+		r.check = func(f field, all map[string]field) bool {
+			other, ok := all[param]
+			if !ok || other.value == "" {
+				return true
+			}
+			start, err := parseAny(other.value)
+			if err != nil {
+				// Kesalahan format field pembanding sudah dilaporkan sendiri.
+				return true
+			}
+			end, err := parseAny(f.value)
+			return err != nil || end.After(start)
+		}
+	case "eqfield":
+		r.check = func(f field, all map[string]field) bool { return f.value == all[param].value }
+	default:
+		panic(fmt.Sprintf("validation: aturan %q tidak dikenal", name))
+	}
+	return r
+}
+
+func matches(pattern *regexp.Regexp) func(field, map[string]field) bool {
+	return func(f field, _ map[string]field) bool { return pattern.MatchString(f.value) }
+}
+
+// parseAny dipakai aturan perbandingan waktu yang bisa diterapkan pada field
+// datetime, date, maupun time.
+func parseAny(value string) (time.Time, error) {
+	if t, err := ParseDateTime(value); err == nil {
+		return t, nil
+	}
+	if t, err := ParseDate(value); err == nil {
+		return t, nil
+	}
+	return ParseTime(value)
+}
+
+func parseRange(param string) (lo, hi int, bounded bool) {
+	if param == "" {
+		return 0, 0, false
+	}
+	parts := strings.SplitN(param, ":", 2)
+	if len(parts) != 2 {
+		panic(fmt.Sprintf("validation: rentang %q harus berbentuk min:max", param))
+	}
+	return mustAtoi("int", parts[0]), mustAtoi("int", parts[1]), true
+}
+
+// Tag validate ditulis oleh programmer, jadi argumen yang salah adalah bug
+// dan langsung panic saat validasi pertama kali dijalankan.
+func mustAtoi(rule, param string) int {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		panic(fmt.Sprintf("validation: argumen aturan %s tidak valid: %q", rule, param))
+	}
+	return n
+}
+
+// layoutHint menerjemahkan layout Go ke format yang dikenal pengguna, misalnya
+// 2006-01-02T15:04:05 menjadi YYYY-MM-DDTHH:mm:ss.
+var layoutHint = strings.NewReplacer("2006", "YYYY", "01", "MM", "02", "DD", "15", "HH", "04", "mm", "05", "ss")
+
+// message mengembalikan key katalog i18n beserta argumennya untuk kesalahan
+// aturan rule pada field. Teks pesannya ada di package i18n.
+func message(rule, field, param string) (string, []interface{}) {
+	switch rule {
+	case "required", "digits", "nik", "phone", "email", "past", "future":
+		return "validation." + rule, []interface{}{field}
+	case "min", "max", "len", "after", "eqfield":
+		return "validation." + rule, []interface{}{field, param}
+	case "oneof":
+		return "validation.oneof", []interface{}{field, strings.ReplaceAll(param, "|", ", ")}
+	case "int":
+		if lo, hi, bounded := parseRange(param); bounded {
+			return "validation.int_range", []interface{}{field, lo, hi}
+		}
+		return "validation.int", []interface{}{field}
+	case "datetime":
+		layout := DateTimeLayout
+		if param != "" {
+			layout = strings.Split(param, "|")[0]
+		}
+		return "validation.datetime", []interface{}{field, layoutHint.Replace(layout)}
+	case "date":
+		return "validation.date", []interface{}{field, layoutHint.Replace(DateLayout)}
+	case "time":
+		return "validation.time", []interface{}{field, layoutHint.Replace(TimeLayout)}
+	}
+	return "validation.invalid", []interface{}{field}
+}
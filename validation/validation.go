@@ -0,0 +1,133 @@
+// Package validation memeriksa request DTO berdasarkan aturan yang ditulis di
+// tag `validate`, misalnya:
+//
+//	NIK  string `form:"nik_korban" validate:"required,nik"`
+//	Usia string `form:"usia_korban" validate:"int=0:150"`
+//
+// Aturan dipisah koma dan argumennya ditulis setelah tanda sama dengan. Semua
+// aturan selain required hanya dijalankan jika field diisi. Nama field di
+// pesan error diambil dari tag form atau json, sama dengan nama di request.
+package validation
+
+import (
+	"backend-pedika-fiber/helper"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Bind membaca body request (JSON, form, atau multipart) ke dst lalu
+// memvalidasinya. Body yang tidak bisa dibaca menghasilkan 400, sedangkan
+// field yang tidak lolos aturan dikembalikan sekaligus sebagai 422. Body
+// kosong tetap divalidasi supaya semua field wajib ikut dilaporkan.
+func Bind(c *fiber.Ctx, dst interface{}) error {
+	if len(c.Body()) == 0 {
+		return Validate(dst)
+	}
+	if err := c.BodyParser(dst); err != nil {
+		return helper.BadRequest("common.invalid_request_body")
+	}
+	return Validate(dst)
+}
+
+// Validate menjalankan aturan validasi pada struct v dan mengembalikan
+// helper.ValidationFailed berisi semua field yang salah, atau nil.
+func Validate(v interface{}) error {
+	if fields := Struct(v); len(fields) > 0 {
+		return helper.ValidationFailed(fields...)
+	}
+	return nil
+}
+
+// Struct mengembalikan semua kesalahan validasi pada v, diurutkan berdasarkan
+// nama field. v harus berupa struct atau pointer ke struct.
+func Struct(v interface{}) []helper.FieldError {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := collectFields(value)
+	var errs []helper.FieldError
+	for _, f := range fields {
+		for _, r := range f.rules {
+			if f.value == "" && r.name != "required" {
+				continue
+			}
+			if !r.check(f, fields) {
+				key, args := message(r.name, f.name, r.param)
+				errs = append(errs, helper.FieldError{
+					Field:   f.name,
+					Code:    r.name,
+					Message: key,
+					Args:    args,
+				})
+				// Satu kesalahan per field sudah cukup untuk ditampilkan ke user.
+				break
+			}
+		}
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+type field struct {
+	name  string
+	value string
+	rules []rule
+}
+
+type rule struct {
+	name  string
+	param string
+	check func(f field, all map[string]field) bool
+}
+
+func collectFields(value reflect.Value) map[string]field {
+	fields := map[string]field{}
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag, ok := sf.Tag.Lookup("validate")
+		if !ok || tag == "" || tag == "-" {
+			continue
+		}
+		f := field{
+			name:  fieldName(sf),
+			value: strings.TrimSpace(stringValue(value.Field(i))),
+		}
+		for _, spec := range strings.Split(tag, ",") {
+			f.rules = append(f.rules, parseRule(spec))
+		}
+		fields[f.name] = f
+	}
+	return fields
+}
+
+func fieldName(sf reflect.StructField) string {
+	for _, key := range []string{"form", "json"} {
+		if name := strings.Split(sf.Tag.Get(key), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+func stringValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	if v.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}